@@ -0,0 +1,259 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boltcapture_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/boltcapture"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/packstream"
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+)
+
+func TestCapture(outer *testing.T) {
+	outer.Run("records and reads back connection traffic", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		writer, err := boltcapture.NewWriter(buffer)
+		AssertNoError(t, err)
+		client, server := net.Pipe()
+		captured := writer.Capture(client, "localhost:7687")
+		go func() {
+			request := make([]byte, 4)
+			_, _ = io.ReadFull(server, request)
+			_, _ = server.Write([]byte("pong"))
+		}()
+
+		_, err = captured.Write([]byte("ping"))
+		AssertNoError(t, err)
+		response := make([]byte, 4)
+		_, err = io.ReadFull(captured, response)
+		AssertNoError(t, err)
+		AssertNoError(t, writer.Close())
+
+		reader, err := boltcapture.NewReader(buffer)
+		AssertNoError(t, err)
+		var records []boltcapture.Record
+		for {
+			record, err := reader.Next()
+			if err == io.EOF {
+				break
+			}
+			AssertNoError(t, err)
+			records = append(records, record)
+		}
+		AssertLen(t, records, 2)
+		AssertDeepEquals(t, records[0].Connection, uint32(1))
+		AssertStringEqual(t, records[0].Server, "localhost:7687")
+		AssertDeepEquals(t, records[0].Direction, boltcapture.ClientToServer)
+		AssertDeepEquals(t, records[0].Data, []byte("ping"))
+		AssertDeepEquals(t, records[1].Direction, boltcapture.ServerToClient)
+		AssertDeepEquals(t, records[1].Data, []byte("pong"))
+		AssertFalse(t, records[1].Time.Before(records[0].Time))
+	})
+
+	outer.Run("writes records through without flushing", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		writer, err := boltcapture.NewWriter(buffer)
+		AssertNoError(t, err)
+		client, server := net.Pipe()
+		defer server.Close()
+		captured := writer.Capture(client, "localhost:7687")
+		go func() {
+			_, _ = io.ReadFull(server, make([]byte, 4))
+		}()
+
+		_, err = captured.Write([]byte("ping"))
+		AssertNoError(t, err)
+
+		reader, err := boltcapture.NewReader(bytes.NewReader(buffer.Bytes()))
+		AssertNoError(t, err)
+		record, err := reader.Next()
+		AssertNoError(t, err)
+		AssertDeepEquals(t, record.Data, []byte("ping"))
+	})
+
+	outer.Run("rejects other files", func(t *testing.T) {
+		_, err := boltcapture.NewReader(strings.NewReader("definitely not a capture"))
+
+		AssertErrorMessageContains(t, err, "not a Bolt capture")
+	})
+}
+
+func TestDecode(outer *testing.T) {
+	outer.Run("reassembles messages split across records and chunks", func(t *testing.T) {
+		run := message(t, func(packer *packstream.Packer) {
+			packer.StructHeader(0x10, 3)
+			packer.String("RETURN $x")
+			packer.MapHeader(1)
+			packer.String("x")
+			packer.Int(42)
+			packer.MapHeader(0)
+		})
+		body := run[2 : len(run)-2]
+		split := []byte{0x00, 0x00} // no-op chunk
+		split = append(split, 0x00, 0x03)
+		split = append(split, body[:3]...)
+		split = append(split, 0x00, byte(len(body)-3))
+		split = append(split, body[3:]...)
+		split = append(split, 0x00, 0x00)
+		handshake := []byte{0x60, 0x60, 0xb0, 0x17, 0, 0, 0, 5, 0, 0, 0, 4, 0, 0, 0, 3, 0, 0, 0, 0}
+
+		messages := boltcapture.Decode([]boltcapture.Record{
+			clientRecord(append(handshake, split[:6]...)),
+			clientRecord(split[6:]),
+		})
+
+		AssertLen(t, messages, 2)
+		AssertStringEqual(t, messages[0].Type, "<HANDSHAKE>")
+		AssertStringEqual(t, messages[0].Fields, "0X6060B017 0X00000005 0X00000004 0X00000003 0X00000000")
+		AssertStringEqual(t, messages[1].Type, "RUN")
+		AssertStringEqual(t, messages[1].Fields, `"RETURN $x" {"x": 42} {}`)
+	})
+
+	outer.Run("redacts credentials", func(t *testing.T) {
+		hello := message(t, func(packer *packstream.Packer) {
+			packer.StructHeader(0x6a, 1)
+			packer.StringMap(map[string]string{"scheme": "basic", "principal": "neo4j", "credentials": "s3cr3t"})
+		})
+
+		messages := boltcapture.Decode([]boltcapture.Record{clientRecord(make([]byte, 20)), clientRecord(hello)})
+
+		AssertLen(t, messages, 2)
+		AssertStringEqual(t, messages[1].Type, "LOGON")
+		AssertStringNotContain(t, messages[1].Fields, "s3cr3t")
+	})
+}
+
+func TestReplayServer(outer *testing.T) {
+	outer.Run("replays captured server responses to the driver", func(t *testing.T) {
+		ctx := context.Background()
+		server, err := boltcapture.NewReplayServer(serverCapture(t))
+		AssertNoError(t, err)
+		defer server.Close()
+		capture := &bytes.Buffer{}
+		writer, err := boltcapture.NewWriter(capture)
+		AssertNoError(t, err)
+		driver, err := neo4j.NewDriverWithContext("bolt://"+server.Address(), neo4j.NoAuth(), func(conf *config.Config) {
+			conf.BoltCapture = writer
+		})
+		AssertNoError(t, err)
+
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		result, err := session.Run(ctx, "RETURN 1 AS x", nil)
+		AssertNoError(t, err)
+		record, err := result.Single(ctx)
+		AssertNoError(t, err)
+		AssertNoError(t, session.Close(ctx))
+		AssertNoError(t, driver.Close(ctx))
+		AssertNoError(t, server.Close())
+		AssertNoError(t, writer.Close())
+
+		AssertDeepEquals(t, record.Values, []any{int64(1)})
+		AssertNoError(t, server.Err())
+		reader, err := boltcapture.NewReader(capture)
+		AssertNoError(t, err)
+		var records []boltcapture.Record
+		for record, err := reader.Next(); err != io.EOF; record, err = reader.Next() {
+			AssertNoError(t, err)
+			records = append(records, record)
+		}
+		var messageTypes []string
+		for _, message := range boltcapture.Decode(records) {
+			messageTypes = append(messageTypes, message.Direction.String()+" "+message.Type)
+		}
+		AssertDeepEquals(t, messageTypes[:8], []string{
+			"C <HANDSHAKE>",
+			"S <HANDSHAKE>",
+			"C HELLO",
+			"S SUCCESS",
+			"C RUN",
+			"C PULL",
+			"S SUCCESS",
+			"S RECORD",
+		})
+	})
+
+	outer.Run("reports connections missing from the capture", func(t *testing.T) {
+		server, err := boltcapture.NewReplayServer(nil)
+		AssertNoError(t, err)
+		conn, err := net.Dial("tcp", server.Address())
+		AssertNoError(t, err)
+		_, err = conn.Read(make([]byte, 1))
+		AssertError(t, err)
+		AssertNoError(t, server.Close())
+
+		AssertErrorMessageContains(t, server.Err(), "connection #1 is not part of the capture")
+	})
+}
+
+// serverCapture returns the capture of a Bolt 5.0 server running a single auto-commit query.
+// Only the number of client messages matters for replaying, hence their empty content.
+func serverCapture(t *testing.T) []boltcapture.Record {
+	emptyMessage := []byte{0x00, 0x01, 0xb0, 0x00, 0x00}
+	records := []boltcapture.Record{
+		clientRecord(make([]byte, 20)),
+		serverRecord([]byte{0x00, 0x00, 0x00, 0x05}),
+		clientRecord(emptyMessage),
+		serverRecord(message(t, func(packer *packstream.Packer) {
+			packer.StructHeader(0x70, 1)
+			packer.StringMap(map[string]string{"server": "Neo4j/5.0.0", "connection_id": "bolt-1"})
+		})),
+		clientRecord(emptyMessage),
+		clientRecord(emptyMessage),
+		serverRecord(message(t, func(packer *packstream.Packer) {
+			packer.StructHeader(0x70, 1)
+			packer.MapHeader(1)
+			packer.String("fields")
+			packer.Strings([]string{"x"})
+		})),
+		serverRecord(message(t, func(packer *packstream.Packer) {
+			packer.StructHeader(0x71, 1)
+			packer.Ints([]int{1})
+		})),
+		serverRecord(message(t, func(packer *packstream.Packer) {
+			packer.StructHeader(0x70, 1)
+			packer.StringMap(map[string]string{"type": "r", "db": "neo4j"})
+		})),
+	}
+	return records
+}
+
+func message(t *testing.T, pack func(*packstream.Packer)) []byte {
+	packer := &packstream.Packer{}
+	packer.Begin(nil)
+	pack(packer)
+	data, err := packer.End()
+	AssertNoError(t, err)
+	return append(append([]byte{byte(len(data) >> 8), byte(len(data))}, data...), 0x00, 0x00)
+}
+
+func clientRecord(data []byte) boltcapture.Record {
+	return boltcapture.Record{Time: time.Now(), Connection: 1, Direction: boltcapture.ClientToServer, Data: data}
+}
+
+func serverRecord(data []byte) boltcapture.Record {
+	return boltcapture.Record{Time: time.Now(), Connection: 1, Direction: boltcapture.ServerToClient, Data: data}
+}
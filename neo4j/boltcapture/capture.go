@@ -0,0 +1,303 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package boltcapture records the raw Bolt traffic of driver connections, decodes it into readable messages and
+// replays it as a fake server.
+//
+// Unlike log.BoltLogger, which renders messages as they are understood by the driver, captures hold the exact bytes
+// exchanged over the network (after TLS decryption), along with their timestamp and direction.
+// This makes it possible to reproduce client-side issues deterministically: capture the traffic of the affected
+// connections (see config.Config's BoltCapture), inspect it with Decode or the boltdecode command, and run the
+// driver against a ReplayServer fed with the capture.
+//
+// Captures contain everything sent over the wire, including credentials, queries, parameters and results.
+// They must be handled with the same care as the database itself.
+package boltcapture
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+)
+
+// Direction tells who sent the bytes of a Record
+type Direction byte
+
+const (
+	// ClientToServer marks bytes written by the driver
+	ClientToServer Direction = 'C'
+	// ServerToClient marks bytes received by the driver
+	ServerToClient Direction = 'S'
+)
+
+func (d Direction) String() string {
+	switch d {
+	case ClientToServer:
+		return "C"
+	case ServerToClient:
+		return "S"
+	default:
+		return fmt.Sprintf("Direction(%d)", byte(d))
+	}
+}
+
+// Record holds bytes written to or read from a connection, as a single network call returned them.
+// Records neither start nor end at message boundaries.
+type Record struct {
+	// Time is when the bytes were written or read
+	Time time.Time
+	// Connection identifies the connection within the capture, numbered from 1 in order of creation
+	Connection uint32
+	// Server is the address of the server the connection is established with
+	Server    string
+	Direction Direction
+	Data      []byte
+}
+
+// A capture starts with magic, followed by records made of:
+//   - the time in nanoseconds since the Unix epoch (8 bytes)
+//   - the connection number (4 bytes)
+//   - the direction (1 byte)
+//   - the length (2 bytes) and bytes of the server address
+//   - the length (4 bytes) and bytes of the data
+//
+// All integers are big-endian.
+var magic = []byte("BOLTCAP\x01")
+
+// Writer records connection traffic to an underlying io.Writer.
+// Every record is written through to the io.Writer as soon as it is captured, so that a capture taken by a process
+// that crashes or never closes its driver still holds the traffic up to that point.
+// Writer is safe for concurrent use.
+type Writer struct {
+	mut         sync.Mutex
+	out         *bufio.Writer
+	closer      io.Closer
+	connections uint32
+	err         error
+}
+
+// NewWriter returns a Writer recording to the given io.Writer.
+func NewWriter(w io.Writer) (*Writer, error) {
+	out := bufio.NewWriter(w)
+	if _, err := out.Write(magic); err != nil {
+		return nil, err
+	}
+	if err := out.Flush(); err != nil {
+		return nil, err
+	}
+	return &Writer{out: out}, nil
+}
+
+// Create creates (or truncates) the named file and returns a Writer recording to it.
+func Create(name string) (*Writer, error) {
+	file, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := NewWriter(file)
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	writer.closer = file
+	return writer, nil
+}
+
+// Capture returns a net.Conn recording all bytes written to and read from conn.
+// The driver calls Capture for every connection selected for capture, right after the connection (and its TLS
+// session, if any) has been established.
+func (w *Writer) Capture(conn net.Conn, server string) net.Conn {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+	w.connections++
+	return &capturingConn{Conn: conn, writer: w, id: w.connections, server: server}
+}
+
+// Flush writes any buffered record to the underlying io.Writer. Records are written through as they are captured,
+// Flush is therefore mostly useful to check whether recording failed.
+// It returns the first error that occurred while recording, if any.
+func (w *Writer) Flush() error {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+	if w.err == nil {
+		w.err = w.out.Flush()
+	}
+	return w.err
+}
+
+// Close flushes the capture and closes the underlying file, if the Writer was created with Create.
+// Connections captured afterwards are not recorded anymore.
+func (w *Writer) Close() error {
+	err := w.Flush()
+	w.mut.Lock()
+	defer w.mut.Unlock()
+	if w.err == nil {
+		w.err = errors.New("capture writer is closed")
+	}
+	if w.closer != nil {
+		err = errorutil.CombineAllErrors(err, w.closer.Close())
+		w.closer = nil
+	}
+	return err
+}
+
+// record appends a record to the capture. Once recording failed, no further record is written: capturing must
+// never disrupt the connection itself.
+func (w *Writer) record(record Record) {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+	if w.err != nil {
+		return
+	}
+	header := make([]byte, 8+4+1+2+len(record.Server)+4)
+	binary.BigEndian.PutUint64(header, uint64(record.Time.UnixNano()))
+	binary.BigEndian.PutUint32(header[8:], record.Connection)
+	header[12] = byte(record.Direction)
+	binary.BigEndian.PutUint16(header[13:], uint16(len(record.Server)))
+	copy(header[15:], record.Server)
+	binary.BigEndian.PutUint32(header[15+len(record.Server):], uint32(len(record.Data)))
+	if _, err := w.out.Write(header); err != nil {
+		w.err = err
+		return
+	}
+	if _, err := w.out.Write(record.Data); err != nil {
+		w.err = err
+		return
+	}
+	// the header and the data are buffered so that they reach the io.Writer in a single call
+	w.err = w.out.Flush()
+}
+
+type capturingConn struct {
+	net.Conn
+	writer *Writer
+	id     uint32
+	server string
+}
+
+func (c *capturingConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.record(ServerToClient, b[:n])
+	}
+	return n, err
+}
+
+func (c *capturingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.record(ClientToServer, b[:n])
+	}
+	return n, err
+}
+
+func (c *capturingConn) record(direction Direction, data []byte) {
+	c.writer.record(Record{
+		Time:       time.Now(),
+		Connection: c.id,
+		Server:     c.server,
+		Direction:  direction,
+		Data:       data,
+	})
+}
+
+// Reader reads the records of a capture.
+type Reader struct {
+	in *bufio.Reader
+}
+
+// NewReader returns a Reader of the capture held by the given io.Reader.
+func NewReader(r io.Reader) (*Reader, error) {
+	in := bufio.NewReader(r)
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(in, header); err != nil {
+		return nil, fmt.Errorf("could not read capture header: %w", err)
+	}
+	if string(header) != string(magic) {
+		return nil, errors.New("not a Bolt capture")
+	}
+	return &Reader{in: in}, nil
+}
+
+// Next returns the next record of the capture, or io.EOF once all records have been read.
+func (r *Reader) Next() (Record, error) {
+	header := make([]byte, 8+4+1+2)
+	if _, err := io.ReadFull(r.in, header); err != nil {
+		if err == io.EOF {
+			return Record{}, io.EOF
+		}
+		return Record{}, truncated(err)
+	}
+	record := Record{
+		Time:       time.Unix(0, int64(binary.BigEndian.Uint64(header))),
+		Connection: binary.BigEndian.Uint32(header[8:]),
+		Direction:  Direction(header[12]),
+	}
+	server := make([]byte, binary.BigEndian.Uint16(header[13:]))
+	if _, err := io.ReadFull(r.in, server); err != nil {
+		return Record{}, truncated(err)
+	}
+	record.Server = string(server)
+	size := make([]byte, 4)
+	if _, err := io.ReadFull(r.in, size); err != nil {
+		return Record{}, truncated(err)
+	}
+	record.Data = make([]byte, binary.BigEndian.Uint32(size))
+	if _, err := io.ReadFull(r.in, record.Data); err != nil {
+		return Record{}, truncated(err)
+	}
+	return record, nil
+}
+
+func truncated(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("truncated capture record: %w", err)
+}
+
+// ReadFile returns all the records of the named capture file.
+func ReadFile(name string) ([]Record, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader, err := NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	var records []Record
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
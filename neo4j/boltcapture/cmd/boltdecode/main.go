@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Command boltdecode prints the Bolt messages held by capture files recorded with the boltcapture package.
+//
+// Usage:
+//
+//	boltdecode [-connection n] [-raw] capture-file...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/boltcapture"
+)
+
+func main() {
+	connection := flag.Uint("connection", 0, "only print the messages of the connection with the given number")
+	raw := flag.Bool("raw", false, "print the captured records as hexadecimal dumps instead of decoded messages")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-connection n] [-raw] capture-file...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	for _, name := range flag.Args() {
+		records, err := boltcapture.ReadFile(name)
+		if err != nil {
+			// still print what could be read, truncated captures are common when the process got killed
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		}
+		if *connection != 0 {
+			records = recordsOf(records, uint32(*connection))
+		}
+		if *raw {
+			for _, record := range records {
+				fmt.Printf("%s  #%d %s  %s: % X\n",
+					record.Time.Format(time.RFC3339Nano), record.Connection, record.Server,
+					record.Direction, record.Data)
+			}
+			continue
+		}
+		for _, message := range boltcapture.Decode(records) {
+			fmt.Println(message)
+		}
+	}
+}
+
+func recordsOf(records []boltcapture.Record, connection uint32) []boltcapture.Record {
+	var result []boltcapture.Record
+	for _, record := range records {
+		if record.Connection == connection {
+			result = append(result, record)
+		}
+	}
+	return result
+}
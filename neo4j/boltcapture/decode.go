@@ -0,0 +1,282 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boltcapture
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/packstream"
+)
+
+// Message is a Bolt message decoded from a capture.
+type Message struct {
+	// Time is the time of the record that completed the message
+	Time       time.Time
+	Connection uint32
+	Server     string
+	Direction  Direction
+	// Type is the name of the message, such as RUN or SUCCESS, or <HANDSHAKE> for the protocol version negotiation
+	Type string
+	// Fields renders the fields of the message
+	Fields string
+}
+
+// String renders the message on a single line, in the fashion of log.BoltToConsole.
+func (m Message) String() string {
+	return fmt.Sprintf("%s  #%d %s  %s: %s %s",
+		m.Time.Format(time.RFC3339Nano), m.Connection, m.Server, m.Direction, m.Type, m.Fields)
+}
+
+// Decode reassembles the messages exchanged over each connection of the given records, in order of completion.
+// Credentials sent by the driver are redacted.
+// Incomplete messages at the end of a connection are left out.
+func Decode(records []Record) []Message {
+	type stream struct {
+		connection uint32
+		direction  Direction
+	}
+	framers := make(map[stream]*framer)
+	var messages []Message
+	for _, record := range records {
+		key := stream{connection: record.Connection, direction: record.Direction}
+		framer := framers[key]
+		if framer == nil {
+			framer = newFramer(record.Direction)
+			framers[key] = framer
+		}
+		for _, frame := range framer.feed(record.Data) {
+			message := Message{
+				Time:       record.Time,
+				Connection: record.Connection,
+				Server:     record.Server,
+				Direction:  record.Direction,
+			}
+			if frame.handshake {
+				message.Type, message.Fields = "<HANDSHAKE>", renderHandshake(frame.data)
+			} else {
+				message.Type, message.Fields = renderMessage(record.Direction, frame.data)
+			}
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+type frame struct {
+	handshake bool
+	data      []byte
+}
+
+// framer splits the bytes sent in one direction of a connection into the handshake and the dechunked messages
+// that follow it.
+type framer struct {
+	handshake int
+	pending   []byte
+	message   []byte
+}
+
+func newFramer(direction Direction) *framer {
+	// the driver sends the magic preamble along with 4 versions, the server replies with the selected version
+	handshake := 20
+	if direction == ServerToClient {
+		handshake = 4
+	}
+	return &framer{handshake: handshake}
+}
+
+// feed returns the frames completed by the given bytes
+func (f *framer) feed(data []byte) []frame {
+	f.pending = append(f.pending, data...)
+	var frames []frame
+	if f.handshake > 0 {
+		if len(f.pending) < f.handshake {
+			return nil
+		}
+		frames = append(frames, frame{handshake: true, data: append([]byte(nil), f.pending[:f.handshake]...)})
+		f.pending = f.pending[f.handshake:]
+		f.handshake = 0
+	}
+	for len(f.pending) >= 2 {
+		size := int(binary.BigEndian.Uint16(f.pending))
+		if size == 0 {
+			f.pending = f.pending[2:]
+			// an empty chunk outside a message is a no-op
+			if len(f.message) > 0 {
+				frames = append(frames, frame{data: f.message})
+				f.message = nil
+			}
+			continue
+		}
+		if len(f.pending) < 2+size {
+			break
+		}
+		f.message = append(f.message, f.pending[2:2+size]...)
+		f.pending = f.pending[2+size:]
+	}
+	return frames
+}
+
+func renderHandshake(data []byte) string {
+	words := make([]string, 0, len(data)/4)
+	for i := 0; i+4 <= len(data); i += 4 {
+		words = append(words, fmt.Sprintf("%#010X", data[i:i+4]))
+	}
+	return strings.Join(words, " ")
+}
+
+var clientMessageTypes = map[byte]string{
+	0x01: "HELLO",
+	0x02: "GOODBYE",
+	0x0f: "RESET",
+	0x10: "RUN",
+	0x11: "BEGIN",
+	0x12: "COMMIT",
+	0x13: "ROLLBACK",
+	0x2f: "DISCARD",
+	0x3f: "PULL",
+	0x54: "TELEMETRY",
+	0x66: "ROUTE",
+	0x6a: "LOGON",
+	0x6b: "LOGOFF",
+}
+
+var serverMessageTypes = map[byte]string{
+	0x70: "SUCCESS",
+	0x71: "RECORD",
+	0x7e: "IGNORED",
+	0x7f: "FAILURE",
+}
+
+func renderMessage(direction Direction, data []byte) (string, string) {
+	unpacker := &packstream.Unpacker{}
+	unpacker.Reset(data)
+	unpacker.Next()
+	if unpacker.Curr != packstream.PackedStruct {
+		return "<UNKNOWN>", fmt.Sprintf("%#X", data)
+	}
+	numFields := unpacker.Len()
+	tag := unpacker.StructTag()
+	messageTypes := clientMessageTypes
+	if direction == ServerToClient {
+		messageTypes = serverMessageTypes
+	}
+	messageType, found := messageTypes[tag]
+	if !found {
+		messageType = fmt.Sprintf("<UNKNOWN 0x%02X>", tag)
+	}
+	fields := make([]string, numFields)
+	for i := range fields {
+		unpacker.Next()
+		fields[i] = render(unpackValue(unpacker))
+	}
+	if unpacker.Err != nil {
+		return messageType, fmt.Sprintf("<undecodable: %s> %#X", unpacker.Err, data)
+	}
+	return messageType, strings.Join(fields, " ")
+}
+
+type structure struct {
+	tag    byte
+	fields []any
+}
+
+func unpackValue(unpacker *packstream.Unpacker) any {
+	switch unpacker.Curr {
+	case packstream.PackedInt:
+		return unpacker.Int()
+	case packstream.PackedFloat:
+		return unpacker.Float()
+	case packstream.PackedStr:
+		return unpacker.String()
+	case packstream.PackedByteArray:
+		return unpacker.ByteArray()
+	case packstream.PackedTrue, packstream.PackedFalse:
+		return unpacker.Bool()
+	case packstream.PackedArray:
+		list := make([]any, unpacker.Len())
+		for i := range list {
+			unpacker.Next()
+			list[i] = unpackValue(unpacker)
+		}
+		return list
+	case packstream.PackedMap:
+		numEntries := unpacker.Len()
+		dictionary := make(map[string]any, numEntries)
+		for i := uint32(0); i < numEntries; i++ {
+			unpacker.Next()
+			key := unpacker.String()
+			unpacker.Next()
+			dictionary[key] = unpackValue(unpacker)
+		}
+		return dictionary
+	case packstream.PackedStruct:
+		result := structure{fields: make([]any, unpacker.Len())}
+		result.tag = unpacker.StructTag()
+		for i := range result.fields {
+			unpacker.Next()
+			result.fields[i] = unpackValue(unpacker)
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func render(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", value)
+	case []byte:
+		return fmt.Sprintf("%#X", value)
+	case []any:
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = render(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		entries := make([]string, len(keys))
+		for i, key := range keys {
+			if key == "credentials" {
+				entries[i] = fmt.Sprintf("%q: \"<redacted>\"", key)
+				continue
+			}
+			entries[i] = fmt.Sprintf("%q: %s", key, render(value[key]))
+		}
+		return "{" + strings.Join(entries, ", ") + "}"
+	case structure:
+		fields := make([]string, len(value.fields))
+		for i, field := range value.fields {
+			fields[i] = render(field)
+		}
+		return fmt.Sprintf("Structure<0x%02X>(%s)", value.tag, strings.Join(fields, ", "))
+	default:
+		return fmt.Sprintf("%v", value)
+	}
+}
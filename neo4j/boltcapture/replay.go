@@ -0,0 +1,184 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package boltcapture
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// ReplayServer is a fake Bolt server replaying the server side of a capture, so that the driver can be run
+// against it instead of a real server.
+//
+// The n-th connection accepted by the server replays the n-th connection of the capture: every time the driver has
+// sent as many messages as it had when the capture was recorded, the server sends back the bytes it had received
+// at that point. The content of the messages sent by the driver is not compared to the capture, so that values
+// differing from one run to the other (such as bookmarks or the user agent) do not get in the way.
+// Once the capture of a connection is exhausted, the server closes it.
+// Timestamps are ignored: the capture is replayed as fast as the driver goes.
+//
+// Routing tables are replayed as captured and therefore point to the original servers.
+// Captures of direct connections (bolt:// URIs) are thus easier to replay.
+type ReplayServer struct {
+	listener net.Listener
+	scripts  [][]step
+	wg       sync.WaitGroup
+	mut      sync.Mutex
+	accepted int
+	err      error
+}
+
+// step either waits for the driver to send a number of messages or sends bytes to the driver
+type step struct {
+	expect int
+	send   []byte
+}
+
+// NewReplayServer starts a ReplayServer listening on a random local port.
+func NewReplayServer(records []Record) (*ReplayServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	server := &ReplayServer{listener: listener, scripts: scriptsOf(records)}
+	server.wg.Add(1)
+	go server.serve()
+	return server, nil
+}
+
+func scriptsOf(records []Record) [][]step {
+	var scripts [][]step
+	indices := make(map[uint32]int)
+	framers := make(map[uint32]*framer)
+	for _, record := range records {
+		index, found := indices[record.Connection]
+		if !found {
+			index = len(scripts)
+			indices[record.Connection] = index
+			scripts = append(scripts, nil)
+			framers[record.Connection] = newFramer(ClientToServer)
+		}
+		script := scripts[index]
+		var last *step
+		if len(script) > 0 {
+			last = &script[len(script)-1]
+		}
+		if record.Direction == ClientToServer {
+			messages := len(framers[record.Connection].feed(record.Data))
+			if messages == 0 {
+				continue
+			}
+			if last != nil && last.send == nil {
+				last.expect += messages
+				continue
+			}
+			scripts[index] = append(script, step{expect: messages})
+			continue
+		}
+		if last != nil && last.send != nil {
+			last.send = append(last.send, record.Data...)
+			continue
+		}
+		scripts[index] = append(script, step{send: append([]byte(nil), record.Data...)})
+	}
+	return scripts
+}
+
+// Address returns the host and port the server listens on, to be used in the URI passed to the driver.
+func (s *ReplayServer) Address() string {
+	return s.listener.Addr().String()
+}
+
+// Err returns the first error that occurred while replaying, such as the driver opening more connections than
+// the capture holds or closing a connection earlier than it did when the capture was recorded.
+func (s *ReplayServer) Err() error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.err
+}
+
+// Close stops the server and waits for the connections being replayed to end.
+func (s *ReplayServer) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *ReplayServer) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				s.fail(err)
+			}
+			return
+		}
+		s.mut.Lock()
+		index := s.accepted
+		s.accepted++
+		s.mut.Unlock()
+		if index >= len(s.scripts) {
+			s.fail(fmt.Errorf("connection #%d is not part of the capture, which holds %d connection(s)",
+				index+1, len(s.scripts)))
+			_ = conn.Close()
+			continue
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			if err := replay(conn, s.scripts[index]); err != nil {
+				s.fail(fmt.Errorf("connection #%d: %w", index+1, err))
+			}
+		}()
+	}
+}
+
+func replay(conn net.Conn, script []step) error {
+	framer := newFramer(ClientToServer)
+	buffer := make([]byte, 4096)
+	received := 0
+	for _, step := range script {
+		if step.send != nil {
+			if _, err := conn.Write(step.send); err != nil {
+				return err
+			}
+			continue
+		}
+		for received < step.expect {
+			n, err := conn.Read(buffer)
+			received += len(framer.feed(buffer[:n]))
+			if err != nil && received < step.expect {
+				return fmt.Errorf("expected %d more message(s) from the driver: %w", step.expect-received, err)
+			}
+		}
+		received -= step.expect
+	}
+	return nil
+}
+
+func (s *ReplayServer) fail(err error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.err == nil {
+		s.err = err
+	}
+}
@@ -42,6 +42,11 @@ func defaultConfig() *Config {
 		MaxTransactionRetryTime:              30 * time.Second,
 		MaxConnectionPoolSize:                100,
 		MaxConnectionLifetime:                1 * time.Hour,
+		CircuitBreakerFailureRate:            0.5,
+		CircuitBreakerWindow:                 1 * time.Minute,
+		CircuitBreakerCoolDown:               30 * time.Second,
+		CircuitBreakerHalfOpenProbes:         1,
+		RoutingTableIdleTimeout:              5 * time.Minute,
 		ConnectionAcquisitionTimeout:         1 * time.Minute,
 		ConnectionLivenessCheckTimeout:       pool.DefaultConnectionLivenessCheckTimeout,
 		SocketConnectTimeout:                 5 * time.Second,
@@ -72,11 +77,55 @@ func validateAndNormaliseConfig(config *Config) error {
 		config.MaxConnectionPoolSize = math.MaxInt32
 	}
 
+	// Min Idle Connections Per Server
+	if config.MinIdleConnectionsPerServer < 0 {
+		return &UsageError{Message: "Minimum idle connections per server cannot be smaller than 0"}
+	}
+
+	if config.MinIdleConnectionsPerServer > config.MaxConnectionPoolSize {
+		return &UsageError{Message: "Minimum idle connections per server cannot be greater than the maximum connection pool size"}
+	}
+
 	// Max Connection Lifetime
 	if config.MaxConnectionLifetime <= 0 {
 		config.MaxConnectionLifetime = 1<<63 - 1
 	}
 
+	// Max Connection Lifetime Jitter
+	if config.MaxConnectionLifetimeJitter < 0 {
+		return &UsageError{Message: "Maximum connection lifetime jitter cannot be smaller than 0"}
+	}
+
+	if config.MaxConnectionLifetimeJitter > config.MaxConnectionLifetime {
+		return &UsageError{Message: "Maximum connection lifetime jitter cannot be greater than the maximum connection lifetime"}
+	}
+
+	// Locality
+	if config.Locality != nil && config.Locality.LocalZone == "" {
+		return &UsageError{Message: "Locality local zone cannot be empty"}
+	}
+
+	// Circuit Breaker
+	if config.CircuitBreakerFailureThreshold > 0 {
+		if config.CircuitBreakerFailureRate <= 0 || config.CircuitBreakerFailureRate > 1 {
+			return &UsageError{Message: "Circuit breaker failure rate must be greater than 0 and at most 1"}
+		}
+		if config.CircuitBreakerWindow <= 0 {
+			return &UsageError{Message: "Circuit breaker window must be greater than 0"}
+		}
+		if config.CircuitBreakerCoolDown <= 0 {
+			return &UsageError{Message: "Circuit breaker cool-down must be greater than 0"}
+		}
+		if config.CircuitBreakerHalfOpenProbes <= 0 {
+			return &UsageError{Message: "Circuit breaker half-open probes must be greater than 0"}
+		}
+	}
+
+	// Routing Table Refresh
+	if config.RoutingTableRefreshAhead > 0 && config.RoutingTableIdleTimeout <= 0 {
+		return &UsageError{Message: "Routing table idle timeout must be greater than 0"}
+	}
+
 	// Connection Acquisition Timeout
 	if config.ConnectionAcquisitionTimeout < 0 {
 		config.ConnectionAcquisitionTimeout = -1
@@ -21,8 +21,14 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/auth"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/boltcapture"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/events"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/loadbalancing"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/notifications"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
 	"time"
 )
 
@@ -89,9 +95,42 @@ type Config struct {
 	// be useful if you want to provide more than one URL for initial router.
 	// If not specified, the URL provided to NewDriver or NewDriverWithContext
 	// is used as the initial router.
+	// See the resolver package for built-in resolvers based on DNS SRV records or on all the IP addresses of a host.
 	//
 	// default: nil
 	AddressResolver ServerAddressResolver
+	// ShuffleInitialRouters makes drivers created with a URI listing several hosts, such as neo4j://a:7687,b:7687,
+	// try these hosts in random order rather than in the order of the URI, spreading the fetching of routing tables
+	// over them.
+	//
+	// default: false
+	ShuffleInitialRouters bool
+	// RoutingTableCache persists the routing tables of drivers created with one of the neo4j:// URI schemes, so that
+	// a driver created later, e.g. after a process restart, does not depend on the initial router being available.
+	// See routing.NewFileCache for a file-based implementation.
+	//
+	// Tables are stored whenever one of them is fetched, under the address of the initial router.
+	// When the driver is created, the stored tables are imported and refreshed in the background. Imported tables
+	// are used until they expire, as if they had just been fetched, and their routers are tried whenever the
+	// initial router fails.
+	//
+	// default: nil (routing tables are not persisted)
+	RoutingTableCache routing.Cache
+	// RoutingTableRefreshAhead makes drivers created with one of the neo4j:// URI schemes renew the routing tables of
+	// recently used databases in the background, up to RoutingTableRefreshAhead before they expire (and at the
+	// earliest halfway through their time to live), so that queries do not wait for routing tables to be fetched.
+	// Failed refreshes are retried with exponential back-off until the table is fetched again or evicted.
+	// The routing tables of databases not used within RoutingTableIdleTimeout are evicted.
+	// Values less than or equal to 0 disable the background refresh: routing tables are then fetched by the first
+	// query needing them once expired, and expired tables are evicted when sessions are closed.
+	//
+	// default: 0
+	RoutingTableRefreshAhead time.Duration
+	// Routing tables of databases not used for longer than RoutingTableIdleTimeout are no longer refreshed in the
+	// background and are evicted, see RoutingTableRefreshAhead. It must be greater than 0.
+	//
+	// default: 5 * time.Minute
+	RoutingTableIdleTimeout time.Duration
 	// Maximum amount of time a retryable operation would continue retrying. It
 	// cannot be specified as a negative value.
 	//
@@ -103,11 +142,108 @@ type Config struct {
 	//
 	// default: 100
 	MaxConnectionPoolSize int
+	// Maximum number of connections to specific servers, keyed by server address (host:port), overriding
+	// MaxConnectionPoolSize for these servers. Values less than or equal to 0 are ignored.
+	//
+	// default: nil
+	MaxConnectionPoolSizePerServer map[string]int
+	// Maximum number of connection acquisitions waiting for a connection to be returned to the pool, across all
+	// servers. Further acquisitions fail immediately with a ConnectionAcquisitionQueueFullError, shedding load
+	// instead of piling up waiters. Values less than or equal to 0 mean no limit.
+	// Waiting acquisitions are served in the order they started waiting, per server.
+	//
+	// default: 0
+	MaxConnectionAcquisitionQueueSize int
+	// LoadBalancer decides which server connections are acquired from, among the candidate servers (e.g. the readers
+	// of a database). It is given statistics about every candidate, such as the number of connections in use and
+	// the observed latencies. See the loadbalancing package for built-in strategies.
+	//
+	// When nil, servers that recently failed to accept a connection are avoided, then the servers with the fewest
+	// connections in use, then the servers with idle connections are preferred, in a round-robin fashion.
+	//
+	// default: nil
+	LoadBalancer loadbalancing.LoadBalancer
+	// Locality tells the driver which zone (e.g. availability zone) the application and every server are in, so
+	// that connections, notably for reads, are acquired from the servers in the same zone as the application first,
+	// saving cross-zone latency and costs. Servers of other zones are used as decided by the spill-over policy, see
+	// loadbalancing.SpillOver. Within each zone, servers are ordered as described for LoadBalancer.
+	// Its LocalZone cannot be empty.
+	//
+	// default: nil (servers are used regardless of their zone)
+	Locality *loadbalancing.Locality
+	// Number of failures with a server within CircuitBreakerWindow that opens the circuit breaker of the server,
+	// provided that failures also make up at least CircuitBreakerFailureRate of the outcomes observed in the window.
+	// Failures are I/O errors (including read timeouts) and failures to establish a connection; successes are
+	// connections given back to the pool in a healthy state.
+	//
+	// While the circuit breaker of a server is open, no connection is acquired from the server. Acquisitions only
+	// targeting servers with an open circuit breaker fail immediately.
+	// After CircuitBreakerCoolDown, the circuit breaker is half-open: CircuitBreakerHalfOpenProbes connection
+	// acquisitions are let through as probes. The circuit breaker closes once all of them succeed, and opens again as
+	// soon as one of them fails.
+	//
+	// State changes are logged, and reported by the metrics of the server (see DriverWithContext.Metrics).
+	// Values less than or equal to 0 disable the circuit breakers.
+	//
+	// default: 0
+	CircuitBreakerFailureThreshold int
+	// Minimum ratio of failures among the outcomes observed with a server within CircuitBreakerWindow for its
+	// circuit breaker to open, see CircuitBreakerFailureThreshold. It must be greater than 0 and at most 1.
+	//
+	// default: 0.5
+	CircuitBreakerFailureRate float64
+	// Duration of the windows failures and successes are counted in, see CircuitBreakerFailureThreshold.
+	// It must be greater than 0.
+	//
+	// default: 1 * time.Minute
+	CircuitBreakerWindow time.Duration
+	// Amount of time the circuit breaker of a server stays open before letting probes through, see
+	// CircuitBreakerFailureThreshold. It must be greater than 0.
+	//
+	// default: 30 * time.Second
+	CircuitBreakerCoolDown time.Duration
+	// Number of connection acquisitions let through as probes by a half-open circuit breaker, see
+	// CircuitBreakerFailureThreshold. It must be greater than 0.
+	//
+	// default: 1
+	CircuitBreakerHalfOpenProbes int
+	// Minimum number of idle connections per server the driver keeps open, so that queries do not pay for
+	// establishing connections. The floor is maintained in the background, e.g. as connections expire after
+	// MaxConnectionLifetime, for every reader and writer of the routing tables known to the driver (or for the
+	// server of drivers created with one of the bolt:// URI schemes).
+	// See also DriverWithContext.WarmUp to open these connections eagerly.
+	// It cannot be negative nor greater than MaxConnectionPoolSize.
+	//
+	// default: 0
+	MinIdleConnectionsPerServer int
 	// Maximum connection lifetime on pooled connections. Values less than
 	// or equal to 0 disables the lifetime check.
 	//
 	// default: 1 * time.Hour
 	MaxConnectionLifetime time.Duration
+	// Spreads the expiry of pooled connections: every connection expires up to MaxConnectionLifetimeJitter
+	// before MaxConnectionLifetime, so that connections created at the same time (e.g. when warming up the pool)
+	// are not all closed and re-established at once. It cannot be negative nor greater than MaxConnectionLifetime.
+	//
+	// default: 0
+	MaxConnectionLifetimeJitter time.Duration
+	// Maximum amount of time a pooled connection can stay idle. Connections idle for longer are closed by the
+	// background maintenance of the pool, while keeping MinIdleConnectionsPerServer idle connections per server.
+	// Values less than or equal to 0 disable the idle time check.
+	//
+	// default: 0
+	MaxConnectionIdleTime time.Duration
+	// Idle connections kept in the pool are probed with a RESET message once idle for longer than
+	// ConnectionKeepAliveInterval, so that firewalls and NATs do not silently drop them. Connections failing the
+	// probe are closed.
+	// When both MaxConnectionIdleTime and MinIdleConnectionsPerServer are set, only the
+	// MinIdleConnectionsPerServer idle connections meant to be kept are probed, the others are left to expire.
+	// Otherwise, all idle connections are probed. A probe counts as a use of the connection, so probing more often
+	// than MaxConnectionIdleTime keeps the probed connections from being evicted.
+	// Values less than or equal to 0 disable the keep-alive probes.
+	//
+	// default: 0
+	ConnectionKeepAliveInterval time.Duration
 	// Maximum amount of time to either acquire an idle connection from the pool
 	// or create a new connection (when the pool is not full). Negative values
 	// result in an infinite wait time, whereas a 0 value results in no timeout.
@@ -191,6 +327,27 @@ type Config struct {
 	// NotificationsDisabledClassifications is part of the GQL compliant notifications preview feature
 	// (see README on what it means in terms of support and compatibility guarantees)
 	NotificationsDisabledClassifications notifications.NotificationDisabledClassifications
+	// NotificationHandler is called for each notification the server reports in a result summary, once the summary
+	// of a query result has been received.
+	// This makes it possible to log, meter or act upon notifications in a central place, instead of inspecting
+	// every neo4j.ResultSummary.
+	// Notifications still have to be sent by the server in the first place, i.e. NotificationsMinSeverity and
+	// NotificationsDisabledCategories (or NotificationsDisabledClassifications) apply beforehand.
+	//
+	// The handler can be overridden per session with neo4j.SessionConfig's NotificationHandler.
+	//
+	// default: nil (no handler)
+	NotificationHandler NotificationHandler
+	// NotificationsStrictPolicy defines the notifications that make queries fail with a neo4j.NotificationError.
+	// This is useful in testing environments, for instance to make sure no query relies on deprecated features or
+	// suffers from a cartesian product.
+	//
+	// The error is raised when the result summary is received, i.e. when consuming the result.
+	// The query has been executed by then: auto-commit transactions are not rolled back, while transaction
+	// functions (and neo4j.ExecuteQuery) roll back, as long as the error is returned from the function.
+	//
+	// default: empty policy (no notification results in an error)
+	NotificationsStrictPolicy notifications.StrictPolicy
 	// By default, if the server requests it, the driver will automatically transmit anonymous usage
 	// statistics to the server it is connected to.
 	//
@@ -212,6 +369,89 @@ type Config struct {
 	// for large data transfers. Currently, the default value is 8 KiB, but may change in the future.
 	// Set to 0 or below to disable buffering.
 	ReadBufferSize int
+	// Tracer is called whenever the driver starts and ends sessions, transactions, queries, connection
+	// acquisitions, routing table refreshes and waits between transaction function retries.
+	// See the tracing package for the reported operations and attributes.
+	//
+	// default: nil (nothing is traced)
+	Tracer tracing.Tracer
+	// SlowQueryThreshold defines the duration above which queries are reported as slow.
+	// The duration of a query is measured client-side and is made of the connection acquisition, the time until the
+	// result is available and the time spent consuming the result (see SlowQuery).
+	// Slow queries are reported to SlowQueryHandler or, if no handler is set, logged as warnings by Log.
+	//
+	// default: 0 (slow queries are not reported)
+	SlowQueryThreshold time.Duration
+	// SlowQueryHandler is called for each query whose duration exceeds SlowQueryThreshold, once its result has been
+	// consumed or has failed.
+	//
+	// default: nil (slow queries are logged as warnings)
+	SlowQueryHandler SlowQueryHandler
+	// BoltCapture records the raw Bolt traffic of the connections selected by BoltCaptureFilter.
+	// See the boltcapture package for decoding captures and replaying them.
+	//
+	// Captures contain everything sent over the wire, including credentials, queries and results.
+	// Records are written through to the capture as they happen, no flush is needed for them to survive a crash.
+	// Closing the boltcapture.Writer is left to the caller, once the driver is closed.
+	//
+	// default: nil (no traffic is captured)
+	BoltCapture *boltcapture.Writer
+	// BoltCaptureFilter selects the connections captured by BoltCapture, given the address of the server the
+	// connection is established with.
+	//
+	// default: nil (all connections are captured)
+	BoltCaptureFilter func(serverAddress string) bool
+	// ConnectionListener is notified whenever a connection is created, borrowed, returned, reset, found dead or
+	// closed, and whenever a server is deactivated.
+	// See the events package for the reported events.
+	//
+	// default: nil (no listener)
+	ConnectionListener events.ConnectionListener
+}
+
+// NotificationHandler is a function type that is called with the query text and each notification of a result
+// summary.
+// Every notification is passed in both of its forms: as a db.Notification and as the equivalent db.GqlStatusObject.
+// When the server only supports one of them, the other one is derived by the driver, in the same way as
+// neo4j.ResultSummary's Notifications and GqlStatusObjects do.
+//
+// The handler runs on the goroutine that receives the summary: the one reading or consuming the result, or, for
+// results left unconsumed, the one committing the transaction, running the next query or closing the session.
+// A handler set on the driver is shared by all its sessions, which may call it concurrently.
+type NotificationHandler func(query string, notification db.Notification, gqlStatusObject db.GqlStatusObject)
+
+// SlowQueryHandler is a function type that is called with each query whose duration exceeds
+// Config.SlowQueryThreshold.
+//
+// The handler runs once the query ends, on the goroutine that ends it: the one that reads the last record, consumes
+// the result or gets the query error, or, for results left unconsumed, the one committing or rolling back the
+// transaction or closing the session. Its duration is therefore not part of the reported query duration.
+type SlowQueryHandler func(query SlowQuery)
+
+// SlowQuery describes a query whose duration exceeded Config.SlowQueryThreshold.
+type SlowQuery struct {
+	// Query is the text of the query
+	Query string
+	// Parameters holds the name of each query parameter, associated with the Go type of its value.
+	// Values are not reported, since they may contain sensitive information.
+	Parameters map[string]string
+	// TransactionMetadata is the metadata of the transaction the query ran in
+	TransactionMetadata map[string]any
+	// Database is the name of the database the query ran against, empty if the default database was used
+	Database string
+	// Server is the address of the server the query ran on
+	Server string
+	// ConnectionAcquisition is the time spent acquiring a connection for the transaction.
+	// Only the first query of a transaction accounts for it.
+	ConnectionAcquisition time.Duration
+	// ResultAvailableAfter is the time until the result was available, i.e. until the server acknowledged the query
+	ResultAvailableAfter time.Duration
+	// ResultConsumption is the time spent from the result being available until it was consumed or failed
+	ResultConsumption time.Duration
+	// Total is the sum of ConnectionAcquisition, ResultAvailableAfter and ResultConsumption
+	Total time.Duration
+	// Err is the error the query failed with, nil if it succeeded
+	Err error
 }
 
 // ServerAddressResolver is a function type that defines the resolver function used by the routing driver to
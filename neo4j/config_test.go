@@ -18,6 +18,7 @@
 package neo4j
 
 import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/loadbalancing"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/notifications"
 	"math"
 	"testing"
@@ -87,6 +88,99 @@ func TestValidateAndNormaliseConfig(rt *testing.T) {
 		}
 	})
 
+	rt.Run("MinIdleConnectionsPerServer less than zero", func(t *testing.T) {
+		config := defaultConfig()
+
+		config.MinIdleConnectionsPerServer = -1
+		err := validateAndNormaliseConfig(config)
+		if err == nil {
+			t.Errorf("MinIdleConnectionsPerServer is negative but never returned an error")
+		}
+	})
+
+	rt.Run("MinIdleConnectionsPerServer greater than MaxConnectionPoolSize", func(t *testing.T) {
+		config := defaultConfig()
+
+		config.MinIdleConnectionsPerServer = 101
+		err := validateAndNormaliseConfig(config)
+		if err == nil {
+			t.Errorf("MinIdleConnectionsPerServer is greater than MaxConnectionPoolSize but never returned an error")
+		}
+	})
+
+	rt.Run("MaxConnectionLifetimeJitter less than zero", func(t *testing.T) {
+		config := defaultConfig()
+
+		config.MaxConnectionLifetimeJitter = -1 * time.Second
+		err := validateAndNormaliseConfig(config)
+		if err == nil {
+			t.Errorf("MaxConnectionLifetimeJitter is negative but never returned an error")
+		}
+	})
+
+	rt.Run("MaxConnectionLifetimeJitter greater than MaxConnectionLifetime", func(t *testing.T) {
+		config := defaultConfig()
+
+		config.MaxConnectionLifetimeJitter = 2 * time.Hour
+		err := validateAndNormaliseConfig(config)
+		if err == nil {
+			t.Errorf("MaxConnectionLifetimeJitter is greater than MaxConnectionLifetime but never returned an error")
+		}
+	})
+
+	rt.Run("Locality without local zone", func(t *testing.T) {
+		config := defaultConfig()
+
+		config.Locality = &loadbalancing.Locality{Zones: map[string]string{"a:7687": "eu-west-1a"}}
+		err := validateAndNormaliseConfig(config)
+		if err == nil {
+			t.Errorf("Locality local zone is empty but never returned an error")
+		}
+	})
+
+	rt.Run("CircuitBreakerFailureRate greater than one", func(t *testing.T) {
+		config := defaultConfig()
+
+		config.CircuitBreakerFailureThreshold = 5
+		config.CircuitBreakerFailureRate = 1.5
+		err := validateAndNormaliseConfig(config)
+		if err == nil {
+			t.Errorf("CircuitBreakerFailureRate is greater than one but never returned an error")
+		}
+	})
+
+	rt.Run("CircuitBreakerHalfOpenProbes zero", func(t *testing.T) {
+		config := defaultConfig()
+
+		config.CircuitBreakerFailureThreshold = 5
+		config.CircuitBreakerHalfOpenProbes = 0
+		err := validateAndNormaliseConfig(config)
+		if err == nil {
+			t.Errorf("CircuitBreakerHalfOpenProbes is zero but never returned an error")
+		}
+	})
+
+	rt.Run("Circuit breaker settings ignored when disabled", func(t *testing.T) {
+		config := defaultConfig()
+
+		config.CircuitBreakerWindow = 0
+		err := validateAndNormaliseConfig(config)
+		if err != nil {
+			t.Errorf("Circuit breaker is disabled but an error was returned: %s", err)
+		}
+	})
+
+	rt.Run("RoutingTableIdleTimeout zero", func(t *testing.T) {
+		config := defaultConfig()
+
+		config.RoutingTableRefreshAhead = 10 * time.Second
+		config.RoutingTableIdleTimeout = 0
+		err := validateAndNormaliseConfig(config)
+		if err == nil {
+			t.Errorf("RoutingTableIdleTimeout is zero but never returned an error")
+		}
+	})
+
 	rt.Run("ConnectionAcquisitionTimeout less than zero", func(t *testing.T) {
 		config := defaultConfig()
 
@@ -21,6 +21,7 @@ import (
 	"context"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
 )
 
 // A router implementation that never routes
@@ -57,3 +58,15 @@ func (r *directRouter) GetNameOfDefaultDatabase(context.Context, []string, strin
 func (r *directRouter) Invalidate(string) {}
 
 func (r *directRouter) CleanUp() {}
+
+func (r *directRouter) Servers() []string {
+	return []string{r.address}
+}
+
+func (r *directRouter) HasServer(server string) bool {
+	return server == r.address
+}
+
+func (r *directRouter) Metrics() map[string]metrics.RoutingTableMetrics {
+	return nil
+}
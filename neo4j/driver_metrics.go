@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"sync"
+	"time"
+
+	imetrics "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/metrics"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/telemetry"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
+)
+
+// driverStats collects the transaction function and query statistics of a driver.
+// A nil *driverStats collects nothing.
+// Thread safe
+type driverStats struct {
+	mut          sync.Mutex
+	transactions map[string]*metrics.TransactionMetrics
+	queries      map[metrics.QueryKey]*queryStats
+}
+
+type queryStats struct {
+	succeeded int64
+	failed    int64
+	latency   imetrics.Histogram
+}
+
+func newDriverStats() *driverStats {
+	return &driverStats{
+		transactions: make(map[string]*metrics.TransactionMetrics),
+		queries:      make(map[metrics.QueryKey]*queryStats),
+	}
+}
+
+func (d *driverStats) onTransactionFunction(database string, attempts int, err error) {
+	if d == nil {
+		return
+	}
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	stats := d.transactions[database]
+	if stats == nil {
+		stats = &metrics.TransactionMetrics{}
+		d.transactions[database] = stats
+	}
+	stats.Executions++
+	if attempts > 1 {
+		stats.Retries += int64(attempts - 1)
+	}
+	if err != nil {
+		stats.Failures++
+	}
+}
+
+func (d *driverStats) onQuery(key metrics.QueryKey, latency time.Duration, err error) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	stats := d.queries[key]
+	if stats == nil {
+		stats = &queryStats{}
+		d.queries[key] = stats
+	}
+	if err != nil {
+		stats.failed++
+		return
+	}
+	stats.succeeded++
+	stats.latency.Observe(latency)
+}
+
+// measureQuery wraps the span of a query so that its latency is recorded when it ends
+func (d *driverStats) measureQuery(span tracing.Span, api telemetry.API, database string) tracing.Span {
+	if d == nil {
+		return span
+	}
+	return &measuredQuerySpan{
+		delegate: span,
+		stats:    d,
+		key:      metrics.QueryKey{API: apiOf(api), Database: database},
+		start:    itime.Now(),
+	}
+}
+
+func (d *driverStats) snapshot(driverMetrics *metrics.DriverMetrics) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	driverMetrics.Transactions = make(map[string]metrics.TransactionMetrics, len(d.transactions))
+	for database, stats := range d.transactions {
+		driverMetrics.Transactions[database] = *stats
+	}
+	driverMetrics.Queries = make(map[metrics.QueryKey]metrics.QueryMetrics, len(d.queries))
+	for key, stats := range d.queries {
+		driverMetrics.Queries[key] = metrics.QueryMetrics{
+			Succeeded: stats.succeeded,
+			Failed:    stats.failed,
+			Latency:   stats.latency.Snapshot(),
+		}
+	}
+}
+
+type measuredQuerySpan struct {
+	delegate tracing.Span
+	stats    *driverStats
+	key      metrics.QueryKey
+	start    time.Time
+}
+
+func (m *measuredQuerySpan) End(attributes map[string]any, err error) {
+	m.stats.onQuery(m.key, itime.Since(m.start), err)
+	m.delegate.End(attributes, err)
+}
+
+func apiOf(api telemetry.API) metrics.API {
+	switch api {
+	case telemetry.ManagedTransaction:
+		return metrics.ManagedTransactionAPI
+	case telemetry.UnmanagedTransaction:
+		return metrics.UnmanagedTransactionAPI
+	case telemetry.AutoCommitTransaction:
+		return metrics.AutoCommitTransactionAPI
+	default:
+		return metrics.ExecuteQueryAPI
+	}
+}
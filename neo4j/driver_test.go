@@ -165,6 +165,32 @@ func TestDriverURIRoutingContext(t *testing.T) {
 	})
 }
 
+func TestDriverURIMultipleHosts(t *testing.T) {
+	t.Run("Uses all hosts as initial routers", func(t *testing.T) {
+		d, err := NewDriver("neo4j://a:7688,b,[::1]:7689/?x=y", NoAuth())
+
+		AssertNoError(t, err)
+		AssertStringEqual(t, d.Target().Host, "a:7688")
+		assertRouterContext(t, d, map[string]string{"x": "y", "address": "a:7688"})
+		r := d.(*driver).delegate.(*driverWithContext).router.(*router.Router)
+		AssertDeepEquals(t, r.InitialRouters(), []string{"a:7688", "b:7687", "[::1]:7689"})
+	})
+
+	t.Run("Empty hosts should error", func(t *testing.T) {
+		_, err := NewDriver("neo4j://a:7687,,b:7687", NoAuth())
+
+		AssertError(t, err)
+		assertUsageError(t, err)
+	})
+
+	t.Run("Multiple hosts without routing should error", func(t *testing.T) {
+		_, err := NewDriver("bolt://a:7687,b:7687", NoAuth())
+
+		AssertError(t, err)
+		assertUsageError(t, err)
+	})
+}
+
 func TestDriverDefaultPort(t *testing.T) {
 	t.Run("neo4j://localhost should default to port 7687", func(t1 *testing.T) {
 		driver, err := NewDriver("neo4j://localhost", NoAuth())
@@ -21,19 +21,29 @@ package neo4j
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/url"
 	"strings"
 	"sync"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/auth"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/collections"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/connector"
 	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/pool"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/router"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
 )
 
+// tlsServerNamed is implemented by the resolved addresses whose server certificates are verified against another name
+// than their host, see resolver.Host.
+type tlsServerNamed interface {
+	TlsServerName() string
+}
+
 // AccessMode defines modes that routing driver decides to which cluster member
 // a connection should be opened.
 type AccessMode int
@@ -84,6 +94,18 @@ type DriverWithContext interface {
 	VerifyAuthentication(ctx context.Context, auth *AuthToken) error
 	// Close the driver and all underlying connections
 	Close(ctx context.Context) error
+	// Shutdown gracefully closes the driver, e.g. before a process is replaced during a deployment.
+	//
+	// Sessions can no longer be created once Shutdown is called, while existing sessions carry on: Shutdown waits
+	// until their transactions and results are over, i.e. until every connection has been given back to the pool.
+	// The driver is then closed like Close does, sending GOODBYE over every connection.
+	//
+	// When the context terminates first, the driver is closed anyway, interrupting the remaining work, and a
+	// ShutdownTimeoutError reporting the remaining work is returned.
+	// Calling Shutdown on a closed driver has no effect.
+	// Calling Shutdown while the driver is shutting down waits for the first call to complete and returns its
+	// outcome, unless the context terminates first, in which case the context error is returned.
+	Shutdown(ctx context.Context) error
 	// IsEncrypted determines whether the driver communication with the server
 	// is encrypted. This is a static check. The function can also be called on
 	// a closed Driver.
@@ -92,6 +114,38 @@ type DriverWithContext interface {
 	// deployment
 	// Contexts terminating too early negatively affect connection pooling and degrade the driver performance.
 	GetServerInfo(ctx context.Context) (ServerInfo, error)
+	// Metrics returns a snapshot of the driver metrics.
+	// They include connection pool metrics, such as the number of idle and in-use connections per server, the
+	// number of queued connection acquisitions and the distribution of connection acquisition times, which help
+	// sizing config.Config MaxConnectionPoolSize and ConnectionAcquisitionTimeout.
+	// They also include routing table refreshes, transaction function retries and query latencies.
+	//
+	// An error is returned if the driver is closed.
+	Metrics() (metrics.DriverMetrics, error)
+	// RoutingTable returns a snapshot of the routing table of the given database, fetching the table first if the
+	// driver does not hold a valid one. Pass an empty database name for the default database.
+	//
+	// An error is returned if the driver is closed or if it does not route, i.e. if it was not created with one of
+	// the neo4j:// URI schemes.
+	// Contexts terminating too early negatively affect connection pooling and degrade the driver performance.
+	RoutingTable(ctx context.Context, database string) (routing.Table, error)
+	// SubscribeRoutingTableChanges registers a listener called whenever servers are added to or removed from a
+	// routing table, be it after the table has been fetched or after a server failure.
+	// The returned function unregisters the listener.
+	//
+	// An error is returned if the driver is closed or if it does not route.
+	SubscribeRoutingTableChanges(listener routing.ChangeListener) (func(), error)
+	// WarmUp eagerly prepares the driver for the given databases, so that the first queries do not pay for
+	// fetching routing tables and establishing connections.
+	// Pass no database for the default database. Databases are ignored by drivers created with one of the bolt://
+	// URI schemes.
+	//
+	// The routing table of every database is fetched, then connections are opened to every reader and writer, up
+	// to config.Config MinIdleConnectionsPerServer (and at least one) per server.
+	// An error is returned if the driver is closed, if a routing table cannot be fetched or if a server cannot be
+	// connected to.
+	// Contexts terminating too early negatively affect connection pooling and degrade the driver performance.
+	WarmUp(ctx context.Context, databases ...string) error
 }
 
 // ResultTransformer is a record accumulator that produces an instance of T when the processing of records is over.
@@ -123,6 +177,12 @@ type ResultTransformer[T any] interface {
 //
 //	driver, err = NewDriverWithContext("neo4j://core.db.server:7687", BasicAuth(username, password))
 //
+// Several core cluster members can be listed, separated by commas. They are tried in order (or in random order, see
+// config.Config ShuffleInitialRouters) whenever a routing table cannot be fetched from the known routers, so that the
+// driver survives the outage of some of them. The first member is used as the target of the driver.
+//
+//	driver, err = NewDriverWithContext("neo4j://core1:7687,core2:7687,core3", BasicAuth(username, password))
+//
 // You can override default configuration options by providing a configuration function(s)
 //
 //	driver, err = NewDriverWithContext(uri, BasicAuth(username, password), function (config *Config) {
@@ -138,6 +198,7 @@ type ResultTransformer[T any] interface {
 //   - `neo4j.BearerAuth`
 //   - `neo4j.CustomAuth`
 func NewDriverWithContext(target string, auth auth.TokenManager, configurers ...func(*Config)) (DriverWithContext, error) {
+	target, otherHosts := splitHosts(target)
 	parsed, err := url.Parse(target)
 	if err != nil {
 		return nil, err
@@ -184,6 +245,20 @@ func NewDriverWithContext(target string, auth auth.TokenManager, configurers ...
 		parsed.Host = address
 	}
 
+	if !routing && len(otherHosts) > 0 {
+		return nil, &UsageError{
+			Message: fmt.Sprintf("Multiple hosts are not supported for URL scheme %s", parsed.Scheme),
+		}
+	}
+	initialRouters := []string{address}
+	for _, host := range otherHosts {
+		router, err := hostWithPort(host)
+		if err != nil {
+			return nil, err
+		}
+		initialRouters = append(initialRouters, router)
+	}
+
 	if !routing && len(parsed.RawQuery) > 0 {
 		return nil, &UsageError{
 			Message: fmt.Sprintf("Routing context is not supported for URL scheme %s", parsed.Scheme),
@@ -218,6 +293,10 @@ func NewDriverWithContext(target string, auth auth.TokenManager, configurers ...
 	d.connector.Log = d.log
 	d.connector.RoutingContext = routingContext
 	d.connector.Config = d.config
+	d.connector.ServerNames = &connector.ServerNames{}
+
+	d.stats = newDriverStats()
+	d.homeDatabases = newHomeDatabaseCache()
 
 	// Let the pool use the same log ID as the driver to simplify log reading.
 	d.pool = pool.New(d.config, d.connector.Connect, d.log, d.logId)
@@ -228,11 +307,16 @@ func NewDriverWithContext(target string, auth auth.TokenManager, configurers ...
 		var routersResolver func() []string
 		addressResolverHook := d.config.AddressResolver
 		if addressResolverHook != nil {
+			serverNames := d.connector.ServerNames
 			routersResolver = func() []string {
 				addresses := addressResolverHook(parsed)
 				servers := make([]string, len(addresses))
 				for i, a := range addresses {
-					servers[i] = fmt.Sprintf("%s:%s", a.Hostname(), a.Port())
+					servers[i] = net.JoinHostPort(a.Hostname(), a.Port())
+					// Addresses resolved from a host name, e.g. by resolver.Host, keep that host name for TLS
+					if named, ok := a.(tlsServerNamed); ok {
+						serverNames.Register(servers[i], named.TlsServerName())
+					}
 				}
 				return servers
 			}
@@ -246,10 +330,22 @@ func NewDriverWithContext(target string, auth auth.TokenManager, configurers ...
 			d.config.ConnectionLivenessCheckTimeout,
 			d.log,
 			d.logId,
+			d.config.Tracer,
+			d.config.ConnectionListener,
 		)
 	}
 
 	d.pool.SetRouter(d.router)
+	if clusterRouter, ok := d.router.(*router.Router); ok {
+		clusterRouter.SetInitialRouters(initialRouters, d.config.ShuffleInitialRouters)
+		if d.config.RoutingTableCache != nil {
+			clusterRouter.UseCache(d.config.RoutingTableCache, address, &idb.ReAuthToken{Manager: d.auth, FromSession: false})
+		}
+		if d.config.RoutingTableRefreshAhead > 0 {
+			clusterRouter.StartRefresher(d.config.RoutingTableRefreshAhead, d.config.RoutingTableIdleTimeout, &idb.ReAuthToken{Manager: d.auth, FromSession: false})
+		}
+	}
+	d.pool.KeepMinIdle(d.router.Servers, &idb.ReAuthToken{Manager: d.auth, FromSession: false})
 
 	d.log.Infof(log.Driver, d.logId, "Created { target: %s }", address)
 	return &d, nil
@@ -257,6 +353,42 @@ func NewDriverWithContext(target string, auth auth.TokenManager, configurers ...
 
 const routingContextAddressKey = "address"
 
+// splitHosts extracts the hosts following the first one in targets listing comma-separated hosts, such as
+// neo4j://a:7687,b:7687,c/, and returns the target with its first host only.
+func splitHosts(target string) (string, []string) {
+	schemeEnd := strings.Index(target, "://")
+	if schemeEnd < 0 {
+		return target, nil
+	}
+	authorityStart := schemeEnd + len("://")
+	authorityEnd := len(target)
+	if i := strings.IndexAny(target[authorityStart:], "/?#"); i >= 0 {
+		authorityEnd = authorityStart + i
+	}
+	authority := target[authorityStart:authorityEnd]
+	hostsStart := strings.LastIndex(authority, "@") + 1
+	hosts := strings.Split(authority[hostsStart:], ",")
+	if len(hosts) == 1 {
+		return target, nil
+	}
+	return target[:authorityStart+hostsStart] + hosts[0] + target[authorityEnd:], hosts[1:]
+}
+
+// hostWithPort validates the host of a URI and adds the default port if the host has none
+func hostWithPort(host string) (string, error) {
+	parsed, err := url.Parse("//" + host)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Host == "" || parsed.Host != host {
+		return "", &UsageError{Message: fmt.Sprintf("Invalid host '%s'", host)}
+	}
+	if parsed.Port() == "" {
+		return host + ":7687", nil
+	}
+	return host, nil
+}
+
 func routingContextFromUrl(useRouting bool, u *url.URL) (map[string]string, error) {
 	if !useRouting {
 		return nil, nil
@@ -312,6 +444,12 @@ type sessionRouter interface {
 	InvalidateWriter(db string, server string)
 	InvalidateReader(db string, server string)
 	InvalidateServer(server string)
+	// Metrics returns the routing table metrics, keyed by database.
+	Metrics() map[string]metrics.RoutingTableMetrics
+	// Servers returns the readers and writers known to the router.
+	Servers() []string
+	// HasServer tells whether the server is a router, reader or writer known to the router.
+	HasServer(server string) bool
 }
 
 type driverWithContext struct {
@@ -329,15 +467,23 @@ type driverWithContext struct {
 	// this is *not* used by default by user-created session (see NewSession)
 	executeQueryBookmarkManager BookmarkManager
 	auth                        auth.TokenManager
+	stats                       *driverStats
+	// draining is set once Shutdown is called, no session can be created anymore
+	draining bool
+	// shutdownDone is closed when the first call to Shutdown completes, with shutdownErr as outcome
+	shutdownDone chan struct{}
+	shutdownErr  error
+	// home databases resolved by the sessions, see sessionWithContext.resolveHomeDatabase
+	homeDatabases *homeDatabaseCache
 }
 
 func (d *driverWithContext) Target() url.URL {
 	return *d.target
 }
 
-// TODO 6.0: remove unused Context parameter
+// TODO 6.0: remove Context parameter, only used as parent of the session span when tracing
 
-func (d *driverWithContext) NewSession(_ context.Context, config SessionConfig) SessionWithContext {
+func (d *driverWithContext) NewSession(ctx context.Context, config SessionConfig) SessionWithContext {
 	if config.DatabaseName == "" {
 		config.DatabaseName = idb.DefaultDatabase
 	}
@@ -358,12 +504,23 @@ func (d *driverWithContext) NewSession(_ context.Context, config SessionConfig)
 	}
 
 	d.mut.Lock()
-	defer d.mut.Unlock()
 	if d.pool == nil {
+		d.mut.Unlock()
 		return &erroredSessionWithContext{
 			err: &UsageError{Message: "Trying to create session on closed driver"}}
 	}
-	return newSessionWithContext(d.config, config, d.router, d.pool, d.log, reAuthToken)
+	if d.draining {
+		d.mut.Unlock()
+		return &erroredSessionWithContext{
+			err: &UsageError{Message: "Trying to create session on shutting down driver"}}
+	}
+	session := newSessionWithContext(d.config, config, d.router, d.pool, d.log, reAuthToken)
+	session.stats = d.stats
+	session.homeDatabases = d.homeDatabases
+	d.mut.Unlock()
+	// the user-provided tracer must not be called with the driver lock held
+	session.startSessionSpan(ctx)
+	return session
 }
 
 func (d *driverWithContext) VerifyConnectivity(ctx context.Context) error {
@@ -387,18 +544,148 @@ func (d *driverWithContext) Close(ctx context.Context) error {
 	d.mut.Lock()
 	if d.pool == nil {
 		// Safeguard against closing more than once
+		d.mut.Unlock()
 		return nil
 	}
 	pool := d.pool
 	d.pool = nil
 	d.mut.Unlock()
 
+	if clusterRouter, ok := d.router.(*router.Router); ok {
+		clusterRouter.StopRefresher()
+	}
 	pool.Close(ctx)
 	pool = nil
 	d.log.Infof(log.Driver, d.logId, "Closed")
 	return nil
 }
 
+func (d *driverWithContext) Shutdown(ctx context.Context) error {
+	d.mut.Lock()
+	if d.draining {
+		// Shutting down already, wait for the first call to complete
+		done := d.shutdownDone
+		d.mut.Unlock()
+		select {
+		case <-done:
+			return d.shutdownErr
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	pool := d.pool
+	if pool == nil {
+		// Safeguard against shutting down a closed driver
+		d.mut.Unlock()
+		return nil
+	}
+	d.draining = true
+	d.shutdownDone = make(chan struct{})
+	d.mut.Unlock()
+
+	d.shutdownErr = d.shutdown(ctx, pool)
+	close(d.shutdownDone)
+	return d.shutdownErr
+}
+
+func (d *driverWithContext) shutdown(ctx context.Context, pool *pool.Pool) error {
+	d.log.Infof(log.Driver, d.logId, "Shutting down, waiting for in-flight work")
+	err := pool.Drain(ctx)
+	if err != nil {
+		d.log.Warnf(log.Driver, d.logId, "Closing while work is still in flight: %s", err)
+	}
+	if closeErr := d.Close(ctx); closeErr != nil {
+		return closeErr
+	}
+	return err
+}
+
+func (d *driverWithContext) Metrics() (metrics.DriverMetrics, error) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	if d.pool == nil {
+		return metrics.DriverMetrics{}, &UsageError{Message: "Trying to get metrics of closed driver"}
+	}
+	driverMetrics := metrics.DriverMetrics{
+		LogId:         d.logId,
+		Pool:          d.pool.Metrics(),
+		RoutingTables: d.router.Metrics(),
+	}
+	d.stats.snapshot(&driverMetrics)
+	return driverMetrics, nil
+}
+
+// routingTableHolder is implemented by the router of drivers created with one of the neo4j:// URI schemes
+type routingTableHolder interface {
+	GetOrUpdateTable(ctx context.Context, bookmarks func(context.Context) ([]string, error), database string, auth *idb.ReAuthToken, boltLogger log.BoltLogger) (routing.Table, error)
+	SubscribeChanges(listener routing.ChangeListener) func()
+}
+
+func (d *driverWithContext) routingTableHolder() (routingTableHolder, error) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	if d.pool == nil {
+		return nil, &UsageError{Message: "Trying to get routing tables of closed driver"}
+	}
+	holder, ok := d.router.(routingTableHolder)
+	if !ok {
+		return nil, &UsageError{Message: "Routing tables are only available to drivers created with a neo4j:// URI"}
+	}
+	return holder, nil
+}
+
+func (d *driverWithContext) RoutingTable(ctx context.Context, database string) (routing.Table, error) {
+	holder, err := d.routingTableHolder()
+	if err != nil {
+		return routing.Table{}, err
+	}
+	noBookmarks := func(context.Context) ([]string, error) {
+		return nil, nil
+	}
+	auth := &idb.ReAuthToken{Manager: d.auth, FromSession: false}
+	table, err := holder.GetOrUpdateTable(ctx, noBookmarks, database, auth, nil)
+	return table, errorutil.WrapError(err)
+}
+
+func (d *driverWithContext) SubscribeRoutingTableChanges(listener routing.ChangeListener) (func(), error) {
+	holder, err := d.routingTableHolder()
+	if err != nil {
+		return nil, err
+	}
+	return holder.SubscribeChanges(listener), nil
+}
+
+func (d *driverWithContext) WarmUp(ctx context.Context, databases ...string) error {
+	d.mut.Lock()
+	driverPool, router := d.pool, d.router
+	d.mut.Unlock()
+	if driverPool == nil {
+		return &UsageError{Message: "Trying to warm up closed driver"}
+	}
+	auth := &idb.ReAuthToken{Manager: d.auth, FromSession: false}
+	servers := router.Servers()
+	if holder, ok := router.(routingTableHolder); ok {
+		if len(databases) == 0 {
+			databases = []string{idb.DefaultDatabase}
+		}
+		noBookmarks := func(context.Context) ([]string, error) {
+			return nil, nil
+		}
+		serverSet := collections.NewSet[string](nil)
+		for _, database := range databases {
+			table, err := holder.GetOrUpdateTable(ctx, noBookmarks, database, auth, nil)
+			if err != nil {
+				return errorutil.WrapError(err)
+			}
+			serverSet.AddAll(table.Readers)
+			serverSet.AddAll(table.Writers)
+		}
+		servers = serverSet.Values()
+	}
+	d.log.Infof(log.Driver, d.logId, "Warming up connections to %s", servers)
+	return errorutil.WrapError(driverPool.WarmUp(ctx, servers, auth))
+}
+
 func (d *driverWithContext) VerifyAuthentication(ctx context.Context, auth *AuthToken) (err error) {
 	session := d.NewSession(ctx, SessionConfig{Auth: auth, forceReAuth: true, DatabaseName: "system"})
 	defer func() {
@@ -672,7 +959,6 @@ func ExecuteQueryWithTransactionConfig(configurers ...func(*TransactionConfig))
 	}
 }
 
-
 // ExecuteQueryWithAuthToken configures neo4j.ExecuteQuery to overwrite the AuthToken for the session.
 func ExecuteQueryWithAuthToken(auth AuthToken) ExecuteQueryConfigurationOption {
 	return func(configuration *ExecuteQueryConfiguration) {
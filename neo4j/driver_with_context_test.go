@@ -22,6 +22,8 @@ import (
 	"errors"
 	"fmt"
 	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
 	"net/url"
 	"sync"
 	"sync/atomic"
@@ -468,6 +470,133 @@ func TestDriverExecuteQuery(outer *testing.T) {
 	})
 }
 
+func TestDriverMetrics(outer *testing.T) {
+	outer.Parallel()
+
+	outer.Run("reports empty metrics of new driver", func(t *testing.T) {
+		driver, err := NewDriverWithContext("bolt://localhost:7687", NoAuth())
+		AssertNoError(t, err)
+		defer driver.Close(context.Background())
+
+		driverMetrics, err := driver.Metrics()
+
+		AssertNoError(t, err)
+		AssertStringNotEmpty(t, driverMetrics.LogId)
+		AssertLen(t, driverMetrics.Pool.Servers, 0)
+		AssertIntEqual(t, driverMetrics.Pool.Waiting, 0)
+		AssertDeepEquals(t, driverMetrics.Pool.Acquired, int64(0))
+		AssertDeepEquals(t, driverMetrics.Pool.AcquisitionTime.Count, uint64(0))
+		AssertLen(t, driverMetrics.RoutingTables, 0)
+		AssertLen(t, driverMetrics.Transactions, 0)
+		AssertLen(t, driverMetrics.Queries, 0)
+	})
+
+	outer.Run("fails on closed driver", func(t *testing.T) {
+		driver, err := NewDriverWithContext("bolt://localhost:7687", NoAuth())
+		AssertNoError(t, err)
+		AssertNoError(t, driver.Close(context.Background()))
+
+		_, err = driver.Metrics()
+
+		AssertTrue(t, IsUsageError(err))
+	})
+}
+
+func TestDriverRoutingTables(outer *testing.T) {
+	outer.Parallel()
+
+	outer.Run("subscribes to changes of routing driver", func(t *testing.T) {
+		driver, err := NewDriverWithContext("neo4j://localhost:7687", NoAuth())
+		AssertNoError(t, err)
+		defer driver.Close(context.Background())
+
+		unsubscribe, err := driver.SubscribeRoutingTableChanges(func(routing.Change) {})
+
+		AssertNoError(t, err)
+		AssertNotNil(t, unsubscribe)
+		unsubscribe()
+	})
+
+	outer.Run("fails on direct driver", func(t *testing.T) {
+		driver, err := NewDriverWithContext("bolt://localhost:7687", NoAuth())
+		AssertNoError(t, err)
+		defer driver.Close(context.Background())
+
+		_, err = driver.RoutingTable(context.Background(), "neo4j")
+		AssertTrue(t, IsUsageError(err))
+		_, err = driver.SubscribeRoutingTableChanges(func(routing.Change) {})
+		AssertTrue(t, IsUsageError(err))
+	})
+
+	outer.Run("fails on closed driver", func(t *testing.T) {
+		driver, err := NewDriverWithContext("neo4j://localhost:7687", NoAuth())
+		AssertNoError(t, err)
+		AssertNoError(t, driver.Close(context.Background()))
+
+		_, err = driver.RoutingTable(context.Background(), "neo4j")
+
+		AssertTrue(t, IsUsageError(err))
+	})
+}
+
+func TestDriverWarmUp(t *testing.T) {
+	driver, err := NewDriverWithContext("neo4j://localhost:7687", NoAuth(), func(config *Config) {
+		config.MinIdleConnectionsPerServer = 2
+	})
+	AssertNoError(t, err)
+	AssertNoError(t, driver.Close(context.Background()))
+
+	err = driver.WarmUp(context.Background())
+
+	AssertTrue(t, IsUsageError(err))
+}
+
+func TestDriverShutdown(outer *testing.T) {
+	newDriver := func(t *testing.T) *driverWithContext {
+		driver, err := NewDriverWithContext("neo4j://localhost:7687", NoAuth())
+		AssertNoError(t, err)
+		return driver.(*driverWithContext)
+	}
+
+	outer.Run("closes the driver without in-flight work", func(t *testing.T) {
+		driver := newDriver(t)
+
+		AssertNoError(t, driver.Shutdown(context.Background()))
+
+		_, err := driver.Metrics()
+		AssertTrue(t, IsUsageError(err))
+		AssertNoError(t, driver.Shutdown(context.Background()))
+		AssertNoError(t, driver.Close(context.Background()))
+	})
+
+	outer.Run("refuses new sessions while shutting down", func(t *testing.T) {
+		driver := newDriver(t)
+		defer driver.Close(context.Background())
+		driver.draining = true
+
+		session := driver.NewSession(context.Background(), SessionConfig{})
+
+		_, err := session.Run(context.Background(), "RETURN 1", nil)
+		AssertErrorMessageContains(t, err, "shutting down driver")
+	})
+
+	outer.Run("waits for the shutdown in progress", func(t *testing.T) {
+		driver := newDriver(t)
+		defer driver.Close(context.Background())
+		driver.draining = true
+		driver.shutdownDone = make(chan struct{})
+		shutdownErr := &ShutdownTimeoutError{}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		AssertDeepEquals(t, driver.Shutdown(ctx), context.DeadlineExceeded)
+
+		driver.shutdownErr = shutdownErr
+		close(driver.shutdownDone)
+		AssertDeepEquals(t, driver.Shutdown(context.Background()), shutdownErr)
+	})
+}
+
 func callExecuteQueryOrBookmarkManagerGetter(driver DriverWithContext, i int) {
 	if i%2 == 0 {
 		// this lazily initializes the default bookmark manager
@@ -540,6 +669,10 @@ func (d *driverDelegate) Close(ctx context.Context) error {
 	return d.delegate.Close(ctx)
 }
 
+func (d *driverDelegate) Shutdown(ctx context.Context) error {
+	return d.delegate.Shutdown(ctx)
+}
+
 func (d *driverDelegate) IsEncrypted() bool {
 	return d.delegate.IsEncrypted()
 }
@@ -548,6 +681,22 @@ func (d *driverDelegate) GetServerInfo(ctx context.Context) (ServerInfo, error)
 	return d.delegate.GetServerInfo(ctx)
 }
 
+func (d *driverDelegate) Metrics() (metrics.DriverMetrics, error) {
+	return d.delegate.Metrics()
+}
+
+func (d *driverDelegate) RoutingTable(ctx context.Context, database string) (routing.Table, error) {
+	return d.delegate.RoutingTable(ctx, database)
+}
+
+func (d *driverDelegate) SubscribeRoutingTableChanges(listener routing.ChangeListener) (func(), error) {
+	return d.delegate.SubscribeRoutingTableChanges(listener)
+}
+
+func (d *driverDelegate) WarmUp(ctx context.Context, databases ...string) error {
+	return d.delegate.WarmUp(ctx, databases...)
+}
+
 type fakeSession struct {
 	executeReadTransactionResult   *fakeResult
 	executeReadErr                 error
@@ -47,6 +47,15 @@ type ConnectivityError = errorutil.ConnectivityError
 
 type TransactionExecutionLimit = errorutil.TransactionExecutionLimit
 
+// ConnectionAcquisitionQueueFullError is returned when a connection cannot be acquired without waiting and
+// config.Config MaxConnectionAcquisitionQueueSize acquisitions are already waiting.
+type ConnectionAcquisitionQueueFullError = errorutil.PoolQueueFull
+
+// ShutdownTimeoutError is returned by DriverWithContext.Shutdown when its context terminates before in-flight work
+// is over. InUse reports the number of connections still in use per server and Waiting the number of connection
+// acquisitions still waiting.
+type ShutdownTimeoutError = errorutil.PoolDrainTimeout
+
 type InvalidAuthenticationError struct {
 	inner error
 }
@@ -59,6 +68,31 @@ func (i *InvalidAuthenticationError) Unwrap() error {
 	return i.inner
 }
 
+// NotificationError is returned when a query result carries a notification that the configured strict policy turns
+// into an error (see config.Config's NotificationsStrictPolicy).
+type NotificationError struct {
+	// Query is the text of the query that triggered the notification.
+	Query string
+	// GqlStatusObject is the offending notification.
+	GqlStatusObject GqlStatusObject
+}
+
+func (e *NotificationError) Error() string {
+	status := e.GqlStatusObject
+	message := fmt.Sprintf("query triggered notification %s (%s): %s",
+		status.GqlStatus(), status.RawClassification(), status.StatusDescription())
+	if position := status.Position(); position != nil {
+		message = fmt.Sprintf("%s (line: %d, column: %d, offset: %d)",
+			message, position.Line(), position.Column(), position.Offset())
+	}
+	return message
+}
+
+// Position returns the position in the query the offending notification points to, nil if there is none.
+func (e *NotificationError) Position() InputPosition {
+	return e.GqlStatusObject.Position()
+}
+
 // IsNeo4jError returns true if the provided error is an instance of Neo4jError.
 func IsNeo4jError(err error) bool {
 	_, is := err.(*Neo4jError)
@@ -83,6 +117,25 @@ func IsTransactionExecutionLimit(err error) bool {
 	return is
 }
 
+// IsConnectionAcquisitionQueueFullError returns true if the provided error is an instance of
+// ConnectionAcquisitionQueueFullError.
+func IsConnectionAcquisitionQueueFullError(err error) bool {
+	_, is := err.(*ConnectionAcquisitionQueueFullError)
+	return is
+}
+
+// IsShutdownTimeoutError returns true if the provided error is an instance of ShutdownTimeoutError.
+func IsShutdownTimeoutError(err error) bool {
+	_, is := err.(*ShutdownTimeoutError)
+	return is
+}
+
+// IsNotificationError returns true if the provided error is an instance of NotificationError.
+func IsNotificationError(err error) bool {
+	_, is := err.(*NotificationError)
+	return is
+}
+
 type TokenExpiredError = errorutil.TokenExpiredError
 
 type ctxCloser interface {
@@ -87,3 +87,29 @@ func TestIsRetryable(outer *testing.T) {
 	}
 
 }
+
+func TestNotificationError(outer *testing.T) {
+	outer.Run("describes notification with its position", func(t *testing.T) {
+		err := &NotificationError{
+			Query: "MATCH (n:Persn) RETURN n",
+			GqlStatusObject: &gqlStatusObject{gqlStatusObject: &db.GqlStatusObject{
+				GqlStatus:         "01N50",
+				StatusDescription: "warn: label does not exist. The label `Persn` does not exist.",
+				Classification:    "UNRECOGNIZED",
+				Position:          &db.InputPosition{Offset: 9, Line: 1, Column: 10},
+				IsNotification:    true,
+			}},
+		}
+
+		if err.Error() != "query triggered notification 01N50 (UNRECOGNIZED): warn: label does not exist. "+
+			"The label `Persn` does not exist. (line: 1, column: 10, offset: 9)" {
+			t.Errorf("unexpected error message: %s", err.Error())
+		}
+		if err.Position().Column() != 10 {
+			t.Errorf("expected column 10, got %d", err.Position().Column())
+		}
+		if !IsNotificationError(err) {
+			t.Errorf("expected error to be a notification error")
+		}
+	})
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package events defines the listener notified of the lifecycle of individual connections and servers.
+// See config.Config's ConnectionListener.
+package events
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+)
+
+// ConnectionListener is notified of connection and server events as they happen.
+//
+// Events are delivered on the goroutine that caused them, once the connection pool has released its locks: the one
+// borrowing or returning the connection, or the pool's background maintenance for expired and idle connections.
+// Listeners may thus call back into the driver, e.g. to read its metrics, but delay the operation that caused the event
+// for as long as they run. Events of different goroutines are delivered concurrently.
+type ConnectionListener interface {
+	OnConnectionEvent(event ConnectionEvent)
+}
+
+// ConnectionListenerFunc adapts a function to the ConnectionListener interface.
+type ConnectionListenerFunc func(event ConnectionEvent)
+
+func (f ConnectionListenerFunc) OnConnectionEvent(event ConnectionEvent) {
+	f(event)
+}
+
+type ConnectionEventType string
+
+const (
+	// ConnectionCreated is emitted once a new connection has been established and authenticated
+	ConnectionCreated ConnectionEventType = "connection_created"
+	// ConnectionBorrowed is emitted when a connection is handed out by the pool
+	ConnectionBorrowed ConnectionEventType = "connection_borrowed"
+	// ConnectionReturned is emitted when a connection is given back to the pool
+	ConnectionReturned ConnectionEventType = "connection_returned"
+	// ConnectionReset is emitted after a connection has been reset, either before being given back to the pool or
+	// after having been idle for longer than the configured liveness check timeout
+	ConnectionReset ConnectionEventType = "connection_reset"
+	// ConnectionDead is emitted when a connection is found to be broken
+	ConnectionDead ConnectionEventType = "connection_dead"
+	// ConnectionClosed is emitted when the pool closes a connection
+	ConnectionClosed ConnectionEventType = "connection_closed"
+	// ServerDeactivated is emitted when a server is deactivated after a failure, either for all databases and
+	// roles (by the connection pool) or only for a specific database and role (by the router)
+	ServerDeactivated ConnectionEventType = "server_deactivated"
+)
+
+// Role is the role of a server in a routing table
+type Role string
+
+const (
+	ReaderRole Role = "reader"
+	WriterRole Role = "writer"
+)
+
+// Reasons for connections to be closed or deemed dead
+const (
+	// ExpiredReason means that the connection outlived config.Config's MaxConnectionLifetime, or that it was idle
+	// and older than another connection to the same server found dead
+	ExpiredReason = "expired"
+	// DeadReason means that the connection was closed because it was found to be broken
+	DeadReason = "dead"
+	// ServerDeactivatedReason means that the connection was closed because its server got deactivated
+	ServerDeactivatedReason = "server_deactivated"
+	// PoolClosedReason means that the connection was closed because the driver was closed
+	PoolClosedReason = "pool_closed"
+	// ReturnedDeadReason means that the connection was found broken when given back to the pool, typically after
+	// a network error
+	ReturnedDeadReason = "returned_dead"
+	// FailedResetReason means that resetting the connection failed
+	FailedResetReason = "failed_reset"
+	// FailedHealthCheckReason means that the connection failed the health check performed before borrowing it
+	FailedHealthCheckReason = "failed_health_check"
+	// IdleTimeoutReason means that the connection stayed idle for longer than config.Config's MaxConnectionIdleTime
+	IdleTimeoutReason = "idle_timeout"
+	// FailedKeepAliveReason means that the connection failed the keep-alive probe of idle connections, see
+	// config.Config's ConnectionKeepAliveInterval
+	FailedKeepAliveReason = "failed_keep_alive"
+)
+
+// ConnectionEvent describes something that happened to a connection or a server.
+type ConnectionEvent struct {
+	Type ConnectionEventType
+	Time time.Time
+	// Server is the address of the server the connection is established with, or of the deactivated server
+	Server string
+	// ConnectionId is the id assigned to the connection by the server, such as bolt-123.
+	// It is empty for ServerDeactivated events.
+	ConnectionId string
+	// Version is the Bolt protocol version negotiated for the connection.
+	// It is only set for ConnectionCreated events.
+	Version db.ProtocolVersion
+	// ServerAgent is the agent reported by the server, such as Neo4j/5.20.0.
+	// It is only set for ConnectionCreated events.
+	ServerAgent string
+	// Reason tells why the connection was closed or deemed dead, see the reason constants.
+	// It is only set for ConnectionClosed and ConnectionDead events.
+	Reason string
+	// Database is the database the server has been deactivated for.
+	// It is empty for other events and when the server has been deactivated for all databases.
+	Database string
+	// Role is the role the server has been deactivated for.
+	// It is empty for other events and when the server has been deactivated for all roles.
+	Role Role
+}
@@ -0,0 +1,329 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package health provides a net/http handler reporting the health of a driver, meant to back the liveness and
+// readiness probes (e.g. /healthz) of services using the driver.
+//
+// Unlike DriverWithContext.VerifyConnectivity, checks are bounded by a timeout and their outcome is cached, so
+// that frequent probes do not hammer the cluster.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
+)
+
+// Status is the overall health of the driver.
+type Status string
+
+const (
+	// StatusUp means that every check succeeded.
+	StatusUp Status = "up"
+	// StatusDegraded means that the driver can still serve some queries, e.g. when a database has no writer or
+	// when the connection pool is saturated.
+	StatusDegraded Status = "degraded"
+	// StatusDown means that the driver cannot reach the server or cluster, or that its credentials are invalid.
+	StatusDown Status = "down"
+)
+
+// Config holds the settings of the health checks.
+type Config struct {
+	// Databases lists the databases whose routing table is checked.
+	// The empty string stands for the home database of the user.
+	//
+	// Only relevant to drivers created with one of the neo4j:// URI schemes.
+	//
+	// default: the home database
+	Databases []string
+	// Timeout bounds the duration of every probe of a check, i.e. of every routing table fetch and of the
+	// connectivity and authentication verifications.
+	//
+	// default: 5 * time.Second
+	Timeout time.Duration
+	// CacheDuration is for how long the report of a check is served before the driver is checked again.
+	// Zero or a negative value disables caching.
+	//
+	// default: 10 * time.Second
+	CacheDuration time.Duration
+	// SkipAuthentication disables the verification of the credentials of the driver.
+	//
+	// default: false
+	SkipAuthentication bool
+}
+
+// Report is the JSON document served by Handler.
+type Report struct {
+	Status    Status    `json:"status"`
+	CheckedAt time.Time `json:"checkedAt"`
+	// Reachable tells whether the driver could connect to the server or cluster during the last check.
+	// Every check makes a round trip to a server, the routing tables cached by the driver are not deemed proof of
+	// connectivity.
+	Reachable bool `json:"reachable"`
+	// Databases holds the routing information per database, keyed by database name ("" for the home database).
+	// It is empty for drivers created with one of the bolt:// URI schemes.
+	Databases             map[string]DatabaseReport `json:"databases,omitempty"`
+	Pool                  *PoolReport               `json:"pool,omitempty"`
+	Authentication        *AuthenticationReport     `json:"authentication,omitempty"`
+	LastConnectivityError *ErrorReport              `json:"lastConnectivityError,omitempty"`
+}
+
+// DatabaseReport describes the reachability of the routers, readers and writers of a database.
+type DatabaseReport struct {
+	Routers RoleReport `json:"routers"`
+	Readers RoleReport `json:"readers"`
+	Writers RoleReport `json:"writers"`
+	// Error is the reason why the routing table could not be fetched
+	Error string `json:"error,omitempty"`
+}
+
+// RoleReport lists the servers currently holding a role in a routing table.
+// A role is available when at least one server holds it. The servers themselves are not contacted, the connectivity
+// of the cluster is reported by Report Reachable.
+type RoleReport struct {
+	Available bool     `json:"available"`
+	Servers   []string `json:"servers"`
+}
+
+// PoolReport describes the saturation of the connection pool.
+type PoolReport struct {
+	Idle             int `json:"idle"`
+	InUse            int `json:"inUse"`
+	MaxSizePerServer int `json:"maxSizePerServer"`
+	// Waiting is the number of connection acquisitions waiting for a connection to be returned to the pool
+	Waiting int `json:"waiting"`
+	// Saturation is the highest ratio of connections in use to the maximum pool size of the server, across servers.
+	// The maximum pool size of a server accounts for config.Config MaxConnectionPoolSizePerServer.
+	Saturation float64 `json:"saturation"`
+}
+
+// AuthenticationReport tells whether the credentials of the driver are valid.
+// Valid is nil when the verification could not complete, e.g. because the server could not be reached.
+type AuthenticationReport struct {
+	Valid *bool  `json:"valid,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ErrorReport is an error that occurred during a check.
+type ErrorReport struct {
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+// Handler is a http.Handler serving the health Report of a driver.
+// It responds with 200 OK when the driver is up or degraded and with 503 Service Unavailable when it is down.
+type Handler struct {
+	driver neo4j.DriverWithContext
+	config Config
+
+	mut       sync.Mutex
+	report    *Report
+	lastError *ErrorReport
+	// checking is closed when the check in progress, if any, completes
+	checking chan struct{}
+}
+
+// NewHandler creates a Handler checking the health of the given driver.
+// The default configuration can be changed with configurers, in the same way as for neo4j.NewDriverWithContext.
+func NewHandler(driver neo4j.DriverWithContext, configurers ...func(*Config)) *Handler {
+	config := Config{
+		Databases:     []string{""},
+		Timeout:       5 * time.Second,
+		CacheDuration: 10 * time.Second,
+	}
+	for _, configurer := range configurers {
+		configurer(&config)
+	}
+	if len(config.Databases) == 0 {
+		config.Databases = []string{""}
+	}
+	return &Handler{driver: driver, config: config}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	report := h.Report()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status == StatusDown {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// Report returns the health report of the driver, checking the driver again if the cached report is stale.
+// Concurrent callers share the same check.
+func (h *Handler) Report() Report {
+	h.mut.Lock()
+	if h.report != nil && itime.Now().Sub(h.report.CheckedAt) < h.config.CacheDuration {
+		report := *h.report
+		h.mut.Unlock()
+		return report
+	}
+	if checking := h.checking; checking != nil {
+		h.mut.Unlock()
+		<-checking
+		h.mut.Lock()
+		defer h.mut.Unlock()
+		return *h.report
+	}
+	checking := make(chan struct{})
+	h.checking = checking
+	h.mut.Unlock()
+
+	// the driver is checked without holding the lock, so that callers do not queue up behind slow probes
+	report, lastError := h.check()
+
+	h.mut.Lock()
+	if lastError != nil {
+		h.lastError = lastError
+	}
+	report.LastConnectivityError = h.lastError
+	h.report = &report
+	h.checking = nil
+	h.mut.Unlock()
+	close(checking)
+	return report
+}
+
+// check probes the driver and returns the report along with the last connectivity error of the probes, if any
+func (h *Handler) check() (Report, *ErrorReport) {
+	var lastError *ErrorReport
+	recordError := func(err error) {
+		lastError = &ErrorReport{Message: err.Error(), At: itime.Now()}
+	}
+	report := Report{CheckedAt: itime.Now()}
+	degraded := false
+	for _, database := range h.config.Databases {
+		table, err := h.routingTable(database)
+		if neo4j.IsUsageError(err) {
+			// routing tables are not available to direct (or closed) drivers
+			report.Databases = nil
+			break
+		}
+		if report.Databases == nil {
+			report.Databases = make(map[string]DatabaseReport, len(h.config.Databases))
+		}
+		databaseReport := DatabaseReport{
+			Routers: roleReportOf(table.Routers),
+			Readers: roleReportOf(table.Readers),
+			Writers: roleReportOf(table.Writers),
+		}
+		if err != nil {
+			recordError(err)
+			databaseReport.Error = err.Error()
+		}
+		if !databaseReport.Readers.Available || !databaseReport.Writers.Available {
+			degraded = true
+		}
+		report.Databases[database] = databaseReport
+	}
+	if driverMetrics, err := h.driver.Metrics(); err == nil {
+		report.Pool = poolReportOf(driverMetrics.Pool)
+		if report.Pool.Waiting > 0 && report.Pool.Saturation >= 1 {
+			degraded = true
+		}
+	}
+	// routing tables are served from the cache of the driver while valid, connectivity is verified separately
+	report.Reachable = h.verifyConnectivity(recordError)
+	if !h.config.SkipAuthentication && report.Reachable {
+		report.Authentication, report.Reachable = h.verifyAuthentication(recordError)
+	}
+
+	switch {
+	case !report.Reachable, report.Authentication != nil && report.Authentication.Valid != nil && !*report.Authentication.Valid:
+		report.Status = StatusDown
+	case degraded:
+		report.Status = StatusDegraded
+	default:
+		report.Status = StatusUp
+	}
+	return report, lastError
+}
+
+func (h *Handler) verifyConnectivity(recordError func(error)) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
+	defer cancel()
+	if err := h.driver.VerifyConnectivity(ctx); err != nil {
+		recordError(err)
+		return false
+	}
+	return true
+}
+
+func (h *Handler) routingTable(database string) (routing.Table, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
+	defer cancel()
+	return h.driver.RoutingTable(ctx, database)
+}
+
+// verifyAuthentication verifies the credentials of the driver, and tells whether the server could be reached
+func (h *Handler) verifyAuthentication(recordError func(error)) (*AuthenticationReport, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
+	defer cancel()
+	err := h.driver.VerifyAuthentication(ctx, nil)
+	if err == nil {
+		valid := true
+		return &AuthenticationReport{Valid: &valid}, true
+	}
+	var authErr *neo4j.InvalidAuthenticationError
+	if errors.As(err, &authErr) {
+		valid := false
+		return &AuthenticationReport{Valid: &valid}, true
+	}
+	if neo4j.IsConnectivityError(err) {
+		recordError(err)
+		return &AuthenticationReport{Error: err.Error()}, false
+	}
+	return &AuthenticationReport{Error: err.Error()}, true
+}
+
+func roleReportOf(servers []string) RoleReport {
+	sorted := make([]string, len(servers))
+	copy(sorted, servers)
+	sort.Strings(sorted)
+	return RoleReport{Available: len(servers) > 0, Servers: sorted}
+}
+
+func poolReportOf(poolMetrics metrics.PoolMetrics) *PoolReport {
+	report := &PoolReport{MaxSizePerServer: poolMetrics.MaxSizePerServer, Waiting: poolMetrics.Waiting}
+	for _, serverMetrics := range poolMetrics.Servers {
+		report.Idle += serverMetrics.Idle
+		report.InUse += serverMetrics.InUse
+		maxSize := serverMetrics.MaxSize
+		if maxSize <= 0 {
+			maxSize = poolMetrics.MaxSizePerServer
+		}
+		if maxSize > 0 {
+			saturation := float64(serverMetrics.InUse) / float64(maxSize)
+			if saturation > report.Saturation {
+				report.Saturation = saturation
+			}
+		}
+	}
+	return report
+}
@@ -0,0 +1,247 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
+)
+
+type fakeDriver struct {
+	neo4j.DriverWithContext
+	tables          map[string]routing.Table
+	routingErr      error
+	connectivityErr error
+	authErr         error
+	metrics         metrics.DriverMetrics
+	routingCalls    int
+	routingHook     func()
+}
+
+func (d *fakeDriver) RoutingTable(_ context.Context, database string) (routing.Table, error) {
+	d.routingCalls++
+	if d.routingHook != nil {
+		d.routingHook()
+	}
+	if d.routingErr != nil {
+		return routing.Table{}, d.routingErr
+	}
+	return d.tables[database], nil
+}
+
+func (d *fakeDriver) VerifyConnectivity(context.Context) error {
+	return d.connectivityErr
+}
+
+func (d *fakeDriver) VerifyAuthentication(context.Context, *neo4j.AuthToken) error {
+	return d.authErr
+}
+
+func (d *fakeDriver) Metrics() (metrics.DriverMetrics, error) {
+	return d.metrics, nil
+}
+
+var cluster = map[string]routing.Table{
+	"": {Routers: []string{"r2", "r1"}, Readers: []string{"r1", "r2"}, Writers: []string{"w1"}},
+}
+
+func TestHandler(outer *testing.T) {
+	outer.Parallel()
+
+	serve := func(t *testing.T, handler *Handler) (int, Report) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		var report Report
+		if err := json.Unmarshal(recorder.Body.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		return recorder.Code, report
+	}
+
+	outer.Run("reports healthy cluster", func(t *testing.T) {
+		driver := &fakeDriver{tables: cluster, metrics: metrics.DriverMetrics{Pool: metrics.PoolMetrics{
+			MaxSizePerServer: 10,
+			Servers:          map[string]metrics.ServerMetrics{"r1": {Idle: 1, InUse: 5}, "w1": {InUse: 2}},
+		}}}
+
+		code, report := serve(t, NewHandler(driver))
+
+		if code != http.StatusOK || report.Status != StatusUp || !report.Reachable {
+			t.Errorf("unexpected report %d %+v", code, report)
+		}
+		expected := DatabaseReport{
+			Routers: RoleReport{Available: true, Servers: []string{"r1", "r2"}},
+			Readers: RoleReport{Available: true, Servers: []string{"r1", "r2"}},
+			Writers: RoleReport{Available: true, Servers: []string{"w1"}},
+		}
+		if !reflect.DeepEqual(report.Databases[""], expected) {
+			t.Errorf("unexpected database report %+v", report.Databases[""])
+		}
+		if *report.Pool != (PoolReport{Idle: 1, InUse: 7, MaxSizePerServer: 10, Saturation: 0.5}) {
+			t.Errorf("unexpected pool report %+v", report.Pool)
+		}
+		if report.Authentication == nil || report.Authentication.Valid == nil || !*report.Authentication.Valid {
+			t.Errorf("expected valid authentication, got %+v", report.Authentication)
+		}
+	})
+
+	outer.Run("computes saturation against the maximum pool size of every server", func(t *testing.T) {
+		driver := &fakeDriver{tables: cluster, metrics: metrics.DriverMetrics{Pool: metrics.PoolMetrics{
+			MaxSizePerServer: 10,
+			Servers: map[string]metrics.ServerMetrics{
+				"r1": {InUse: 5, MaxSize: 10},
+				"w1": {InUse: 2, MaxSize: 2},
+			},
+		}}}
+
+		_, report := serve(t, NewHandler(driver))
+
+		if report.Pool.Saturation != 1 {
+			t.Errorf("expected saturated pool, got %+v", report.Pool)
+		}
+	})
+
+	outer.Run("reports degraded cluster without writer", func(t *testing.T) {
+		driver := &fakeDriver{tables: map[string]routing.Table{
+			"movies": {Routers: []string{"r1"}, Readers: []string{"r1"}},
+		}}
+
+		code, report := serve(t, NewHandler(driver, func(config *Config) {
+			config.Databases = []string{"movies"}
+		}))
+
+		if code != http.StatusOK || report.Status != StatusDegraded || report.Databases["movies"].Writers.Available {
+			t.Errorf("unexpected report %d %+v", code, report)
+		}
+	})
+
+	outer.Run("reports unreachable cluster", func(t *testing.T) {
+		noRoute := errors.New("no route")
+		driver := &fakeDriver{routingErr: noRoute, connectivityErr: noRoute}
+
+		code, report := serve(t, NewHandler(driver))
+
+		if code != http.StatusServiceUnavailable || report.Status != StatusDown || report.Authentication != nil {
+			t.Errorf("unexpected report %d %+v", code, report)
+		}
+		if report.LastConnectivityError == nil || report.LastConnectivityError.Message != "no route" {
+			t.Errorf("expected last connectivity error, got %+v", report.LastConnectivityError)
+		}
+	})
+
+	outer.Run("reports unreachable cluster while its routing table is still valid", func(t *testing.T) {
+		driver := &fakeDriver{tables: cluster, connectivityErr: errors.New("refused")}
+
+		code, report := serve(t, NewHandler(driver, func(config *Config) {
+			config.SkipAuthentication = true
+		}))
+
+		if code != http.StatusServiceUnavailable || report.Status != StatusDown || report.Reachable {
+			t.Errorf("unexpected report %d %+v", code, report)
+		}
+		if report.LastConnectivityError == nil || report.LastConnectivityError.Message != "refused" {
+			t.Errorf("expected last connectivity error, got %+v", report.LastConnectivityError)
+		}
+	})
+
+	outer.Run("reports unreachable cluster when authentication cannot be verified", func(t *testing.T) {
+		driver := &fakeDriver{tables: cluster, authErr: &neo4j.ConnectivityError{Inner: errors.New("connection reset")}}
+
+		code, report := serve(t, NewHandler(driver))
+
+		if code != http.StatusServiceUnavailable || report.Status != StatusDown || report.Reachable {
+			t.Errorf("unexpected report %d %+v", code, report)
+		}
+		if report.Authentication == nil || report.Authentication.Valid != nil {
+			t.Errorf("expected unverified authentication, got %+v", report.Authentication)
+		}
+	})
+
+	outer.Run("reports invalid credentials", func(t *testing.T) {
+		driver := &fakeDriver{tables: cluster, authErr: &neo4j.InvalidAuthenticationError{}}
+
+		code, report := serve(t, NewHandler(driver))
+
+		if code != http.StatusServiceUnavailable || report.Status != StatusDown {
+			t.Errorf("unexpected report %d %+v", code, report)
+		}
+		if report.Authentication.Valid == nil || *report.Authentication.Valid {
+			t.Errorf("expected invalid authentication, got %+v", report.Authentication)
+		}
+	})
+
+	outer.Run("checks connectivity of direct driver", func(t *testing.T) {
+		driver := &fakeDriver{
+			routingErr:      &neo4j.UsageError{Message: "direct"},
+			connectivityErr: errors.New("refused"),
+		}
+
+		code, report := serve(t, NewHandler(driver, func(config *Config) {
+			config.SkipAuthentication = true
+		}))
+
+		if code != http.StatusServiceUnavailable || report.Databases != nil || report.LastConnectivityError.Message != "refused" {
+			t.Errorf("unexpected report %d %+v", code, report)
+		}
+	})
+
+	outer.Run("caches reports", func(t *testing.T) {
+		driver := &fakeDriver{tables: cluster}
+		handler := NewHandler(driver)
+
+		handler.Report()
+		handler.Report()
+
+		if driver.routingCalls != 1 {
+			t.Errorf("expected a single check, got %d", driver.routingCalls)
+		}
+	})
+
+	outer.Run("shares checks between concurrent callers", func(t *testing.T) {
+		checking := make(chan struct{})
+		release := make(chan struct{})
+		driver := &fakeDriver{tables: cluster, routingHook: func() {
+			close(checking)
+			<-release
+		}}
+		handler := NewHandler(driver)
+		reports := make(chan Report, 2)
+
+		go func() { reports <- handler.Report() }()
+		<-checking
+		go func() { reports <- handler.Report() }()
+		close(release)
+
+		first, second := <-reports, <-reports
+		if driver.routingCalls != 1 {
+			t.Errorf("expected a single check, got %d", driver.routingCalls)
+		}
+		if first.Status != StatusUp || !reflect.DeepEqual(first, second) {
+			t.Errorf("expected the same healthy report, got %+v and %+v", first, second)
+		}
+	})
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"container/list"
+	"context"
+	"sync"
+
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+)
+
+// homeDatabaseCache holds the home databases resolved by the sessions of a driver, so that sessions without
+// DatabaseName do not each fetch a routing table to resolve theirs.
+// The least recently used entries are evicted beyond maxCachedHomeDatabases.
+// A nil *homeDatabaseCache holds nothing. Thread safe.
+type homeDatabaseCache struct {
+	entries map[homeDatabaseKey]*list.Element
+	lru     *list.List // of *homeDatabaseEntry, most recently used first
+	maxSize int
+	mut     sync.Mutex
+}
+
+// maxCachedHomeDatabases bounds the home databases cached per driver, as sessions may impersonate any number of users
+const maxCachedHomeDatabases = 1000
+
+type homeDatabaseEntry struct {
+	key      homeDatabaseKey
+	database string
+}
+
+// homeDatabaseKey identifies whose home database is cached: the principal of the driver credentials, or of the
+// session credentials, possibly impersonating another user.
+type homeDatabaseKey struct {
+	fromSession      bool
+	principal        string
+	impersonatedUser string
+}
+
+func newHomeDatabaseCache() *homeDatabaseCache {
+	return &homeDatabaseCache{
+		entries: make(map[homeDatabaseKey]*list.Element),
+		lru:     list.New(),
+		maxSize: maxCachedHomeDatabases,
+	}
+}
+
+// homeDatabaseKeyOf returns the key the home database of the session is cached under, if it can be cached.
+// The principal is taken from the session credentials, or else from the current token of the driver credentials, so
+// that a token manager rotating the driver credentials to another user does not reuse the home database of the
+// previous one. It cannot be cached for credentials without a principal, such as bearer tokens.
+func homeDatabaseKeyOf(ctx context.Context, config SessionConfig, token *idb.ReAuthToken) (homeDatabaseKey, bool) {
+	if config.Auth != nil {
+		principal, ok := principalOf(config.Auth.Tokens)
+		if !ok {
+			return homeDatabaseKey{}, false
+		}
+		return homeDatabaseKey{fromSession: true, principal: principal, impersonatedUser: config.ImpersonatedUser}, true
+	}
+	if token == nil || token.Manager == nil {
+		return homeDatabaseKey{impersonatedUser: config.ImpersonatedUser}, true
+	}
+	driverToken, err := token.Manager.GetAuthToken(ctx)
+	if err != nil {
+		return homeDatabaseKey{}, false
+	}
+	principal, ok := principalOf(driverToken.Tokens)
+	if !ok {
+		return homeDatabaseKey{}, false
+	}
+	return homeDatabaseKey{principal: principal, impersonatedUser: config.ImpersonatedUser}, true
+}
+
+// principalOf returns the user the credentials authenticate, which is nobody without authentication
+func principalOf(tokens map[string]any) (string, bool) {
+	if tokens["scheme"] == "none" {
+		return "", true
+	}
+	principal, ok := tokens["principal"].(string)
+	return principal, ok
+}
+
+func (c *homeDatabaseCache) get(key homeDatabaseKey) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	element, found := c.entries[key]
+	if !found {
+		return "", false
+	}
+	c.lru.MoveToFront(element)
+	return element.Value.(*homeDatabaseEntry).database, true
+}
+
+func (c *homeDatabaseCache) set(key homeDatabaseKey, database string) {
+	if c == nil || database == "" {
+		return
+	}
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if element, found := c.entries[key]; found {
+		element.Value.(*homeDatabaseEntry).database = database
+		c.lru.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&homeDatabaseEntry{key: key, database: database})
+	if c.lru.Len() > c.maxSize {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*homeDatabaseEntry).key)
+	}
+}
+
+// invalidate removes the cached home database, unless it has been replaced with another database in the meantime
+func (c *homeDatabaseCache) invalidate(key homeDatabaseKey, database string) {
+	if c == nil {
+		return
+	}
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if element, found := c.entries[key]; found && element.Value.(*homeDatabaseEntry).database == database {
+		c.lru.Remove(element)
+		delete(c.entries, key)
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	"testing"
+)
+
+func TestHomeDatabaseCache(outer *testing.T) {
+	jane := homeDatabaseKey{impersonatedUser: "jane"}
+	john := homeDatabaseKey{impersonatedUser: "john"}
+	joe := homeDatabaseKey{impersonatedUser: "joe"}
+
+	outer.Run("evicts least recently used home databases", func(t *testing.T) {
+		cache := newHomeDatabaseCache()
+		cache.maxSize = 2
+		cache.set(jane, "janedb")
+		cache.set(john, "johndb")
+		_, _ = cache.get(jane)
+
+		cache.set(joe, "joedb")
+
+		_, found := cache.get(john)
+		AssertFalse(t, found)
+		database, _ := cache.get(jane)
+		AssertStringEqual(t, database, "janedb")
+		database, _ = cache.get(joe)
+		AssertStringEqual(t, database, "joedb")
+	})
+
+	outer.Run("replaces home databases without evicting", func(t *testing.T) {
+		cache := newHomeDatabaseCache()
+		cache.maxSize = 2
+		cache.set(jane, "janedb")
+		cache.set(john, "johndb")
+
+		cache.set(jane, "otherdb")
+
+		database, _ := cache.get(jane)
+		AssertStringEqual(t, database, "otherdb")
+		database, _ = cache.get(john)
+		AssertStringEqual(t, database, "johndb")
+	})
+
+	outer.Run("invalidates only the given home database", func(t *testing.T) {
+		cache := newHomeDatabaseCache()
+		cache.set(jane, "janedb")
+
+		cache.invalidate(jane, "otherdb")
+		_, found := cache.get(jane)
+		AssertTrue(t, found)
+
+		cache.invalidate(jane, "janedb")
+		_, found = cache.get(jane)
+		AssertFalse(t, found)
+	})
+}
@@ -155,6 +155,10 @@ func (b *bolt3) ServerVersion() string {
 	return b.serverVersion
 }
 
+func (b *bolt3) ConnectionId() string {
+	return b.connId
+}
+
 // Sets b.err and b.state on failure
 func (b *bolt3) receiveMsg(ctx context.Context) any {
 	msg, err := b.in.next(ctx, b.conn)
@@ -694,6 +698,10 @@ func (b *bolt3) HasFailed() bool {
 	return b.state == bolt3_failed
 }
 
+func (b *bolt3) NeedsReset() bool {
+	return b.state != bolt3_ready && b.state != bolt3_dead
+}
+
 func (b *bolt3) Birthdate() time.Time {
 	return b.birthDate
 }
@@ -178,6 +178,10 @@ func (b *bolt4) ServerVersion() string {
 	return b.serverVersion
 }
 
+func (b *bolt4) ConnectionId() string {
+	return b.connId
+}
+
 // Sets b.err and b.state to bolt4_failed or bolt4_dead when fatal is true.
 func (b *bolt4) setError(err error, fatal bool) {
 	// Has no effect, can reduce nested ifs
@@ -783,6 +787,10 @@ func (b *bolt4) HasFailed() bool {
 	return b.state == bolt4_failed
 }
 
+func (b *bolt4) NeedsReset() bool {
+	return b.state != bolt4_ready && b.state != bolt4_dead
+}
+
 func (b *bolt4) Birthdate() time.Time {
 	return b.birthDate
 }
@@ -182,6 +182,10 @@ func (b *bolt5) ServerVersion() string {
 	return b.serverVersion
 }
 
+func (b *bolt5) ConnectionId() string {
+	return b.connId
+}
+
 // Sets b.err and b.state to bolt5Failed or bolt5Dead when fatal is true.
 func (b *bolt5) setError(err error, fatal bool) {
 	// Has no effect, can reduce nested ifs
@@ -769,6 +773,10 @@ func (b *bolt5) HasFailed() bool {
 	return b.state == bolt5Failed
 }
 
+func (b *bolt5) NeedsReset() bool {
+	return b.state != bolt5Ready && b.state != bolt5Dead
+}
+
 func (b *bolt5) Birthdate() time.Time {
 	return b.birthDate
 }
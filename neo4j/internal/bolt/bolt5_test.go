@@ -497,10 +497,12 @@ func TestBolt5(outer *testing.T) {
 		skeys, _ := bolt.Keys(str)
 		assertKeys(t, runKeys, skeys)
 		assertBoltState(t, bolt5Streaming, bolt)
+		AssertTrue(t, bolt.NeedsReset())
 
 		// Retrieve the records
 		assertRunResponseOk(t, bolt, str)
 		assertBoltState(t, bolt5Ready, bolt)
+		AssertFalse(t, bolt.NeedsReset())
 	})
 
 	outer.Run("Run auto-commit with impersonation", func(t *testing.T) {
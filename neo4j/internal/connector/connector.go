@@ -24,12 +24,15 @@ import (
 	"errors"
 	"io"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/events"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/bolt"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+	ievents "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/events"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
 )
 
@@ -41,6 +44,29 @@ type Connector struct {
 	Network          string
 	Config           *config.Config
 	SupplyConnection func(context.Context, string) (net.Conn, error)
+	ServerNames      *ServerNames
+}
+
+// ServerNames records the host names of the servers dialed by IP address, e.g. after resolving the host name with
+// the resolver package, so that TLS verifies their certificates against the host name rather than the IP address.
+type ServerNames struct {
+	names sync.Map
+}
+
+// Register records the host name the server at address, an IP address and port, is known under.
+func (n *ServerNames) Register(address, hostName string) {
+	n.names.Store(address, hostName)
+}
+
+func (n *ServerNames) lookup(address string) (string, bool) {
+	if n == nil {
+		return "", false
+	}
+	hostName, found := n.names.Load(address)
+	if !found {
+		return "", false
+	}
+	return hostName.(string), true
 }
 
 func (c Connector) Connect(
@@ -79,7 +105,7 @@ func (c Connector) Connect(
 		connection, err := bolt.Connect(
 			ctx,
 			address,
-			conn,
+			c.capture(conn, address),
 			auth,
 			c.Config.UserAgent,
 			c.RoutingContext,
@@ -92,11 +118,12 @@ func (c Connector) Connect(
 		if err != nil {
 			return nil, err
 		}
+		c.notifyCreated(connection)
 		return connection, nil
 	}
 
 	// TLS requested, continue with handshake
-	serverName, _, err := net.SplitHostPort(address)
+	serverName, err := c.serverNameOf(address)
 	if err != nil {
 		errorListener.OnDialError(ctx, address, err)
 		return nil, err
@@ -116,7 +143,7 @@ func (c Connector) Connect(
 	connection, err = bolt.Connect(
 		ctx,
 		address,
-		tlsConn,
+		c.capture(tlsConn, address),
 		auth,
 		c.Config.UserAgent,
 		c.RoutingContext,
@@ -129,9 +156,19 @@ func (c Connector) Connect(
 	if err != nil {
 		return nil, err
 	}
+	c.notifyCreated(connection)
 	return
 }
 
+// serverNameOf returns the name TLS verifies the certificate of the server at address against
+func (c Connector) serverNameOf(address string) (string, error) {
+	if hostName, found := c.ServerNames.lookup(address); found {
+		return hostName, nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	return host, err
+}
+
 func (c Connector) createConnection(ctx context.Context, address string) (net.Conn, error) {
 	dialer := net.Dialer{Timeout: c.Config.SocketConnectTimeout}
 	if !c.Config.SocketKeepalive {
@@ -141,6 +178,27 @@ func (c Connector) createConnection(ctx context.Context, address string) (net.Co
 	return dialer.DialContext(ctx, c.Network, address)
 }
 
+func (c Connector) notifyCreated(connection db.Connection) {
+	ievents.Notify(c.Config.ConnectionListener, events.ConnectionEvent{
+		Type:         events.ConnectionCreated,
+		Server:       connection.ServerName(),
+		ConnectionId: connection.ConnectionId(),
+		Version:      connection.Version(),
+		ServerAgent:  connection.ServerVersion(),
+	})
+}
+
+// capture records the traffic of the given connection, if selected for capture
+func (c Connector) capture(conn net.Conn, address string) net.Conn {
+	if c.Config.BoltCapture == nil {
+		return conn
+	}
+	if c.Config.BoltCaptureFilter != nil && !c.Config.BoltCaptureFilter(address) {
+		return conn
+	}
+	return c.Config.BoltCapture.Capture(conn, address)
+}
+
 func (c Connector) tlsConfig(serverName string) *tls.Config {
 	var config *tls.Config
 	if c.Config.TlsConfig != nil {
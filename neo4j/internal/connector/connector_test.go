@@ -85,6 +85,37 @@ func TestConnect(outer *testing.T) {
 		AssertError(t, err)
 		AssertTrue(t, connectionDelegate.Closed)
 	})
+
+	outer.Run("verifies the certificate of servers dialed by IP address against their host name", func(t *testing.T) {
+		serverNames := make(chan string, 2)
+		supply := func(context.Context, string) (net.Conn, error) {
+			client, server := net.Pipe()
+			go func() {
+				// Fails the handshake once the server name sent by the client is known
+				_ = tls.Server(server, &tls.Config{GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+					serverNames <- hello.ServerName
+					return nil, io.EOF
+				}}).Handshake()
+				_ = server.Close()
+			}()
+			return client, nil
+		}
+		names := &connector.ServerNames{}
+		names.Register("10.0.0.1:7687", "example.com")
+		connector := &connector.Connector{
+			SupplyConnection: supply,
+			Config:           &config.Config{},
+			ServerNames:      names,
+		}
+
+		_, err := connector.Connect(ctx, "10.0.0.1:7687", nil, noopErrorListener{}, nil)
+		AssertError(t, err)
+		AssertStringEqual(t, <-serverNames, "example.com")
+
+		_, err = connector.Connect(ctx, "other.example.com:7687", nil, noopErrorListener{}, nil)
+		AssertError(t, err)
+		AssertStringEqual(t, <-serverNames, "other.example.com")
+	})
 }
 
 type Provider struct {
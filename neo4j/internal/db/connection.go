@@ -126,6 +126,8 @@ type Connection interface {
 	ServerName() string
 	// ServerVersion returns the server version on pattern Neo4j/1.2.3
 	ServerVersion() string
+	// ConnectionId returns the id assigned to the connection by the server, such as bolt-123
+	ConnectionId() string
 	// IsAlive returns true if the connection is fully functional.
 	// Implementation of this should be passive, no pinging or similar since it might be
 	// called rather frequently.
@@ -183,6 +185,13 @@ type RoutingTable struct {
 // Marker for using the default database instance.
 const DefaultDatabase = ""
 
+// ResetStateReporter is implemented by connections able to tell whether Reset involves a round trip to the server.
+type ResetStateReporter interface {
+	// NeedsReset tells whether Reset has to send a RESET message and wait for the server response, i.e. whether the
+	// connection is neither ready for a new transaction nor dead.
+	NeedsReset() bool
+}
+
 // DatabaseSelector allows to select a database if the database server connection supports selecting which database instance on the server
 // to connect to. Prior to Neo4j 4 there was only one database per server.
 type DatabaseSelector interface {
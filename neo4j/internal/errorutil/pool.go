@@ -17,7 +17,11 @@
 
 package errorutil
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 type PoolTimeout struct {
 	Err     error
@@ -36,6 +40,49 @@ func (e *PoolFull) Error() string {
 	return fmt.Sprintf("No idle connections on any of [%s]", e.Servers)
 }
 
+type PoolQueueFull struct {
+	Servers   []string
+	QueueSize int
+}
+
+func (e *PoolQueueFull) Error() string {
+	return fmt.Sprintf("No idle connections on any of [%s] and already %d connection acquisitions waiting", e.Servers, e.QueueSize)
+}
+
+type PoolCircuitOpen struct {
+	Servers []string
+}
+
+func (e *PoolCircuitOpen) Error() string {
+	return fmt.Sprintf("Circuit breaker open for all of [%s]", e.Servers)
+}
+
+type PoolDrainTimeout struct {
+	// InUse holds the number of connections still in use, keyed by server address
+	InUse map[string]int
+	// Waiting is the number of connection acquisitions still waiting
+	Waiting int
+	Err     error
+}
+
+func (e *PoolDrainTimeout) Error() string {
+	servers := make([]string, 0, len(e.InUse))
+	for server := range e.InUse {
+		servers = append(servers, server)
+	}
+	sort.Strings(servers)
+	inUse := make([]string, len(servers))
+	for i, server := range servers {
+		inUse[i] = fmt.Sprintf("%s: %d", server, e.InUse[server])
+	}
+	return fmt.Sprintf("Deadline reached while draining the pool, with connections still in use on [%s] and %d connection acquisitions waiting: %s",
+		strings.Join(inUse, ", "), e.Waiting, e.Err)
+}
+
+func (e *PoolDrainTimeout) Unwrap() error {
+	return e.Err
+}
+
 type PoolClosed struct {
 }
 
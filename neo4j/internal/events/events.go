@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package events simplifies the calls to the user-provided events.ConnectionListener.
+package events
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/events"
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+)
+
+// Notify timestamps the event and passes it to the listener, if set.
+func Notify(listener events.ConnectionListener, event events.ConnectionEvent) {
+	if listener == nil {
+		return
+	}
+	event.Time = itime.Now()
+	listener.OnConnectionEvent(event)
+}
+
+// NotifyConnection notifies the listener, if set, of an event of the given connection.
+func NotifyConnection(listener events.ConnectionListener, eventType events.ConnectionEventType, connection idb.Connection, reason string) {
+	if listener == nil {
+		return
+	}
+	Notify(listener, connectionEvent(eventType, connection, reason))
+}
+
+// Pending holds back the events occurring while locks are held, so that the listener, which may be slow or call back
+// into the driver, is notified once the locks are released, see Flush.
+// A nil *Pending drops events. Not thread safe.
+type Pending struct {
+	listener events.ConnectionListener
+	events   []events.ConnectionEvent
+}
+
+func NewPending(listener events.ConnectionListener) *Pending {
+	return &Pending{listener: listener}
+}
+
+// AddConnection timestamps an event of the given connection, to be passed to the listener by Flush.
+func (p *Pending) AddConnection(eventType events.ConnectionEventType, connection idb.Connection, reason string) {
+	if p == nil || p.listener == nil {
+		return
+	}
+	event := connectionEvent(eventType, connection, reason)
+	event.Time = itime.Now()
+	p.events = append(p.events, event)
+}
+
+// Flush passes the pending events to the listener, in the order they occurred.
+// Must be called without holding the locks the events were collected under.
+func (p *Pending) Flush() {
+	if p == nil {
+		return
+	}
+	for _, event := range p.events {
+		p.listener.OnConnectionEvent(event)
+	}
+	p.events = nil
+}
+
+func connectionEvent(eventType events.ConnectionEventType, connection idb.Connection, reason string) events.ConnectionEvent {
+	return events.ConnectionEvent{
+		Type:         eventType,
+		Server:       connection.ServerName(),
+		ConnectionId: connection.ConnectionId(),
+		Reason:       reason,
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics contains the building blocks of the driver statistics.
+package metrics
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+)
+
+// DefaultBounds are the upper bounds of the buckets of the duration histograms
+var DefaultBounds = []time.Duration{
+	1 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	1 * time.Minute,
+}
+
+// Histogram accumulates durations into DefaultBounds buckets.
+// The zero value is ready to use.
+// Not thread safe
+type Histogram struct {
+	counts []uint64
+	count  uint64
+	sum    time.Duration
+}
+
+func (h *Histogram) Observe(duration time.Duration) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(DefaultBounds))
+	}
+	for i, bound := range DefaultBounds {
+		if duration <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += duration
+}
+
+func (h *Histogram) Snapshot() metrics.Histogram {
+	counts := make([]uint64, len(DefaultBounds))
+	copy(counts, h.counts)
+	return metrics.Histogram{
+		Bounds: append([]time.Duration(nil), DefaultBounds...),
+		Counts: counts,
+		Count:  h.count,
+		Sum:    h.sum,
+	}
+}
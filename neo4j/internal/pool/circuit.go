@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+)
+
+// Circuit breaker of a server, see config.Config CircuitBreakerFailureThreshold.
+// Kept in the server statistics so that it outlives the deactivation of the server.
+// Not thread safe, guarded by the pool's server lock.
+type circuitBreaker struct {
+	state       metrics.CircuitState
+	windowStart time.Time
+	failures    int
+	successes   int
+	openedAt    time.Time
+	// probes let through and probes that succeeded while half-open
+	probes         int
+	probeSuccesses int
+	openings       int64
+}
+
+func (b *circuitBreaker) currentState() metrics.CircuitState {
+	if b.state == "" {
+		return metrics.CircuitClosed
+	}
+	return b.state
+}
+
+// admits tells whether connections can be acquired from the server, moving from open to half-open once the cool-down
+// has elapsed.
+func (b *circuitBreaker) admits(now time.Time, conf *config.Config) bool {
+	switch b.currentState() {
+	case metrics.CircuitOpen:
+		if now.Sub(b.openedAt) < conf.CircuitBreakerCoolDown {
+			return false
+		}
+		b.state = metrics.CircuitHalfOpen
+		b.probes = 0
+		b.probeSuccesses = 0
+		return true
+	case metrics.CircuitHalfOpen:
+		return b.probes < conf.CircuitBreakerHalfOpenProbes
+	}
+	return true
+}
+
+// coolingDown tells whether the circuit is open and its cool-down has not elapsed yet.
+func (b *circuitBreaker) coolingDown(now time.Time, conf *config.Config) bool {
+	return b.currentState() == metrics.CircuitOpen && now.Sub(b.openedAt) < conf.CircuitBreakerCoolDown
+}
+
+// takeProbe reserves a probe when half-open, returns false when no probe is left.
+func (b *circuitBreaker) takeProbe(conf *config.Config) bool {
+	if b.currentState() != metrics.CircuitHalfOpen {
+		return true
+	}
+	if b.probes >= conf.CircuitBreakerHalfOpenProbes {
+		return false
+	}
+	b.probes++
+	return true
+}
+
+// releaseProbe gives back a probe reserved by takeProbe, when the outcome of the probe is unknown.
+func (b *circuitBreaker) releaseProbe() {
+	if b.currentState() == metrics.CircuitHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+func (b *circuitBreaker) onSuccess(now time.Time, conf *config.Config) {
+	switch b.currentState() {
+	case metrics.CircuitHalfOpen:
+		b.probeSuccesses++
+		if b.probeSuccesses >= conf.CircuitBreakerHalfOpenProbes {
+			b.close(now)
+		}
+	case metrics.CircuitClosed:
+		b.slideWindow(now, conf)
+		b.successes++
+	}
+}
+
+func (b *circuitBreaker) onFailure(now time.Time, conf *config.Config) {
+	switch b.currentState() {
+	case metrics.CircuitHalfOpen:
+		b.open(now)
+	case metrics.CircuitClosed:
+		b.slideWindow(now, conf)
+		b.failures++
+		failureRate := float64(b.failures) / float64(b.failures+b.successes)
+		if b.failures >= conf.CircuitBreakerFailureThreshold && failureRate >= conf.CircuitBreakerFailureRate {
+			b.open(now)
+		}
+	}
+}
+
+func (b *circuitBreaker) slideWindow(now time.Time, conf *config.Config) {
+	if now.Sub(b.windowStart) >= conf.CircuitBreakerWindow {
+		b.windowStart = now
+		b.failures = 0
+		b.successes = 0
+	}
+}
+
+func (b *circuitBreaker) open(now time.Time) {
+	b.state = metrics.CircuitOpen
+	b.openedAt = now
+	b.openings++
+}
+
+func (b *circuitBreaker) close(now time.Time) {
+	b.state = metrics.CircuitClosed
+	b.windowStart = now
+	b.failures = 0
+	b.successes = 0
+}
+
+func (p *Pool) circuitBreakersEnabled() bool {
+	return p.config.CircuitBreakerFailureThreshold > 0
+}
+
+// admittedServers leaves out the servers whose circuit breaker is open, or half-open without probes left.
+// Fails when all servers are left out.
+func (p *Pool) admittedServers(serverNames []string) ([]string, error) {
+	if !p.circuitBreakersEnabled() {
+		return serverNames, nil
+	}
+	p.serversMut.Lock()
+	defer p.serversMut.Unlock()
+	now := itime.Now()
+	admitted := make([]string, 0, len(serverNames))
+	for _, serverName := range serverNames {
+		breaker := &p.statsOf(serverName).circuit
+		previousState := breaker.currentState()
+		if breaker.admits(now, p.config) {
+			admitted = append(admitted, serverName)
+		}
+		p.logCircuitTransition(serverName, previousState, breaker.currentState())
+	}
+	if len(admitted) == 0 {
+		return nil, &errorutil.PoolCircuitOpen{Servers: serverNames}
+	}
+	return admitted, nil
+}
+
+// Must be called while holding the server lock
+func (p *Pool) circuitClosed(serverName string) bool {
+	stats := p.stats[serverName]
+	return stats == nil || stats.circuit.currentState() == metrics.CircuitClosed
+}
+
+// Must be called while holding the server lock
+func (p *Pool) takeCircuitProbe(serverName string) bool {
+	if !p.circuitBreakersEnabled() {
+		return true
+	}
+	return p.statsOf(serverName).circuit.takeProbe(p.config)
+}
+
+// Must be called while holding the server lock
+func (p *Pool) releaseCircuitProbe(serverName string) {
+	if !p.circuitBreakersEnabled() {
+		return
+	}
+	p.statsOf(serverName).circuit.releaseProbe()
+}
+
+// onCircuitReturn counts connections given back to the pool in a healthy state as successes.
+// Connections given back broken already counted as failures through OnIoError, unless they were probes broken for
+// another reason, which then count as failed probes.
+func (p *Pool) onCircuitReturn(serverName string, healthy bool) {
+	if !p.circuitBreakersEnabled() {
+		return
+	}
+	p.serversMut.Lock()
+	defer p.serversMut.Unlock()
+	breaker := &p.statsOf(serverName).circuit
+	previousState := breaker.currentState()
+	now := itime.Now()
+	if healthy {
+		breaker.onSuccess(now, p.config)
+	} else if previousState == metrics.CircuitHalfOpen {
+		breaker.open(now)
+	}
+	p.logCircuitTransition(serverName, previousState, breaker.currentState())
+}
+
+func (p *Pool) onCircuitFailure(serverName string, err error) {
+	if !p.circuitBreakersEnabled() {
+		return
+	}
+	p.serversMut.Lock()
+	defer p.serversMut.Unlock()
+	breaker := &p.statsOf(serverName).circuit
+	previousState := breaker.currentState()
+	breaker.onFailure(itime.Now(), p.config)
+	if breaker.currentState() == metrics.CircuitOpen && previousState != metrics.CircuitOpen {
+		p.log.Warnf(log.Pool, p.logId, "Circuit breaker of %s opened for %s after error: %s",
+			serverName, p.config.CircuitBreakerCoolDown, err)
+		return
+	}
+	p.logCircuitTransition(serverName, previousState, breaker.currentState())
+}
+
+func (p *Pool) logCircuitTransition(serverName string, previousState, state metrics.CircuitState) {
+	if previousState == state {
+		return
+	}
+	p.log.Infof(log.Pool, p.logId, "Circuit breaker of %s moved from %s to %s", serverName, previousState, state)
+}
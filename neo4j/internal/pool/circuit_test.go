@@ -0,0 +1,250 @@
+//go:build internal_time_mock
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/bolt"
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+)
+
+func TestPoolCircuitBreaker(outer *testing.T) {
+	connect := func(_ context.Context, s string, _ *idb.ReAuthToken, _ bolt.ConnectionErrorListener, _ log.BoltLogger) (idb.Connection, error) {
+		return &ConnFake{Name: s, Alive: true, Birth: itime.Now()}, nil
+	}
+	circuitConfig := func() config.Config {
+		return config.Config{
+			MaxConnectionLifetime:          1 * time.Hour,
+			MaxConnectionPoolSize:          2,
+			CircuitBreakerFailureThreshold: 2,
+			CircuitBreakerFailureRate:      0.5,
+			CircuitBreakerWindow:           1 * time.Minute,
+			CircuitBreakerCoolDown:         30 * time.Second,
+			CircuitBreakerHalfOpenProbes:   1,
+		}
+	}
+	newPool := func(conf *config.Config) *Pool {
+		p := New(conf, connect, logger, "pool id")
+		p.SetRouter(&RouterFake{})
+		return p
+	}
+	borrow := func(p *Pool, serverNames ...string) (idb.Connection, error) {
+		return p.Borrow(ctx, getServers(serverNames), false, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+	}
+	fail := func(p *Pool, serverName string, times int) {
+		for i := 0; i < times; i++ {
+			p.OnDialError(ctx, serverName, errors.New("connection refused"))
+		}
+	}
+	circuitState := func(p *Pool, serverName string) metrics.CircuitState {
+		return p.Metrics().Servers[serverName].CircuitState
+	}
+
+	outer.Run("opens after reaching the failure threshold", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := circuitConfig()
+		p := newPool(&conf)
+		defer p.Close(ctx)
+
+		fail(p, "srv1", 1)
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitClosed)
+		fail(p, "srv1", 1)
+
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitOpen)
+		AssertDeepEquals(t, p.Metrics().Servers["srv1"].CircuitOpenings, int64(1))
+		_, err := borrow(p, "srv1")
+		_, isCircuitOpen := err.(*errorutil.PoolCircuitOpen)
+		AssertTrue(t, isCircuitOpen)
+		AssertDeepEquals(t, p.Metrics().Rejected, int64(1))
+	})
+
+	outer.Run("excludes servers with an open circuit from candidates", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := circuitConfig()
+		p := newPool(&conf)
+		defer p.Close(ctx)
+		fail(p, "srv1", 2)
+
+		conn, err := borrow(p, "srv1", "srv2")
+
+		assertConnection(t, conn, err)
+		AssertStringEqual(t, conn.ServerName(), "srv2")
+	})
+
+	outer.Run("stays closed below the failure rate", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := circuitConfig()
+		p := newPool(&conf)
+		defer p.Close(ctx)
+		for i := 0; i < 3; i++ {
+			conn, err := borrow(p, "srv1")
+			assertConnection(t, conn, err)
+			p.Return(ctx, conn)
+		}
+
+		fail(p, "srv1", 2)
+
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitClosed)
+	})
+
+	outer.Run("forgets failures of previous windows", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := circuitConfig()
+		p := newPool(&conf)
+		defer p.Close(ctx)
+
+		fail(p, "srv1", 1)
+		_ = itime.TickTime(1 * time.Minute)
+		fail(p, "srv1", 1)
+
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitClosed)
+	})
+
+	outer.Run("closes once the probe succeeds after the cool-down", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := circuitConfig()
+		p := newPool(&conf)
+		defer p.Close(ctx)
+		fail(p, "srv1", 2)
+		_ = itime.TickTime(30 * time.Second)
+
+		probe, err := borrow(p, "srv1")
+		assertConnection(t, probe, err)
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitHalfOpen)
+		_, err = borrow(p, "srv1")
+		_, isCircuitOpen := err.(*errorutil.PoolCircuitOpen)
+		AssertTrue(t, isCircuitOpen)
+		p.Return(ctx, probe)
+
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitClosed)
+		conn, err := borrow(p, "srv1")
+		assertConnection(t, conn, err)
+	})
+
+	outer.Run("opens again when the probe fails", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := circuitConfig()
+		p := newPool(&conf)
+		defer p.Close(ctx)
+		fail(p, "srv1", 2)
+		_ = itime.TickTime(30 * time.Second)
+		probe, err := borrow(p, "srv1")
+		assertConnection(t, probe, err)
+
+		p.OnIoError(ctx, probe, &errorutil.ConnectionReadTimeout{ReadTimeout: time.Second, Err: context.DeadlineExceeded})
+
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitOpen)
+		AssertDeepEquals(t, p.Metrics().Servers["srv1"].CircuitOpenings, int64(2))
+	})
+
+	outer.Run("opens again when the probe is returned broken", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := circuitConfig()
+		p := newPool(&conf)
+		defer p.Close(ctx)
+		fail(p, "srv1", 2)
+		_ = itime.TickTime(30 * time.Second)
+		probe, err := borrow(p, "srv1")
+		assertConnection(t, probe, err)
+
+		probe.(*ConnFake).Alive = false
+		p.Return(ctx, probe)
+
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitOpen)
+	})
+
+	outer.Run("keeps the probe when the server is full", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := circuitConfig()
+		p := newPool(&conf)
+		defer p.Close(ctx)
+		for i := 0; i < conf.MaxConnectionPoolSize; i++ {
+			conn, err := borrow(p, "srv1")
+			assertConnection(t, conn, err)
+		}
+		fail(p, "srv1", 2)
+		_ = itime.TickTime(30 * time.Second)
+
+		for i := 0; i < 2; i++ {
+			_, err := borrow(p, "srv1")
+			AssertDeepEquals(t, err, &errorutil.PoolFull{Servers: []string{"srv1"}})
+		}
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitHalfOpen)
+	})
+
+	outer.Run("releases the probe when connecting fails for another reason than the server", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := circuitConfig()
+		authErr := &db.Neo4jError{Code: "Neo.ClientError.Security.Unauthorized"}
+		var connectErr error
+		p := New(&conf, func(ctx context.Context, s string, auth *idb.ReAuthToken, listener bolt.ConnectionErrorListener, logger log.BoltLogger) (idb.Connection, error) {
+			if connectErr != nil {
+				return nil, connectErr
+			}
+			return connect(ctx, s, auth, listener, logger)
+		}, logger, "pool id")
+		p.SetRouter(&RouterFake{})
+		defer p.Close(ctx)
+		fail(p, "srv1", 2)
+		_ = itime.TickTime(30 * time.Second)
+
+		connectErr = authErr
+		_, err := borrow(p, "srv1")
+		AssertDeepEquals(t, err, authErr)
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitHalfOpen)
+
+		connectErr = nil
+		probe, err := borrow(p, "srv1")
+		assertConnection(t, probe, err)
+		p.Return(ctx, probe)
+		AssertDeepEquals(t, circuitState(p, "srv1"), metrics.CircuitClosed)
+	})
+
+	outer.Run("is disabled by default", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 2}
+		p := newPool(&conf)
+		defer p.Close(ctx)
+
+		fail(p, "srv1", 10)
+
+		conn, err := borrow(p, "srv1")
+		assertConnection(t, conn, err)
+	})
+}
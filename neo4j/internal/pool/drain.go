@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+)
+
+// Drain waits until no connection is in use, no connection acquisition is waiting and no returned connection is
+// being reset in the background, so that the pool can be closed without interrupting in-flight work.
+// Connections keep being handed out while draining.
+// When the context terminates first, the returned error reports the remaining work.
+func (p *Pool) Drain(ctx context.Context) error {
+	for {
+		// Wait for the signal taken before checking, so that work completing meanwhile is not missed
+		settled := p.settled.wait()
+		inUse, waiting := p.inFlight()
+		if len(inUse) == 0 && waiting == 0 && !p.resetting() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return &errorutil.PoolDrainTimeout{InUse: inUse, Waiting: waiting, Err: ctx.Err()}
+		case <-settled:
+		}
+	}
+}
+
+func (p *Pool) inFlight() (map[string]int, int) {
+	waiting := p.queueSize()
+	p.serversMut.Lock()
+	defer p.serversMut.Unlock()
+	inUse := make(map[string]int)
+	for name, srv := range p.servers {
+		if busy := srv.numBusy() + srv.reservations; busy > 0 {
+			inUse[name] = busy
+		}
+	}
+	return inUse, waiting
+}
+
+// signal tells the goroutines waiting on it that something happened, its zero value is ready to use.
+// The pool signals settled whenever work leaves the pool: a connection is made available again or unregistered, a
+// background reset or a connection attempt ends, or an acquisition gives up.
+type signal struct {
+	mut     sync.Mutex
+	waiting chan struct{}
+}
+
+// wait returns a channel closed by the next notification
+func (s *signal) wait() <-chan struct{} {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.waiting == nil {
+		s.waiting = make(chan struct{})
+	}
+	return s.waiting
+}
+
+func (s *signal) notify() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.waiting != nil {
+		close(s.waiting)
+		s.waiting = nil
+	}
+}
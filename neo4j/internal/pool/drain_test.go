@@ -0,0 +1,116 @@
+//go:build internal_time_mock
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/bolt"
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+func TestPoolDrain(outer *testing.T) {
+	connect := func(_ context.Context, s string, _ *idb.ReAuthToken, _ bolt.ConnectionErrorListener, _ log.BoltLogger) (idb.Connection, error) {
+		return &ConnFake{Name: s, Alive: true, Birth: time.Now()}, nil
+	}
+	borrow := func(p *Pool, serverName string) idb.Connection {
+		conn, err := p.Borrow(ctx, getServers([]string{serverName}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(outer, conn, err)
+		return conn
+	}
+
+	outer.Run("returns immediately without connections in use", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 2}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		p.Return(ctx, borrow(p, "srv1"))
+
+		AssertNoError(t, p.Drain(ctx))
+	})
+
+	outer.Run("waits for connections in use to be returned", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 2}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn := borrow(p, "srv1")
+		drained := make(chan error, 1)
+
+		go func() {
+			drained <- p.Drain(ctx)
+		}()
+		select {
+		case <-drained:
+			t.Fatal("expected drain to wait for the connection in use")
+		case <-time.After(50 * time.Millisecond):
+		}
+		p.Return(ctx, conn)
+
+		AssertNoError(t, <-drained)
+	})
+
+	outer.Run("waits for waiting acquisitions to be served", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn := borrow(p, "srv1")
+		go func() {
+			p.Return(ctx, borrow(p, "srv1"))
+		}()
+		for p.queueSize() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		drained := make(chan error, 1)
+
+		go func() {
+			drained <- p.Drain(ctx)
+		}()
+		p.Return(ctx, conn)
+
+		AssertNoError(t, <-drained)
+		inUse, waiting := p.inFlight()
+		AssertLen(t, inUse, 0)
+		AssertIntEqual(t, waiting, 0)
+	})
+
+	outer.Run("reports remaining work when the context terminates", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 2}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		borrow(p, "srv1")
+		borrow(p, "srv1")
+		borrow(p, "srv2")
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		err := p.Drain(timeoutCtx)
+
+		drainTimeout, ok := err.(*errorutil.PoolDrainTimeout)
+		AssertTrue(t, ok)
+		AssertDeepEquals(t, drainTimeout.InUse, map[string]int{"srv1": 2, "srv2": 1})
+		AssertIntEqual(t, drainTimeout.Waiting, 0)
+		AssertErrorMessageContains(t, err, "srv1: 2, srv2: 1")
+	})
+}
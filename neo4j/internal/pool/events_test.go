@@ -0,0 +1,200 @@
+//go:build internal_time_mock
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/events"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/bolt"
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+type recordingListener struct {
+	mut    sync.Mutex
+	events []events.ConnectionEvent
+}
+
+func (r *recordingListener) OnConnectionEvent(event events.ConnectionEvent) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.events = append(r.events, event)
+}
+
+// typesAndReasons returns the type and reason of the recorded events and forgets them
+func (r *recordingListener) typesAndReasons() []string {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	result := make([]string, len(r.events))
+	for i, event := range r.events {
+		result[i] = string(event.Type)
+		if event.Reason != "" {
+			result[i] += "/" + event.Reason
+		}
+	}
+	r.events = nil
+	return result
+}
+
+func TestPoolEvents(outer *testing.T) {
+	connect := func(_ context.Context, s string, _ *idb.ReAuthToken, _ bolt.ConnectionErrorListener, _ log.BoltLogger) (idb.Connection, error) {
+		return &ConnFake{Name: s, Alive: true, Birth: itime.Now(), Id: 1}, nil
+	}
+
+	outer.Run("notifies borrowed, returned and reset connections", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		listener := &recordingListener{}
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1, ConnectionListener: listener}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		p.Return(ctx, conn)
+
+		AssertDeepEquals(t, listener.typesAndReasons(), []string{"connection_borrowed", "connection_returned", "connection_reset"})
+	})
+
+	outer.Run("notifies dead connections and their closing", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		listener := &recordingListener{}
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1, ConnectionListener: listener}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		listener.typesAndReasons()
+
+		conn.(*ConnFake).Alive = false
+		p.Return(ctx, conn)
+
+		AssertDeepEquals(t, listener.typesAndReasons(), []string{
+			"connection_returned",
+			"connection_dead/returned_dead",
+			"connection_closed/dead",
+		})
+	})
+
+	outer.Run("notifies expired connections", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		listener := &recordingListener{}
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Minute, MaxConnectionPoolSize: 1, ConnectionListener: listener}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		p.Return(ctx, conn)
+		listener.typesAndReasons()
+
+		itime.ForceTickTime(2 * time.Minute)
+		p.CleanUp(ctx)
+
+		AssertDeepEquals(t, listener.typesAndReasons(), []string{"connection_closed/expired"})
+	})
+
+	outer.Run("notifies deactivated servers and the closing of their connections", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		listener := &recordingListener{}
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1, ConnectionListener: listener}
+		p := New(&conf, connect, logger, "pool id")
+		p.SetRouter(&RouterFake{})
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		p.Return(ctx, conn)
+		listener.typesAndReasons()
+
+		p.OnIoError(ctx, conn, errors.New("connection reset by peer"))
+
+		AssertDeepEquals(t, listener.typesAndReasons(), []string{
+			"server_deactivated",
+			"connection_closed/server_deactivated",
+		})
+	})
+
+	outer.Run("notifies connections closed with the pool", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		listener := &recordingListener{}
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1, ConnectionListener: listener}
+		p := New(&conf, connect, logger, "pool id")
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		listener.typesAndReasons()
+
+		p.Close(ctx)
+
+		AssertDeepEquals(t, listener.events, []events.ConnectionEvent{{
+			Type:         events.ConnectionClosed,
+			Time:         itime.Now(),
+			Server:       "srv1",
+			ConnectionId: "bolt-1",
+			Reason:       events.PoolClosedReason,
+		}})
+	})
+	outer.Run("notifies without holding the pool locks", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		var p *Pool
+		var notified []events.ConnectionEventType
+		listener := events.ConnectionListenerFunc(func(event events.ConnectionEvent) {
+			// would deadlock if the listener were called while the pool holds its locks
+			p.Metrics()
+			notified = append(notified, event.Type)
+		})
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Minute, MaxConnectionPoolSize: 2, ConnectionListener: listener}
+		p = New(&conf, connect, logger, "pool id")
+		p.SetRouter(&RouterFake{})
+		conn1, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn1, err)
+		conn2, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn2, err)
+		p.Return(ctx, conn1)
+		itime.ForceTickTime(2 * time.Minute)
+		p.CleanUp(ctx)
+		p.OnIoError(ctx, conn2, errors.New("connection reset by peer"))
+		p.Return(ctx, conn2)
+		p.Close(ctx)
+
+		AssertDeepEquals(t, notified, []events.ConnectionEventType{
+			events.ConnectionBorrowed,
+			events.ConnectionBorrowed,
+			events.ConnectionReturned,
+			events.ConnectionReset,
+			events.ConnectionClosed, // expired
+			events.ServerDeactivated,
+			events.ConnectionReturned,
+			events.ConnectionReset,
+			events.ConnectionClosed, // server deactivated
+		})
+	})
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/loadbalancing"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+// localServersFirst returns the servers to borrow from, and these servers in the order they should be borrowed
+// from. When config.Config Locality is set, the servers of the local zone are tried first, and the other servers
+// only as allowed by the spill-over policy. The servers of each zone are ordered by orderServers.
+func (p *Pool) localServersFirst(ctx context.Context, serverNames []string) ([]string, []string) {
+	locality := p.config.Locality
+	if locality == nil {
+		return serverNames, p.orderServers(ctx, serverNames)
+	}
+	var local, remote []string
+	for _, serverName := range serverNames {
+		if locality.IsLocal(serverName) {
+			local = append(local, serverName)
+		} else {
+			remote = append(remote, serverName)
+		}
+	}
+	if len(local) == 0 || len(remote) == 0 {
+		return serverNames, p.orderServers(ctx, serverNames)
+	}
+	switch locality.SpillOver {
+	case loadbalancing.SpillOverNever:
+		return local, p.orderServers(ctx, local)
+	case loadbalancing.SpillOverWhenUnhealthy:
+		if p.anyHealthy(local) {
+			return local, p.orderServers(ctx, local)
+		}
+		p.log.Debugf(log.Pool, p.logId, "No healthy server in zone %s, spilling over to %s", locality.LocalZone, remote)
+	}
+	return serverNames, append(p.orderServers(ctx, local), p.orderServers(ctx, remote)...)
+}
+
+// anyHealthy tells whether any of the servers did not recently fail to accept a connection.
+// Servers with an open circuit breaker are left out of the candidates beforehand, see admittedServers.
+func (p *Pool) anyHealthy(serverNames []string) bool {
+	p.serversMut.Lock()
+	defer p.serversMut.Unlock()
+	now := itime.Now()
+	for _, serverName := range serverNames {
+		if srv := p.servers[serverName]; srv == nil || !srv.hasFailedConnect(now) {
+			return true
+		}
+	}
+	return false
+}
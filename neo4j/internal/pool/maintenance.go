@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/events"
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	ievents "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/events"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+// maintenanceInterval is how often the pool maintains its connections in the background: it closes expired and
+// long idle connections, probes idle connections and tops up servers that fell below
+// config.Config MinIdleConnectionsPerServer.
+const maintenanceInterval = 1 * time.Second
+
+// maintenancePollInterval is how often the background maintenance checks whether maintenanceInterval elapsed.
+// Elapsed time is measured with the itime clock, so that tests can drive the maintenance by ticking a frozen clock.
+const maintenancePollInterval = 100 * time.Millisecond
+
+// keepAliveTimeout bounds the duration of the keep-alive probe of an idle connection.
+const keepAliveTimeout = 30 * time.Second
+
+// maintenanceEnabled tells whether the pool needs to maintain its connections in the background.
+// Without idle eviction, keep-alive probes or minimum idle connections, expired connections are only closed when
+// borrowing or by CleanUp, as they have always been.
+func maintenanceEnabled(config *config.Config) bool {
+	return config.MaxConnectionIdleTime > 0 ||
+		config.ConnectionKeepAliveInterval > 0 ||
+		config.MinIdleConnectionsPerServer > 0
+}
+
+// runMaintenance maintains the connections of the pool every maintenanceInterval from start, until ctx is cancelled
+func (p *Pool) runMaintenance(ctx context.Context, start time.Time) {
+	lastRun := start
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(maintenancePollInterval):
+		}
+		if itime.Since(lastRun) < maintenanceInterval {
+			continue
+		}
+		p.maintain(ctx)
+		lastRun = itime.Now()
+	}
+}
+
+func (p *Pool) maintain(ctx context.Context) {
+	if p.isClosed() {
+		return
+	}
+	now := itime.Now()
+	var probes []idb.Connection
+	pending := ievents.NewPending(p.config.ConnectionListener)
+	p.serversMut.Lock()
+	for name, srv := range p.servers {
+		srv.removeIdleOlderThan(now, p.config.MaxConnectionLifetime, p.config.MaxConnectionLifetimeJitter, pending)
+		if p.config.MaxConnectionIdleTime > 0 {
+			srv.removeIdleLongerThan(now, p.config.MaxConnectionIdleTime, p.config.MinIdleConnectionsPerServer, pending)
+		}
+		if p.config.ConnectionKeepAliveInterval > 0 && !srv.closing {
+			// Probing a connection makes it look recently used, only probe the connections meant to be kept when
+			// idle connections are evicted down to a minimum
+			limit := math.MaxInt32
+			if p.config.MaxConnectionIdleTime > 0 && p.config.MinIdleConnectionsPerServer > 0 {
+				limit = p.config.MinIdleConnectionsPerServer
+			}
+			probes = append(probes, srv.takeIdleLongerThan(now, p.config.ConnectionKeepAliveInterval, limit)...)
+		}
+		if srv.size() == 0 && !srv.hasFailedConnect(now) {
+			delete(p.servers, name)
+		}
+	}
+	p.serversMut.Unlock()
+	pending.Flush()
+
+	// Probes run concurrently, so that an unresponsive server delays the pass by at most keepAliveTimeout
+	var probing sync.WaitGroup
+	probing.Add(len(probes))
+	for _, c := range probes {
+		go func(c idb.Connection) {
+			defer probing.Done()
+			p.keepAlive(ctx, c)
+		}(c)
+	}
+	probing.Wait()
+
+	p.maintenanceMut.Lock()
+	getServerNames, auth := p.minIdleServers, p.minIdleAuth
+	p.maintenanceMut.Unlock()
+	if getServerNames != nil && p.config.MinIdleConnectionsPerServer > 0 {
+		p.maintainMinIdle(ctx, getServerNames(), auth)
+	}
+}
+
+// keepAlive probes a connection taken out of the idle connections with a RESET message, so that firewalls and NATs
+// do not silently drop it, then gives it back to the pool if still alive.
+func (p *Pool) keepAlive(ctx context.Context, c idb.Connection) {
+	serverName := c.ServerName()
+	probeCtx, cancel := context.WithTimeout(ctx, keepAliveTimeout)
+	start := itime.Now()
+	c.ForceReset(probeCtx)
+	roundTripTime := itime.Since(start)
+	cancel()
+	if !c.IsAlive() {
+		p.log.Infof(log.Pool, p.logId, "Idle connection to %s failed keep-alive probe", serverName)
+		ievents.NotifyConnection(p.config.ConnectionListener, events.ConnectionDead, c, events.FailedKeepAliveReason)
+		p.unreg(serverName, c, itime.Now(), events.DeadReason)
+		return
+	}
+	ievents.NotifyConnection(p.config.ConnectionListener, events.ConnectionReset, c, "")
+	p.observeRoundTripTime(serverName, roundTripTime)
+	p.makeAvailable(ctx, serverName, c)
+}
+
+// lifetimeJitter returns how much earlier than config.Config MaxConnectionLifetime the connection expires, so that
+// connections created at the same time, e.g. when warming up the pool, do not all expire and reconnect at once.
+// The jitter is derived from the connection id, to remain stable over the life of the connection.
+func lifetimeJitter(c idb.Connection, maxJitter time.Duration) time.Duration {
+	if maxJitter <= 0 {
+		return 0
+	}
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(c.ConnectionId()))
+	return time.Duration(hash.Sum64() % uint64(maxJitter))
+}
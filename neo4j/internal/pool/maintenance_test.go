@@ -0,0 +1,263 @@
+//go:build internal_time_mock
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/bolt"
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+func TestPoolMaintenance(outer *testing.T) {
+	idleConnection := func(id int, birth, idle time.Time) *ConnFake {
+		return &ConnFake{Name: "srv1", Id: id, Alive: true, Birth: birth, Idle: idle}
+	}
+
+	outer.Run("closes expired connections and forgets empty servers", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Minute, MaxConnectionPoolSize: 2}
+		p := New(&conf, nil, logger, "pool id")
+		defer p.Close(ctx)
+		now := itime.Now()
+		setIdleConnections(p, map[string][]idb.Connection{"srv1": {idleConnection(1, now.Add(-2*time.Minute), now)}})
+
+		p.maintain(ctx)
+
+		AssertIntEqual(t, len(p.getServers()), 0)
+	})
+
+	outer.Run("closes long idle connections down to the minimum of idle connections", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3, MaxConnectionIdleTime: 1 * time.Minute, MinIdleConnectionsPerServer: 1}
+		p := New(&conf, nil, logger, "pool id")
+		defer p.Close(ctx)
+		now := itime.Now()
+		recent := idleConnection(1, now, now)
+		setIdleConnections(p, map[string][]idb.Connection{"srv1": {
+			recent,
+			idleConnection(2, now, now.Add(-2*time.Minute)),
+			idleConnection(3, now, now.Add(-3*time.Minute)),
+		}})
+
+		p.maintain(ctx)
+
+		srv := p.getServers()["srv1"]
+		AssertIntEqual(t, srv.numIdle(), 1)
+		AssertDeepEquals(t, srv.getIdle(), recent)
+	})
+
+	outer.Run("keeps long idle connections when at the minimum of idle connections", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3, MaxConnectionIdleTime: 1 * time.Minute, MinIdleConnectionsPerServer: 2}
+		p := New(&conf, nil, logger, "pool id")
+		defer p.Close(ctx)
+		now := itime.Now()
+		setIdleConnections(p, map[string][]idb.Connection{"srv1": {
+			idleConnection(1, now, now.Add(-2*time.Minute)),
+			idleConnection(2, now, now.Add(-3*time.Minute)),
+		}})
+
+		p.maintain(ctx)
+
+		AssertIntEqual(t, p.getServers()["srv1"].numIdle(), 2)
+	})
+
+	outer.Run("expires connections earlier with lifetime jitter", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3, MaxConnectionLifetimeJitter: 10 * time.Minute}
+		p := New(&conf, nil, logger, "pool id")
+		defer p.Close(ctx)
+		now := itime.Now()
+		conn := idleConnection(1, now, now)
+		jitter := lifetimeJitter(conn, conf.MaxConnectionLifetimeJitter)
+		AssertTrue(t, jitter > 0 && jitter < conf.MaxConnectionLifetimeJitter)
+		AssertDeepEquals(t, lifetimeJitter(conn, conf.MaxConnectionLifetimeJitter), jitter)
+		setIdleConnections(p, map[string][]idb.Connection{"srv1": {conn}})
+
+		itime.ForceTickTime(conf.MaxConnectionLifetime - jitter - time.Second)
+		p.maintain(ctx)
+		AssertIntEqual(t, p.getServers()["srv1"].numIdle(), 1)
+
+		itime.ForceTickTime(time.Second)
+		p.maintain(ctx)
+		AssertIntEqual(t, len(p.getServers()), 0)
+	})
+
+	outer.Run("probes idle connections", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3, ConnectionKeepAliveInterval: 1 * time.Minute}
+		p := New(&conf, nil, logger, "pool id")
+		defer p.Close(ctx)
+		now := itime.Now()
+		resets := 0
+		healthy := idleConnection(1, now, now.Add(-2*time.Minute))
+		healthy.ForceResetHook = func() {
+			resets++
+			healthy.Idle = itime.Now()
+		}
+		dead := idleConnection(2, now, now.Add(-2*time.Minute))
+		dead.ForceResetHook = func() {
+			dead.Alive = false
+		}
+		recent := idleConnection(3, now, now)
+		recent.ForceResetHook = func() {
+			t.Errorf("recently used connection should not be probed")
+		}
+		setIdleConnections(p, map[string][]idb.Connection{"srv1": {healthy, dead, recent}})
+
+		p.maintain(ctx)
+
+		srv := p.getServers()["srv1"]
+		AssertIntEqual(t, resets, 1)
+		AssertIntEqual(t, srv.numIdle(), 2)
+		AssertIntEqual(t, srv.numBusy(), 0)
+	})
+
+	outer.Run("probes all idle connections without minimum of idle connections", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3, MaxConnectionIdleTime: 10 * time.Minute, ConnectionKeepAliveInterval: 1 * time.Minute}
+		p := New(&conf, nil, logger, "pool id")
+		defer p.Close(ctx)
+		now := itime.Now()
+		var resets int32
+		conns := []idb.Connection{idleConnection(1, now, now.Add(-2*time.Minute)), idleConnection(2, now, now.Add(-3*time.Minute))}
+		for _, conn := range conns {
+			conn.(*ConnFake).ForceResetHook = func() { atomic.AddInt32(&resets, 1) }
+		}
+		setIdleConnections(p, map[string][]idb.Connection{"srv1": conns})
+
+		p.maintain(ctx)
+
+		AssertIntEqual(t, int(atomic.LoadInt32(&resets)), 2)
+		AssertIntEqual(t, p.getServers()["srv1"].numIdle(), 2)
+	})
+
+	outer.Run("probes servers concurrently", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3, ConnectionKeepAliveInterval: 1 * time.Minute}
+		p := New(&conf, nil, logger, "pool id")
+		defer p.Close(ctx)
+		now := itime.Now()
+		unresponsive := idleConnection(1, now, now.Add(-2*time.Minute))
+		responsive := &ConnFake{Name: "srv2", Id: 2, Alive: true, Birth: now, Idle: now.Add(-2 * time.Minute)}
+		probed := make(chan struct{})
+		// The probe of srv1 only completes once srv2 has been probed
+		unresponsive.ForceResetHook = func() { <-probed }
+		responsive.ForceResetHook = func() { close(probed) }
+		setIdleConnections(p, map[string][]idb.Connection{"srv1": {unresponsive}, "srv2": {responsive}})
+
+		p.maintain(ctx)
+
+		AssertIntEqual(t, p.getServers()["srv1"].numIdle(), 1)
+		AssertIntEqual(t, p.getServers()["srv2"].numIdle(), 1)
+	})
+
+	outer.Run("closes connections independently of the maintenance context", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Minute, MaxConnectionPoolSize: 2, MaxConnectionIdleTime: 1 * time.Minute}
+		p := New(&conf, nil, logger, "pool id")
+		defer p.Close(ctx)
+		now := itime.Now()
+		maintenanceCtx, cancel := context.WithCancel(ctx)
+		closeErrs := make(chan error, 2)
+		closeHook := func(closeCtx context.Context) {
+			<-maintenanceCtx.Done()
+			_, hasDeadline := closeCtx.Deadline()
+			AssertTrue(t, hasDeadline)
+			closeErrs <- closeCtx.Err()
+		}
+		expired := idleConnection(1, now.Add(-2*time.Minute), now)
+		expired.CloseHook = closeHook
+		idle := idleConnection(2, now, now.Add(-2*time.Minute))
+		idle.CloseHook = closeHook
+		setIdleConnections(p, map[string][]idb.Connection{"srv1": {expired, idle}})
+
+		p.maintain(maintenanceCtx)
+		cancel()
+
+		AssertNoError(t, <-closeErrs)
+		AssertNoError(t, <-closeErrs)
+	})
+
+	outer.Run("runs in the background on the itime clock", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3, MaxConnectionIdleTime: 1 * time.Minute}
+		p := New(&conf, nil, logger, "pool id")
+		defer p.Close(ctx)
+		now := itime.Now()
+		p.serversMut.Lock()
+		setIdleConnections(p, map[string][]idb.Connection{"srv1": {idleConnection(1, now, now.Add(-2*time.Minute))}})
+		p.serversMut.Unlock()
+
+		time.Sleep(3 * maintenancePollInterval)
+		AssertIntEqual(t, len(p.getServers()), 1)
+
+		itime.ForceTickTime(maintenanceInterval)
+		for len(p.getServers()) > 0 {
+			time.Sleep(maintenancePollInterval)
+		}
+	})
+
+	outer.Run("is only enabled when configured", func(t *testing.T) {
+		AssertFalse(t, maintenanceEnabled(&config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionLifetimeJitter: 1 * time.Minute}))
+		AssertTrue(t, maintenanceEnabled(&config.Config{MaxConnectionIdleTime: 1 * time.Minute}))
+		AssertTrue(t, maintenanceEnabled(&config.Config{ConnectionKeepAliveInterval: 1 * time.Minute}))
+		AssertTrue(t, maintenanceEnabled(&config.Config{MinIdleConnectionsPerServer: 1}))
+	})
+
+	outer.Run("close interrupts connections being established", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		connecting := make(chan struct{})
+		connectErrs := make(chan error, 1)
+		connect := func(ctx context.Context, _ string, _ *idb.ReAuthToken, _ bolt.ConnectionErrorListener, _ log.BoltLogger) (idb.Connection, error) {
+			close(connecting)
+			<-ctx.Done()
+			connectErrs <- ctx.Err()
+			return nil, ctx.Err()
+		}
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3, MinIdleConnectionsPerServer: 1}
+		p := New(&conf, connect, logger, "pool id")
+		p.KeepMinIdle(func() []string { return []string{"srv1"} }, reAuthToken)
+
+		itime.ForceTickTime(maintenanceInterval)
+		<-connecting
+		p.Close(ctx)
+
+		AssertDeepEquals(t, <-connectErrs, context.Canceled)
+	})
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+	imetrics "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/metrics"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+)
+
+// Statistics of a server, kept even when the pool forgets about the server itself.
+// Not thread safe, guarded by the pool's server lock.
+type serverStats struct {
+	created            int64
+	failedToCreate     int64
+	closed             int64
+	lastFailedToCreate time.Time
+	// moving averages fed to config.Config LoadBalancer
+	connectionTime time.Duration
+	roundTripTime  time.Duration
+	circuit        circuitBreaker
+}
+
+// movingAverageWeight is the weight of the latest sample in the exponentially weighted moving averages of durations
+const movingAverageWeight = 0.2
+
+func movingAverage(average, sample time.Duration) time.Duration {
+	if average == 0 {
+		return sample
+	}
+	return average + time.Duration(movingAverageWeight*float64(sample-average))
+}
+
+func (s *serverStats) observeConnectionTime(duration time.Duration) {
+	s.connectionTime = movingAverage(s.connectionTime, duration)
+}
+
+func (p *Pool) observeRoundTripTime(serverName string, duration time.Duration) {
+	p.serversMut.Lock()
+	defer p.serversMut.Unlock()
+	stats := p.statsOf(serverName)
+	stats.roundTripTime = movingAverage(stats.roundTripTime, duration)
+}
+
+// Thread safe
+type acquisitionStats struct {
+	mut      sync.Mutex
+	acquired int64
+	timeouts int64
+	rejected int64
+	duration imetrics.Histogram
+}
+
+func (a *acquisitionStats) onBorrow(duration time.Duration, err error) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	switch err.(type) {
+	case nil:
+		a.acquired++
+		a.duration.Observe(duration)
+	case *errorutil.PoolTimeout:
+		a.timeouts++
+	case *errorutil.PoolFull, *errorutil.PoolQueueFull, *errorutil.PoolCircuitOpen:
+		a.rejected++
+	}
+}
+
+func (a *acquisitionStats) snapshot(poolMetrics *metrics.PoolMetrics) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	poolMetrics.Acquired = a.acquired
+	poolMetrics.Timeouts = a.timeouts
+	poolMetrics.Rejected = a.rejected
+	poolMetrics.AcquisitionTime = a.duration.Snapshot()
+}
+
+// Metrics returns a snapshot of the pool metrics.
+func (p *Pool) Metrics() metrics.PoolMetrics {
+	poolMetrics := metrics.PoolMetrics{MaxSizePerServer: p.config.MaxConnectionPoolSize}
+	p.serversMut.Lock()
+	poolMetrics.Servers = make(map[string]metrics.ServerMetrics, len(p.stats))
+	for name, stats := range p.stats {
+		serverMetrics := metrics.ServerMetrics{
+			Created:            stats.created,
+			FailedToCreate:     stats.failedToCreate,
+			Closed:             stats.closed,
+			LastFailedToCreate: stats.lastFailedToCreate,
+			CircuitState:       stats.circuit.currentState(),
+			CircuitOpenings:    stats.circuit.openings,
+			MaxSize:            p.maxSize(name),
+		}
+		if srv := p.servers[name]; srv != nil {
+			serverMetrics.Idle = srv.numIdle()
+			serverMetrics.InUse = srv.numBusy()
+			serverMetrics.Creating = srv.reservations
+		}
+		poolMetrics.Servers[name] = serverMetrics
+	}
+	p.serversMut.Unlock()
+	poolMetrics.Waiting = p.queueSize()
+	p.acquisitions.snapshot(&poolMetrics)
+	return poolMetrics
+}
+
+// prunableStatsLocked returns the servers whose statistics can be pruned unless a routing table lists them, see
+// pruneStats.
+// Must be called while holding the server lock
+func (p *Pool) prunableStatsLocked(now time.Time) []string {
+	var serverNames []string
+	for name, stats := range p.stats {
+		if p.servers[name] == nil && !stats.circuit.coolingDown(now, p.config) {
+			serverNames = append(serverNames, name)
+		}
+	}
+	return serverNames
+}
+
+// pruneStats forgets the statistics of the given servers, unless the pool connected to them again in the meantime or
+// a routing table lists them, so that the statistics of servers that left the cluster do not pile up.
+// Servers whose circuit breaker is open keep their statistics until the cool-down ends.
+func (p *Pool) pruneStats(serverNames []string, now time.Time) {
+	if len(serverNames) == 0 {
+		return
+	}
+	unknown := serverNames[:0]
+	for _, name := range serverNames {
+		// asked without holding the server lock, the router has its own
+		if p.router == nil || !p.router.HasServer(name) {
+			unknown = append(unknown, name)
+		}
+	}
+	p.serversMut.Lock()
+	defer p.serversMut.Unlock()
+	for _, name := range unknown {
+		if stats := p.stats[name]; stats != nil && p.servers[name] == nil && !stats.circuit.coolingDown(now, p.config) {
+			delete(p.stats, name)
+		}
+	}
+}
+
+// Must be called while holding the server lock
+func (p *Pool) statsOf(serverName string) *serverStats {
+	stats := p.stats[serverName]
+	if stats == nil {
+		stats = &serverStats{}
+		p.stats[serverName] = stats
+	}
+	return stats
+}
@@ -0,0 +1,160 @@
+//go:build internal_time_mock
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/bolt"
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+)
+
+func TestPoolMetrics(outer *testing.T) {
+	connectErr := errors.New("connection refused")
+	connect := func(_ context.Context, s string, _ *idb.ReAuthToken, _ bolt.ConnectionErrorListener, _ log.BoltLogger) (idb.Connection, error) {
+		if s == "down" {
+			return nil, connectErr
+		}
+		return &ConnFake{Name: s, Alive: true, Birth: itime.Now()}, nil
+	}
+
+	outer.Run("reports connections per server", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 2}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+
+		c1, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, c1, err)
+		c2, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, c2, err)
+		p.Return(ctx, c2)
+		_, err = p.Borrow(ctx, getServers([]string{"down"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		AssertError(t, err)
+
+		poolMetrics := p.Metrics()
+
+		AssertDeepEquals(t, poolMetrics.Servers, map[string]metrics.ServerMetrics{
+			"srv1": {Idle: 1, InUse: 1, Created: 2, MaxSize: 2, CircuitState: metrics.CircuitClosed},
+			"down": {FailedToCreate: 1, LastFailedToCreate: itime.Now(), MaxSize: 2, CircuitState: metrics.CircuitClosed},
+		})
+		AssertIntEqual(t, poolMetrics.MaxSizePerServer, 2)
+		AssertDeepEquals(t, poolMetrics.Acquired, int64(2))
+		AssertDeepEquals(t, poolMetrics.AcquisitionTime.Count, uint64(2))
+		// time is frozen, acquisitions took no time
+		for _, count := range poolMetrics.AcquisitionTime.Counts {
+			AssertDeepEquals(t, count, uint64(2))
+		}
+	})
+
+	outer.Run("keeps counting closed connections of forgotten servers", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		conn.(*ConnFake).Alive = false
+		p.Return(ctx, conn)
+
+		AssertDeepEquals(t, p.Metrics().Servers, map[string]metrics.ServerMetrics{
+			"srv1": {Created: 1, Closed: 1, MaxSize: 1, CircuitState: metrics.CircuitClosed},
+		})
+	})
+
+	outer.Run("prunes servers that left the pool and the routing tables", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		conf := config.Config{
+			MaxConnectionLifetime:          1 * time.Hour,
+			MaxConnectionPoolSize:          1,
+			CircuitBreakerFailureThreshold: 1,
+			CircuitBreakerFailureRate:      0.5,
+			CircuitBreakerWindow:           1 * time.Minute,
+			CircuitBreakerCoolDown:         30 * time.Second,
+			CircuitBreakerHalfOpenProbes:   1,
+		}
+		p := New(&conf, connect, logger, "pool id")
+		p.SetRouter(&RouterFake{KnownServers: []string{"srv2"}})
+		defer p.Close(ctx)
+		for _, serverName := range []string{"srv1", "srv2"} {
+			conn, err := p.Borrow(ctx, getServers([]string{serverName}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+			assertConnection(t, conn, err)
+			conn.(*ConnFake).Alive = false
+			p.Return(ctx, conn)
+		}
+		p.OnDialError(ctx, "srv3", connectErr)
+
+		p.CleanUp(ctx)
+
+		AssertDeepEquals(t, serverNamesOf(p.Metrics()), []string{"srv2", "srv3"})
+		itime.ForceTickTime(conf.CircuitBreakerCoolDown)
+		p.CleanUp(ctx)
+		AssertDeepEquals(t, serverNamesOf(p.Metrics()), []string{"srv2"})
+	})
+
+	outer.Run("reports rejections, timeouts and waiting acquisitions", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+
+		_, err = p.Borrow(ctx, getServers([]string{"srv1"}), false, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		AssertError(t, err)
+		timeoutCtx, cancel := context.WithCancel(ctx)
+		errs := make(chan error, 1)
+		go func() {
+			_, err := p.Borrow(timeoutCtx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+			errs <- err
+		}()
+		waitForBorrowers(p, 1)
+		AssertIntEqual(t, p.Metrics().Waiting, 1)
+		cancel()
+		AssertError(t, <-errs)
+
+		poolMetrics := p.Metrics()
+		AssertIntEqual(t, poolMetrics.Waiting, 0)
+		AssertDeepEquals(t, poolMetrics.Acquired, int64(1))
+		AssertDeepEquals(t, poolMetrics.Rejected, int64(1))
+		AssertDeepEquals(t, poolMetrics.Timeouts, int64(1))
+	})
+}
+
+func serverNamesOf(poolMetrics metrics.PoolMetrics) []string {
+	serverNames := make([]string, 0, len(poolMetrics.Servers))
+	for name := range poolMetrics.Servers {
+		serverNames = append(serverNames, name)
+	}
+	sort.Strings(serverNames)
+	return serverNames
+}
@@ -26,14 +26,18 @@ import (
 	"math"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/events"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/bolt"
 	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+	ievents "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/events"
 	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/loadbalancing"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
 )
 
@@ -41,16 +45,38 @@ import (
 // Liveness checks are performed before a connection is deemed idle enough to be reset.
 const DefaultConnectionLivenessCheckTimeout = math.MaxInt64
 
+// backgroundResetTimeout bounds the duration of the reset of connections returned to the pool, when performed in
+// the background.
+const backgroundResetTimeout = 30 * time.Second
+
+// backgroundCloseTimeout bounds the duration of the closure of discarded connections, performed in the background.
+const backgroundCloseTimeout = 30 * time.Second
+
 type Connect func(context.Context, string, *idb.ReAuthToken, bolt.ConnectionErrorListener, log.BoltLogger) (idb.Connection, error)
 
 type poolRouter interface {
 	InvalidateWriter(db string, server string)
 	InvalidateReader(db string, server string)
 	InvalidateServer(server string)
+	HasServer(server string) bool
 }
 
+// qitem is a borrower waiting for a connection to any of its servers.
+// Waiters are served in FIFO order, per server: a connection made available on a server is handed over to the
+// longest waiting borrower of that server, through wakeup. A nil connection tells the borrower to try again, e.g.
+// because a connection to one of its servers was closed, leaving room for a new one.
 type qitem struct {
-	wakeup chan bool
+	servers []string
+	wakeup  chan idb.Connection
+}
+
+func (q *qitem) waitsFor(serverName string) bool {
+	for _, name := range q.servers {
+		if name == serverName {
+			return true
+		}
+	}
+	return false
 }
 
 type Pool struct {
@@ -61,9 +87,29 @@ type Pool struct {
 	serversMut sync.Mutex
 	queueMut   sync.Mutex
 	queue      list.List
-	closed     bool
+	closed     uint32 // accessed atomically, see isClosed
 	log        log.Logger
 	logId      string
+	// stats are guarded by serversMut
+	stats        map[string]*serverStats
+	acquisitions acquisitionStats
+	// maintenanceCtx is cancelled when the pool is closed, which stops the background maintenance of connections
+	// and interrupts the connections it establishes, see maintenance.go
+	maintenanceCtx    context.Context
+	cancelMaintenance context.CancelFunc
+	// minIdleServers and minIdleAuth are guarded by maintenanceMut
+	maintenanceMut sync.Mutex
+	minIdleServers func() []string
+	minIdleAuth    *idb.ReAuthToken
+	// resetsMut makes starting a background reset and closing the pool mutually exclusive, it guards numResets.
+	// resets tracks the background resets in flight, which Close interrupts through resetsCtx and waits for.
+	resetsMut    sync.Mutex
+	resets       sync.WaitGroup
+	numResets    int
+	resetsCtx    context.Context
+	cancelResets context.CancelFunc
+	// settled is notified whenever work leaves the pool, see Drain
+	settled signal
 }
 
 type serverPenalty struct {
@@ -83,35 +129,51 @@ func New(config *config.Config, connect Connect, logger log.Logger, logId string
 		queueMut:   sync.Mutex{},
 		logId:      logId,
 		log:        logger,
+		stats:      make(map[string]*serverStats),
+	}
+	p.maintenanceCtx, p.cancelMaintenance = context.WithCancel(context.Background())
+	p.resetsCtx, p.cancelResets = context.WithCancel(context.Background())
+	if maintenanceEnabled(config) {
+		go p.runMaintenance(p.maintenanceCtx, itime.Now())
 	}
 	p.log.Infof(log.Pool, p.logId, "Created")
 	return p
 }
 
+func (p *Pool) isClosed() bool {
+	return atomic.LoadUint32(&p.closed) == 1
+}
+
 func (p *Pool) SetRouter(router poolRouter) {
 	p.router = router
 }
 
 func (p *Pool) Close(ctx context.Context) {
-	p.closed = true
+	p.resetsMut.Lock()
+	atomic.StoreUint32(&p.closed, 1)
+	p.resetsMut.Unlock()
+	p.cancelMaintenance()
+	// Interrupt the background resets and wait for them to hand their connections back
+	p.cancelResets()
+	p.resets.Wait()
 	p.queueMut.Lock()
-	for e := p.queue.Front(); e != nil; e = e.Next() {
-		queuedRequest := e.Value.(*qitem)
-		p.queue.Remove(e)
-		queuedRequest.wakeup <- true
+	for e := p.queue.Front(); e != nil; e = p.queue.Front() {
+		queuedRequest := p.queue.Remove(e).(*qitem)
+		queuedRequest.wakeup <- nil
 	}
 	p.queueMut.Unlock()
 	// Go through each server and close all connections to it
+	pending := ievents.NewPending(p.config.ConnectionListener)
 	p.serversMut.Lock()
 	for n, s := range p.servers {
-		s.closeAll(ctx)
+		s.closeAll(ctx, pending)
 		delete(p.servers, n)
 	}
 	p.serversMut.Unlock()
+	pending.Flush()
 	p.log.Infof(log.Pool, p.logId, "Closed")
 }
 
-// For testing
 func (p *Pool) queueSize() int {
 	p.queueMut.Lock()
 	defer p.queueMut.Unlock()
@@ -133,19 +195,73 @@ func (p *Pool) getServers() map[string]*server {
 // gets removed from the map at some point in time. If there is a noticed
 // failed connect still active  we should wait a while with removal to get
 // prioritization right.
+// The statistics of servers the pool forgot about are pruned as well, see pruneStats.
 func (p *Pool) CleanUp(ctx context.Context) {
+	pending := ievents.NewPending(p.config.ConnectionListener)
 	p.serversMut.Lock()
-	defer p.serversMut.Unlock()
 	now := itime.Now()
 	for n, s := range p.servers {
-		s.removeIdleOlderThan(ctx, now, p.config.MaxConnectionLifetime)
+		s.removeIdleOlderThan(now, p.config.MaxConnectionLifetime, p.config.MaxConnectionLifetimeJitter, pending)
 		if s.size() == 0 && !s.hasFailedConnect(now) {
 			delete(p.servers, n)
 		}
 	}
+	candidates := p.prunableStatsLocked(now)
+	p.serversMut.Unlock()
+	pending.Flush()
+	p.pruneStats(candidates, now)
+}
+
+// orderServers returns the servers in the order they should be borrowed from, as decided by
+// config.Config LoadBalancer, or by lowest penalty by default.
+func (p *Pool) orderServers(ctx context.Context, serverNames []string) []string {
+	if loadBalancer := p.config.LoadBalancer; loadBalancer != nil {
+		return loadBalancer.Order(p.getLoadBalancingStats(ctx, serverNames))
+	}
+	// Retrieve penalty for each server
+	penalties := p.getPenaltiesForServers(ctx, serverNames)
+	// Sort server penalties by lowest penalty
+	sort.Slice(penalties, func(i, j int) bool {
+		return penalties[i].penalty < penalties[j].penalty
+	})
+	orderedServerNames := make([]string, len(penalties))
+	for i, penalty := range penalties {
+		orderedServerNames[i] = penalty.name
+	}
+	return orderedServerNames
+}
+
+func (p *Pool) getLoadBalancingStats(ctx context.Context, serverNames []string) []loadbalancing.Server {
+	pending := ievents.NewPending(p.config.ConnectionListener)
+	defer pending.Flush()
+	p.serversMut.Lock()
+	defer p.serversMut.Unlock()
+
+	servers := make([]loadbalancing.Server, len(serverNames))
+	now := itime.Now()
+	for i, n := range serverNames {
+		stats := p.statsOf(n)
+		servers[i] = loadbalancing.Server{
+			Address:        n,
+			MaxSize:        p.maxSize(n),
+			ConnectionTime: stats.connectionTime,
+			RoundTripTime:  stats.roundTripTime,
+		}
+		if s := p.servers[n]; s != nil {
+			// Make sure that we don't get a too old connection
+			s.removeIdleOlderThan(now, p.config.MaxConnectionLifetime, p.config.MaxConnectionLifetimeJitter, pending)
+			servers[i].Idle = s.numIdle()
+			servers[i].InUse = s.numBusy()
+			servers[i].Creating = s.reservations
+			servers[i].FailedRecently = s.hasFailedConnect(now)
+		}
+	}
+	return servers
 }
 
 func (p *Pool) getPenaltiesForServers(ctx context.Context, serverNames []string) []serverPenalty {
+	pending := ievents.NewPending(p.config.ConnectionListener)
+	defer pending.Flush()
 	p.serversMut.Lock()
 	defer p.serversMut.Unlock()
 
@@ -157,7 +273,7 @@ func (p *Pool) getPenaltiesForServers(ctx context.Context, serverNames []string)
 		penalties[i].name = n
 		if s != nil {
 			// Make sure that we don't get a too old connection
-			s.removeIdleOlderThan(ctx, now, p.config.MaxConnectionLifetime)
+			s.removeIdleOlderThan(now, p.config.MaxConnectionLifetime, p.config.MaxConnectionLifetimeJitter, pending)
 			penalties[i].penalty = s.calculatePenalty(now)
 		} else {
 			penalties[i].penalty = newConnectionPenalty
@@ -172,7 +288,7 @@ func (p *Pool) anyHasCapacity(serverNames []string) bool {
 	for _, serverName := range serverNames {
 		srv := p.servers[serverName]
 		if srv != nil {
-			if srv.numIdle() > 0 || srv.size() < p.config.MaxConnectionPoolSize {
+			if srv.numIdle() > 0 || srv.size() < p.maxSize(serverName) {
 				return true
 			}
 		}
@@ -187,28 +303,45 @@ func (p *Pool) Borrow(
 	boltLogger log.BoltLogger,
 	idlenessTimeout time.Duration,
 	auth *idb.ReAuthToken,
+) (idb.Connection, error) {
+	start := itime.Now()
+	conn, err := p.borrow(ctx, getServerNames, wait, boltLogger, idlenessTimeout, auth)
+	p.acquisitions.onBorrow(itime.Since(start), err)
+	if err != nil {
+		p.settled.notify()
+	}
+	if conn != nil {
+		ievents.NotifyConnection(p.config.ConnectionListener, events.ConnectionBorrowed, conn, "")
+	}
+	return conn, err
+}
+
+func (p *Pool) borrow(
+	ctx context.Context,
+	getServerNames func() []string,
+	wait bool,
+	boltLogger log.BoltLogger,
+	idlenessTimeout time.Duration,
+	auth *idb.ReAuthToken,
 ) (idb.Connection, error) {
 	for {
-		if p.closed {
+		if p.isClosed() {
 			return nil, &errorutil.PoolClosed{}
 		}
 		serverNames := getServerNames()
 		if len(serverNames) == 0 {
 			return nil, &errorutil.PoolOutOfServers{}
 		}
+		serverNames, err := p.admittedServers(serverNames)
+		if err != nil {
+			return nil, err
+		}
+		serverNames, orderedServerNames := p.localServersFirst(ctx, serverNames)
 		p.log.Debugf(log.Pool, p.logId, "Trying to borrow connection from %s", serverNames)
-		// Retrieve penalty for each server
-		penalties := p.getPenaltiesForServers(ctx, serverNames)
-		// Sort server penalties by lowest penalty
-		sort.Slice(penalties, func(i, j int) bool {
-			return penalties[i].penalty < penalties[j].penalty
-		})
-
-		var err error
 
 		var conn idb.Connection
-		for _, s := range penalties {
-			conn, err = p.tryBorrow(ctx, s.name, boltLogger, idlenessTimeout, auth)
+		for _, serverName := range orderedServerNames {
+			conn, err = p.tryBorrow(ctx, serverName, boltLogger, idlenessTimeout, auth)
 			if conn != nil {
 				return conn, nil
 			}
@@ -234,48 +367,93 @@ func (p *Pool) Borrow(
 
 		// Wait for a matching connection to be returned from another thread.
 		p.queueMut.Lock()
-		// By owning the queue lock, we are guaranteed that every call to Return from now on, until we release the
-		// lock, will notify us (or another waiter). To avoid starving this thread, we have to check once more whether
-		// any call to Return between checking for capacity above and acquiring the lock happened. In that case, we
-		// are no longer guaranteed to be notified, so we have to start over.
+		// By owning the queue lock, we are guaranteed that every connection made available from now on, until we
+		// release the lock, will be handed over to us (or to another waiter). To avoid starving this thread, we have
+		// to check once more whether any connection was made available between checking for capacity above and
+		// acquiring the lock. In that case, we are no longer guaranteed to be notified, so we have to start over.
 		if p.anyHasCapacity(serverNames) {
 			p.queueMut.Unlock()
 			continue
 		}
+		if maxQueueSize := p.config.MaxConnectionAcquisitionQueueSize; maxQueueSize > 0 && p.queue.Len() >= maxQueueSize {
+			p.queueMut.Unlock()
+			p.log.Warnf(log.Pool, p.logId, "Borrow rejected, %d borrowers already waiting", maxQueueSize)
+			return nil, &errorutil.PoolQueueFull{Servers: serverNames, QueueSize: maxQueueSize}
+		}
 		// Add a waiting request to the queue and unlock the queue to let other threads that return
 		// their connections access the queue.
 		q := &qitem{
-			wakeup: make(chan bool, 1),
+			servers: serverNames,
+			wakeup:  make(chan idb.Connection, 1),
 		}
 		e := p.queue.PushBack(q)
 		p.queueMut.Unlock()
 
 		p.log.Warnf(log.Pool, p.logId, "Borrow queued")
-		// Wait for either a wake-up signal that indicates that we got a connection or a timeout.
+		// Wait for either a connection to be handed over, a wake-up signal to try again or a timeout.
 		select {
-		case <-q.wakeup:
-			continue
+		case conn := <-q.wakeup:
+			if conn == nil {
+				continue
+			}
+			conn, err := p.checkHandedOver(ctx, conn, boltLogger, idlenessTimeout, auth)
+			if conn != nil || err != nil {
+				return conn, err
+			}
 		case <-ctx.Done():
 			p.queueMut.Lock()
 			p.queue.Remove(e)
-			if len(q.wakeup) == 1 {
-				// We got notified, but are no longer interested.
-				// Ask the next waiter.
-				if e := p.queue.Front(); e != nil {
-					queuedRequest := e.Value.(*qitem)
-					p.queue.Remove(e)
-					queuedRequest.wakeup <- true
-				}
-				p.queueMut.Unlock()
-				continue
+			var handedOver idb.Connection
+			woken := false
+			select {
+			case handedOver = <-q.wakeup:
+				woken = true
+			default:
 			}
 			p.queueMut.Unlock()
+			if handedOver != nil {
+				// We got a connection, but are no longer interested. Hand it over to the next waiter.
+				p.makeAvailable(ctx, handedOver.ServerName(), handedOver)
+			} else if woken {
+				// We got notified, but are no longer interested. Ask the next waiter.
+				for _, serverName := range serverNames {
+					p.wakeUpWaiterFor(serverName)
+				}
+			}
 			p.log.Warnf(log.Pool, p.logId, "Borrow time-out")
 			return nil, &errorutil.PoolTimeout{Err: ctx.Err(), Servers: serverNames}
 		}
 	}
 }
 
+// checkHandedOver checks the health of a connection handed over by makeAvailable, which is still registered as busy.
+// A nil connection and a nil error mean that the connection was unhealthy and the borrower should try again.
+func (p *Pool) checkHandedOver(
+	ctx context.Context,
+	conn idb.Connection,
+	boltLogger log.BoltLogger,
+	idlenessTimeout time.Duration,
+	auth *idb.ReAuthToken,
+) (idb.Connection, error) {
+	serverName := conn.ServerName()
+	p.serversMut.Lock()
+	srv := p.servers[serverName]
+	p.serversMut.Unlock()
+	if srv == nil {
+		return nil, nil
+	}
+	healthy, err := srv.healthCheck(ctx, conn, idlenessTimeout, auth, boltLogger, p.config.ConnectionListener)
+	if healthy {
+		return conn, nil
+	}
+	ievents.NotifyConnection(p.config.ConnectionListener, events.ConnectionDead, conn, events.FailedHealthCheckReason)
+	p.unreg(serverName, conn, itime.Now(), events.DeadReason)
+	if err != nil {
+		p.log.Debugf(log.Pool, p.logId, "Health check failed for %s: %s", serverName, err)
+	}
+	return nil, err
+}
+
 func (p *Pool) tryBorrow(
 	ctx context.Context,
 	serverName string,
@@ -293,17 +471,18 @@ func (p *Pool) tryBorrow(
 			srv.closing = false
 			connection := srv.getIdle()
 			if connection == nil {
-				if srv.size() >= p.config.MaxConnectionPoolSize {
+				if srv.size() >= p.maxSize(serverName) {
 					return nil, nil
 				}
 				break
 			}
 			unlock.Do(p.serversMut.Unlock)
-			healthy, err := srv.healthCheck(ctx, connection, idlenessTimeout, auth, boltLogger)
+			healthy, err := srv.healthCheck(ctx, connection, idlenessTimeout, auth, boltLogger, p.config.ConnectionListener)
 			if healthy {
 				return connection, nil
 			}
-			p.unreg(ctx, serverName, connection, itime.Now())
+			ievents.NotifyConnection(p.config.ConnectionListener, events.ConnectionDead, connection, events.FailedHealthCheckReason)
+			p.unreg(serverName, connection, itime.Now(), events.DeadReason)
 			if err != nil {
 				p.log.Debugf(log.Pool, p.logId, "Health check failed for %s: %s", serverName, err)
 				return nil, err
@@ -314,46 +493,66 @@ func (p *Pool) tryBorrow(
 		} else {
 			// Make sure that there is a server in the map
 			srv = NewServer()
+			srv.stats = p.statsOf(serverName)
 			p.servers[serverName] = srv
 			break
 		}
 	}
 
+	// A half-open circuit breaker only lets a limited number of connection attempts through
+	if !p.takeCircuitProbe(serverName) {
+		return nil, nil
+	}
 	srv.reservations++
 	unlock.Do(p.serversMut.Unlock)
 
 	// No idle connection, try to connect
 	p.log.Infof(log.Pool, p.logId, "Connecting to %s", serverName)
+	start := itime.Now()
 	c, err := p.connect(ctx, serverName, auth, p, boltLogger)
 	p.serversMut.Lock()
 	*unlock = sync.Once{}
 	srv.reservations--
 	if err != nil {
 		p.log.Warnf(log.Pool, p.logId, "Failed to connect to %s: %s", serverName, err)
+		srv.stats.failedToCreate++
+		srv.stats.lastFailedToCreate = itime.Now()
 		// FeatureNotSupportedError is not the server fault, don't penalize it
 		if _, ok := err.(*db.FeatureNotSupportedError); !ok {
 			srv.notifyFailedConnect(itime.Now())
 		}
+		// Failures of the server itself already reopened the circuit through OnDialError or OnIoError, others, such as
+		// authentication failures or cancellations, tell nothing about its availability
+		p.releaseCircuitProbe(serverName)
+		unlock.Do(p.serversMut.Unlock)
+		// The failed attempt leaves room for another one
+		p.wakeUpWaiterFor(serverName)
 		return nil, err
 	}
 
 	// Ok, got a connection, register the connection
 	srv.registerBusy(c)
 	srv.notifySuccessfulConnect()
+	srv.stats.created++
+	srv.stats.observeConnectionTime(itime.Since(start))
 	return c, nil
 }
 
-func (p *Pool) unreg(ctx context.Context, serverName string, c idb.Connection, now time.Time) {
+func (p *Pool) unreg(serverName string, c idb.Connection, now time.Time, reason string) {
+	pending := ievents.NewPending(p.config.ConnectionListener)
 	p.serversMut.Lock()
-	defer p.serversMut.Unlock()
-	p.unregLocked(ctx, serverName, c, now)
+	p.unregLocked(serverName, c, now, reason, pending)
+	p.serversMut.Unlock()
+	pending.Flush()
+	p.settled.notify()
+	// The closed connection leaves room for a new one
+	p.wakeUpWaiterFor(serverName)
 }
 
-func (p *Pool) unregLocked(ctx context.Context, serverName string, c idb.Connection, now time.Time) {
-	defer func() {
-		// Close connection in another thread to avoid potential long blocking operation during close.
-		go c.Close(ctx)
-	}()
+func (p *Pool) unregLocked(serverName string, c idb.Connection, now time.Time, reason string, pending *ievents.Pending) {
+	defer closeInBackground(c)
+	p.statsOf(serverName).closed++
+	pending.AddConnection(events.ConnectionClosed, c, reason)
 
 	server := p.servers[serverName]
 	// Check for strange condition of not finding the server.
@@ -368,18 +567,20 @@ func (p *Pool) unregLocked(ctx context.Context, serverName string, c idb.Connect
 	}
 }
 
-func (p *Pool) removeIdleOlderThanOnServer(ctx context.Context, serverName string, now time.Time, maxAge time.Duration) {
+func (p *Pool) removeIdleOlderThanOnServer(serverName string, now time.Time, maxAge, jitter time.Duration) {
+	pending := ievents.NewPending(p.config.ConnectionListener)
+	defer pending.Flush()
 	p.serversMut.Lock()
 	defer p.serversMut.Unlock()
 	server := p.servers[serverName]
 	if server == nil {
 		return
 	}
-	server.removeIdleOlderThan(ctx, now, maxAge)
+	server.removeIdleOlderThan(now, maxAge, jitter, pending)
 }
 
 func (p *Pool) Return(ctx context.Context, c idb.Connection) {
-	if p.closed {
+	if p.isClosed() {
 		p.log.Warnf(log.Pool, p.logId, "Trying to return connection to closed pool")
 		return
 	}
@@ -388,10 +589,15 @@ func (p *Pool) Return(ctx context.Context, c idb.Connection) {
 	serverName := c.ServerName()
 	isAlive := c.IsAlive()
 	p.log.Debugf(log.Pool, p.logId, "Returning connection to %s {alive:%t}", serverName, isAlive)
+	ievents.NotifyConnection(p.config.ConnectionListener, events.ConnectionReturned, c, "")
+	if !isAlive {
+		ievents.NotifyConnection(p.config.ConnectionListener, events.ConnectionDead, c, events.ReturnedDeadReason)
+	}
 
 	// If the connection is dead, remove all other idle connections on the same server that older
 	// or of the same age as the dead connection, otherwise perform normal cleanup of old connections
 	maxAge := p.config.MaxConnectionLifetime
+	jitter := p.config.MaxConnectionLifetimeJitter
 	now := itime.Now()
 	age := now.Sub(c.Birthdate())
 	if !isAlive {
@@ -399,50 +605,180 @@ func (p *Pool) Return(ctx context.Context, c idb.Connection) {
 		// might also be bad, remove the idle ones.
 		if age < maxAge {
 			maxAge = age
+			jitter = 0
+		}
+	}
+	p.removeIdleOlderThanOnServer(serverName, now, maxAge, jitter)
+
+	if resetReporter, ok := c.(idb.ResetStateReporter); ok && isAlive && resetReporter.NeedsReset() {
+		// Resetting involves a round trip to the server, do not make the caller wait for it.
+		// The connection remains busy, and thus cannot be borrowed, until the reset succeeds.
+		c.SetBoltLogger(nil)
+		if !p.startReset() {
+			p.unreg(serverName, c, now, events.PoolClosedReason)
+			return
 		}
+		go func() {
+			defer p.endReset()
+			resetCtx, cancel := context.WithTimeout(p.resetsCtx, backgroundResetTimeout)
+			defer cancel()
+			p.release(resetCtx, c, serverName, age, now)
+		}()
+		return
 	}
-	p.removeIdleOlderThanOnServer(ctx, serverName, now, maxAge)
+	p.release(ctx, c, serverName, age, now)
+}
+
+// startReset registers a background reset, unless the pool is closed
+func (p *Pool) startReset() bool {
+	p.resetsMut.Lock()
+	defer p.resetsMut.Unlock()
+	if p.isClosed() {
+		return false
+	}
+	p.resets.Add(1)
+	p.numResets++
+	return true
+}
 
+func (p *Pool) endReset() {
+	p.resetsMut.Lock()
+	defer p.resetsMut.Unlock()
+	p.numResets--
+	p.resets.Done()
+	p.settled.notify()
+}
+
+func (p *Pool) resetting() bool {
+	p.resetsMut.Lock()
+	defer p.resetsMut.Unlock()
+	return p.numResets > 0
+}
+
+// release resets the returned connection and makes it available to borrowers again, unless it is dead or too old
+func (p *Pool) release(ctx context.Context, c idb.Connection, serverName string, age time.Duration, now time.Time) {
+	if p.isClosed() {
+		p.unreg(serverName, c, now, events.PoolClosedReason)
+		return
+	}
+	isAlive := c.IsAlive()
 	// Prepare connection for being used by someone else if is alive.
 	// Since reset could find the connection to be in a bad state or non-recoverable state,
 	// make sure again that it really is alive.
 	if isAlive {
+		resetReporter, ok := c.(idb.ResetStateReporter)
+		roundTrip := ok && resetReporter.NeedsReset()
+		start := itime.Now()
 		c.Reset(ctx)
+		ievents.NotifyConnection(p.config.ConnectionListener, events.ConnectionReset, c, "")
 		isAlive = c.IsAlive()
+		if !isAlive {
+			ievents.NotifyConnection(p.config.ConnectionListener, events.ConnectionDead, c, events.FailedResetReason)
+		} else if roundTrip {
+			p.observeRoundTripTime(serverName, itime.Since(start))
+		}
 	}
+	p.onCircuitReturn(serverName, isAlive)
 
 	c.SetBoltLogger(nil)
 
 	// Shouldn't return a too old or dead connection back to the pool
-	if !isAlive || age >= p.config.MaxConnectionLifetime {
-		p.unreg(ctx, serverName, c, now)
+	if !isAlive || age >= p.config.MaxConnectionLifetime-lifetimeJitter(c, p.config.MaxConnectionLifetimeJitter) {
+		reason := events.ExpiredReason
+		if !isAlive {
+			reason = events.DeadReason
+		}
+		p.unreg(serverName, c, now, reason)
 		p.log.Infof(log.Pool, p.logId, "Unregistering dead or too old connection to %s", serverName)
+		return
 	}
 
-	if isAlive {
-		// Just put it back in the list of idle connections for this server
-		p.serversMut.Lock()
-		server := p.servers[serverName]
-		if server != nil { // Strange when server not found
-			server.returnBusy(ctx, c)
-			if server.closing && server.size() == 0 {
-				delete(p.servers, serverName)
-			}
-		} else {
-			p.log.Warnf(log.Pool, p.logId, "Server %s not found", serverName)
+	p.makeAvailable(ctx, serverName, c)
+}
+
+// makeAvailable hands the busy connection over to the borrower waiting the longest for its server, if any, or puts
+// it back in the list of idle connections of its server.
+func (p *Pool) makeAvailable(ctx context.Context, serverName string, c idb.Connection) {
+	pending := ievents.NewPending(p.config.ConnectionListener)
+	defer pending.Flush()
+	defer p.settled.notify()
+	mustClose := false
+	defer func() {
+		// Closing involves a round trip to the server, do not hold the locks meanwhile
+		if mustClose {
+			c.Close(ctx)
+		}
+	}()
+	p.queueMut.Lock()
+	defer p.queueMut.Unlock()
+	p.serversMut.Lock()
+	defer p.serversMut.Unlock()
+	server := p.servers[serverName]
+	if server == nil {
+		// The pool got closed meanwhile, or the connection was not registered in the first place
+		p.log.Warnf(log.Pool, p.logId, "Server %s not found, closing connection", serverName)
+		reason := events.ServerDeactivatedReason
+		if p.isClosed() {
+			reason = events.PoolClosedReason
 		}
-		p.serversMut.Unlock()
+		p.statsOf(serverName).closed++
+		pending.AddConnection(events.ConnectionClosed, c, reason)
+		mustClose = true
+		return
+	}
+	if !server.closing {
+		if q := p.dequeueWaiterLocked(serverName); q != nil {
+			q.wakeup <- c
+			return
+		}
+	}
+	mustClose = server.returnBusy(c, pending)
+	if server.closing {
+		// returnBusy closed the connection, which leaves room for a new one
+		if q := p.dequeueWaiterLocked(serverName); q != nil {
+			q.wakeup <- nil
+		}
+		if server.size() == 0 {
+			delete(p.servers, serverName)
+		}
+	}
+}
+
+// wakeUpWaiterFor tells the borrower waiting the longest for the server to try borrowing again, if any.
+func (p *Pool) wakeUpWaiterFor(serverName string) {
+	p.queueMut.Lock()
+	defer p.queueMut.Unlock()
+	if q := p.dequeueWaiterLocked(serverName); q != nil {
+		q.wakeup <- nil
 	}
+}
 
-	// Check if there is anyone in the queue waiting for a connection to this server.
+// wakeUpWaitersFor tells all the borrowers waiting for the server to try borrowing again.
+func (p *Pool) wakeUpWaitersFor(serverName string) {
 	p.queueMut.Lock()
+	defer p.queueMut.Unlock()
+	for q := p.dequeueWaiterLocked(serverName); q != nil; q = p.dequeueWaiterLocked(serverName) {
+		q.wakeup <- nil
+	}
+}
 
-	if e := p.queue.Front(); e != nil {
-		queuedRequest := e.Value.(*qitem)
-		p.queue.Remove(e)
-		queuedRequest.wakeup <- true
+// Must be called while holding the queue lock
+func (p *Pool) dequeueWaiterLocked(serverName string) *qitem {
+	for e := p.queue.Front(); e != nil; e = e.Next() {
+		if q := e.Value.(*qitem); q.waitsFor(serverName) {
+			p.queue.Remove(e)
+			return q
+		}
 	}
-	p.queueMut.Unlock()
+	return nil
+}
+
+// maxSize returns the maximum number of connections to the server, see config.Config MaxConnectionPoolSizePerServer
+func (p *Pool) maxSize(serverName string) int {
+	if size, ok := p.config.MaxConnectionPoolSizePerServer[serverName]; ok && size > 0 {
+		return size
+	}
+	return p.config.MaxConnectionPoolSize
 }
 
 func (p *Pool) OnNeo4jError(ctx context.Context, connection idb.Connection, error *db.Neo4jError) error {
@@ -481,23 +817,30 @@ func (p *Pool) OnNeo4jError(ctx context.Context, connection idb.Connection, erro
 	return nil
 }
 
-func (p *Pool) OnIoError(ctx context.Context, connection idb.Connection, _ error) {
+func (p *Pool) OnIoError(ctx context.Context, connection idb.Connection, err error) {
+	p.onCircuitFailure(connection.ServerName(), err)
 	p.deactivate(ctx, connection.ServerName())
 }
 
-func (p *Pool) OnDialError(ctx context.Context, serverName string, _ error) {
+func (p *Pool) OnDialError(ctx context.Context, serverName string, err error) {
+	p.onCircuitFailure(serverName, err)
 	p.deactivate(ctx, serverName)
 }
 
 func (p *Pool) deactivate(ctx context.Context, serverName string) {
 	p.log.Debugf(log.Pool, p.logId, "Deactivating server %s", serverName)
+	ievents.Notify(p.config.ConnectionListener, events.ConnectionEvent{Type: events.ServerDeactivated, Server: serverName})
 	p.router.InvalidateServer(serverName)
+	pending := ievents.NewPending(p.config.ConnectionListener)
 	p.serversMut.Lock()
-	defer p.serversMut.Unlock()
 	server := p.servers[serverName]
 	if server != nil {
-		server.startClosing(ctx)
+		server.startClosing(ctx, pending)
 	}
+	p.serversMut.Unlock()
+	pending.Flush()
+	// Waiters will not be handed over connections to the server anymore, let them try the remaining servers
+	p.wakeUpWaitersFor(serverName)
 }
 
 func (p *Pool) deactivateWriter(serverName string, db string) {
@@ -35,6 +35,7 @@ import (
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
 	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
 	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/loadbalancing"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
 )
 
@@ -308,9 +309,485 @@ func TestPoolBorrowReturn(outer *testing.T) {
 		wg.Wait()
 		AssertTrue(t, reAuthCalled)
 	})
+
+	outer.Run("Returns without waiting for connections to be reset", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: maxAge, MaxConnectionPoolSize: 1}
+		conn := &resettingConnFake{ConnFake: &ConnFake{Name: "srv1", Alive: true, Birth: time.Now()}, resetting: make(chan struct{})}
+		p := New(&conf, func(context.Context, string, *idb.ReAuthToken, bolt.ConnectionErrorListener, log.BoltLogger) (idb.Connection, error) {
+			return conn, nil
+		}, logger, "pool id")
+		defer p.Close(ctx)
+		c, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, c, err)
+
+		p.Return(ctx, c)
+
+		// the connection cannot be borrowed until reset
+		_, err = p.Borrow(ctx, getServers([]string{"srv1"}), false, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		AssertError(t, err)
+		close(conn.resetting)
+		c, err = p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, c, err)
+		AssertDeepEquals(t, c, conn)
+	})
+
+	outer.Run("Unregisters connections dying on background reset", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: maxAge, MaxConnectionPoolSize: 1}
+		conn := &resettingConnFake{ConnFake: &ConnFake{Name: "srv1", Alive: true, Birth: time.Now()}, resetting: make(chan struct{}), dies: true}
+		p := New(&conf, func(context.Context, string, *idb.ReAuthToken, bolt.ConnectionErrorListener, log.BoltLogger) (idb.Connection, error) {
+			return conn, nil
+		}, logger, "pool id")
+		defer p.Close(ctx)
+		c, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, c, err)
+
+		closeErrs := make(chan error, 1)
+		conn.onClose = func(ctx context.Context) {
+			// the closure outlives the background reset
+			for p.resetting() {
+				time.Sleep(time.Millisecond)
+			}
+			closeErrs <- ctx.Err()
+		}
+
+		p.Return(ctx, c)
+		close(conn.resetting)
+
+		for len(p.getServers()) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+		AssertNoError(t, <-closeErrs)
+	})
+
+	outer.Run("Interrupts and waits for background resets on close", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: maxAge, MaxConnectionPoolSize: 1}
+		conn := &resettingConnFake{ConnFake: &ConnFake{Name: "srv1", Alive: true, Birth: time.Now()}, resetting: make(chan struct{})}
+		p := New(&conf, func(context.Context, string, *idb.ReAuthToken, bolt.ConnectionErrorListener, log.BoltLogger) (idb.Connection, error) {
+			return conn, nil
+		}, logger, "pool id")
+		c, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, c, err)
+		p.Return(ctx, c)
+
+		p.Close(ctx)
+
+		AssertFalse(t, p.resetting())
+		for !conn.isClosed() {
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	outer.Run("Closes connections returned after close", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: maxAge, MaxConnectionPoolSize: 1}
+		conn := &resettingConnFake{ConnFake: &ConnFake{Name: "srv1", Alive: true, Birth: time.Now()}, resetting: make(chan struct{})}
+		close(conn.resetting)
+		p := New(&conf, nil, logger, "pool id")
+		p.Close(ctx)
+
+		p.release(ctx, conn, "srv1", 0, time.Now())
+
+		for !conn.isClosed() {
+			time.Sleep(time.Millisecond)
+		}
+	})
+
+	outer.Run("Closes connections made available to servers no longer in the pool", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: maxAge, MaxConnectionPoolSize: 1}
+		conn := &resettingConnFake{ConnFake: &ConnFake{Name: "srv1", Alive: true, Birth: time.Now()}, resetting: make(chan struct{})}
+		p := New(&conf, nil, logger, "pool id")
+		defer p.Close(ctx)
+
+		p.makeAvailable(ctx, "srv1", conn)
+
+		AssertTrue(t, conn.isClosed())
+		AssertIntEqual(t, len(p.getServers()), 0)
+	})
+}
+
+// resettingConnFake is a connection whose reset requires a round trip to the server, which completes once
+// resetting is closed
+type resettingConnFake struct {
+	*ConnFake
+	resetting chan struct{}
+	dies      bool
+	closed    bool
+	onClose   func(context.Context)
+	mut       sync.Mutex
+}
+
+func (c *resettingConnFake) NeedsReset() bool {
+	return true
+}
+
+func (c *resettingConnFake) Reset(ctx context.Context) {
+	interrupted := false
+	select {
+	case <-c.resetting:
+	case <-ctx.Done():
+		interrupted = true
+	}
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if c.dies || interrupted {
+		c.ConnFake.Alive = false
+	}
+}
+
+func (c *resettingConnFake) Close(ctx context.Context) {
+	if c.onClose != nil {
+		c.onClose(ctx)
+	}
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.closed = true
+}
+
+func (c *resettingConnFake) isClosed() bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.closed
+}
+
+func (c *resettingConnFake) IsAlive() bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.ConnFake.Alive
 }
 
 // Resource usage scenarios
+func TestPoolQueue(outer *testing.T) {
+	connect := func(_ context.Context, s string, _ *idb.ReAuthToken, _ bolt.ConnectionErrorListener, _ log.BoltLogger) (idb.Connection, error) {
+		return &ConnFake{Name: s, Alive: true, Birth: time.Now()}, nil
+	}
+	borrowAsync := func(p *Pool, serverNames ...string) chan idb.Connection {
+		borrowed := make(chan idb.Connection, 1)
+		go func() {
+			conn, err := p.Borrow(ctx, getServers(serverNames), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+			if err != nil {
+				borrowed <- nil
+				return
+			}
+			borrowed <- conn
+		}()
+		return borrowed
+	}
+
+	outer.Run("hands returned connections over to waiters of the same server", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		reader, err := p.Borrow(ctx, getServers([]string{"reader"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, reader, err)
+		writer, err := p.Borrow(ctx, getServers([]string{"writer"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, writer, err)
+		readerWaiter := borrowAsync(p, "reader")
+		waitForBorrowers(p, 1)
+		writerWaiter := borrowAsync(p, "writer")
+		waitForBorrowers(p, 2)
+
+		p.Return(ctx, writer)
+
+		AssertDeepEquals(t, <-writerWaiter, writer)
+		AssertIntEqual(t, p.queueSize(), 1)
+		p.Return(ctx, reader)
+		AssertDeepEquals(t, <-readerWaiter, reader)
+	})
+
+	outer.Run("serves waiters of a server in FIFO order", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		first := borrowAsync(p, "srv1")
+		waitForBorrowers(p, 1)
+		second := borrowAsync(p, "srv1")
+		waitForBorrowers(p, 2)
+
+		p.Return(ctx, conn)
+		conn = <-first
+		AssertNotNil(t, conn)
+		AssertIntEqual(t, len(second), 0)
+		p.Return(ctx, conn)
+
+		AssertNotNil(t, <-second)
+	})
+
+	outer.Run("fails fast when too many borrowers wait", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1, MaxConnectionAcquisitionQueueSize: 1}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		waiter := borrowAsync(p, "srv1")
+		waitForBorrowers(p, 1)
+
+		_, err = p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+
+		AssertDeepEquals(t, err, &errorutil.PoolQueueFull{Servers: []string{"srv1"}, QueueSize: 1})
+		p.Return(ctx, conn)
+		AssertNotNil(t, <-waiter)
+	})
+
+	outer.Run("limits connections per server", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 2, MaxConnectionPoolSizePerServer: map[string]int{"small": 1}}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+
+		for _, serverName := range []string{"small", "large", "large"} {
+			conn, err := p.Borrow(ctx, getServers([]string{serverName}), false, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+			assertConnection(t, conn, err)
+		}
+		_, err := p.Borrow(ctx, getServers([]string{"small"}), false, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		AssertDeepEquals(t, err, &errorutil.PoolFull{Servers: []string{"small"}})
+	})
+
+	outer.Run("wakes up waiters when connections are closed", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		waiter := borrowAsync(p, "srv1")
+		waitForBorrowers(p, 1)
+
+		conn.(*ConnFake).Alive = false
+		p.Return(ctx, conn)
+
+		newConn := <-waiter
+		AssertNotNil(t, newConn)
+		AssertNotDeepEquals(t, newConn, conn)
+	})
+
+	outer.Run("wakes up waiters when connections to closing servers are returned", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 1}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		waiter := borrowAsync(p, "srv1")
+		waitForBorrowers(p, 1)
+		p.serversMut.Lock()
+		p.servers["srv1"].startClosing(ctx, nil)
+		p.serversMut.Unlock()
+
+		p.Return(ctx, conn)
+
+		newConn := <-waiter
+		AssertNotNil(t, newConn)
+		AssertNotDeepEquals(t, newConn, conn)
+	})
+
+	outer.Run("wakes up waiters of deactivated servers", func(t *testing.T) {
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 2, MaxConnectionPoolSizePerServer: map[string]int{"srv1": 1}}
+		p := New(&conf, connect, logger, "pool id")
+		p.SetRouter(&RouterFake{})
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		serversMut := sync.Mutex{}
+		servers := []string{"srv1"}
+		getServerNames := func() []string {
+			serversMut.Lock()
+			defer serversMut.Unlock()
+			return servers
+		}
+		waiters := make(chan idb.Connection, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				conn, _ := p.Borrow(ctx, getServerNames, true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+				waiters <- conn
+			}()
+		}
+		waitForBorrowers(p, 2)
+
+		serversMut.Lock()
+		servers = []string{"srv2"}
+		serversMut.Unlock()
+		p.deactivate(ctx, "srv1")
+
+		first, second := <-waiters, <-waiters
+		AssertNotNil(t, first)
+		AssertNotNil(t, second)
+		AssertStringEqual(t, first.ServerName(), "srv2")
+		AssertStringEqual(t, second.ServerName(), "srv2")
+	})
+}
+
+func TestPoolLoadBalancing(outer *testing.T) {
+	connect := func(_ context.Context, s string, _ *idb.ReAuthToken, _ bolt.ConnectionErrorListener, _ log.BoltLogger) (idb.Connection, error) {
+		return &ConnFake{Name: s, Alive: true, Birth: time.Now()}, nil
+	}
+
+	outer.Run("borrows from the first server ordered by the load balancer", func(t *testing.T) {
+		var candidates []loadbalancing.Server
+		conf := config.Config{
+			MaxConnectionLifetime: 1 * time.Hour,
+			MaxConnectionPoolSize: 2,
+			LoadBalancer: loadbalancing.LoadBalancerFunc(func(servers []loadbalancing.Server) []string {
+				candidates = servers
+				return []string{"srv2", "srv1"}
+			}),
+		}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1", "srv2"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+
+		AssertStringEqual(t, conn.ServerName(), "srv2")
+		AssertIntEqual(t, len(candidates), 2)
+		AssertStringEqual(t, candidates[0].Address, "srv1")
+		AssertIntEqual(t, candidates[0].MaxSize, 2)
+		AssertStringEqual(t, candidates[1].Address, "srv2")
+	})
+
+	outer.Run("passes server statistics to the load balancer", func(t *testing.T) {
+		var candidates []loadbalancing.Server
+		conf := config.Config{
+			MaxConnectionLifetime: 1 * time.Hour,
+			MaxConnectionPoolSize: 3,
+			LoadBalancer: loadbalancing.LoadBalancerFunc(func(servers []loadbalancing.Server) []string {
+				candidates = servers
+				return []string{servers[0].Address}
+			}),
+		}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn1, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn1, err)
+		conn2, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn2, err)
+		p.Return(ctx, conn2)
+
+		conn3, err := p.Borrow(ctx, getServers([]string{"srv1"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn3, err)
+
+		AssertIntEqual(t, len(candidates), 1)
+		AssertIntEqual(t, candidates[0].Idle, 1)
+		AssertIntEqual(t, candidates[0].InUse, 1)
+		AssertIntEqual(t, candidates[0].Creating, 0)
+		AssertFalse(t, candidates[0].FailedRecently)
+	})
+
+	outer.Run("does not borrow from servers left out by the load balancer", func(t *testing.T) {
+		conf := config.Config{
+			MaxConnectionLifetime:        1 * time.Hour,
+			MaxConnectionPoolSize:        1,
+			ConnectionAcquisitionTimeout: 10 * time.Millisecond,
+			LoadBalancer: loadbalancing.LoadBalancerFunc(func(servers []loadbalancing.Server) []string {
+				return []string{"srv1"}
+			}),
+		}
+		p := New(&conf, connect, logger, "pool id")
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1", "srv2"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+		AssertStringEqual(t, conn.ServerName(), "srv1")
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err = p.Borrow(timeoutCtx, getServers([]string{"srv1", "srv2"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+
+		AssertError(t, err)
+		AssertIntEqual(t, len(p.servers), 1)
+	})
+}
+
+func TestPoolLocality(outer *testing.T) {
+	connect := func(_ context.Context, s string, _ *idb.ReAuthToken, _ bolt.ConnectionErrorListener, _ log.BoltLogger) (idb.Connection, error) {
+		if s == "local-down" {
+			return nil, errors.New("connection refused")
+		}
+		return &ConnFake{Name: s, Alive: true, Birth: time.Now()}, nil
+	}
+	newPool := func(spillOver loadbalancing.SpillOver) *Pool {
+		conf := config.Config{
+			MaxConnectionLifetime: 1 * time.Hour,
+			MaxConnectionPoolSize: 1,
+			Locality: &loadbalancing.Locality{
+				LocalZone: "zone1",
+				Zones:     map[string]string{"local": "zone1", "local-down": "zone1", "remote": "zone2"},
+				SpillOver: spillOver,
+			},
+		}
+		return New(&conf, connect, logger, "pool id")
+	}
+	borrowWithin := func(p *Pool, servers []string, timeout time.Duration) (idb.Connection, error) {
+		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return p.Borrow(timeoutCtx, getServers(servers), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+	}
+
+	outer.Run("borrows from local servers first", func(t *testing.T) {
+		p := newPool(loadbalancing.SpillOverWhenUnavailable)
+		defer p.Close(ctx)
+
+		conn, err := p.Borrow(ctx, getServers([]string{"remote", "local"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+
+		assertConnection(t, conn, err)
+		AssertStringEqual(t, conn.ServerName(), "local")
+	})
+
+	outer.Run("borrows from remote servers without local server", func(t *testing.T) {
+		p := newPool(loadbalancing.SpillOverNever)
+		defer p.Close(ctx)
+
+		conn, err := p.Borrow(ctx, getServers([]string{"remote"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+
+		assertConnection(t, conn, err)
+		AssertStringEqual(t, conn.ServerName(), "remote")
+	})
+
+	outer.Run("spills over when local servers are unavailable", func(t *testing.T) {
+		p := newPool(loadbalancing.SpillOverWhenUnavailable)
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"remote", "local"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+
+		conn, err = p.Borrow(ctx, getServers([]string{"remote", "local"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+
+		assertConnection(t, conn, err)
+		AssertStringEqual(t, conn.ServerName(), "remote")
+	})
+
+	outer.Run("waits for full local servers when spilling over only when unhealthy", func(t *testing.T) {
+		p := newPool(loadbalancing.SpillOverWhenUnhealthy)
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"remote", "local"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+
+		_, err = borrowWithin(p, []string{"remote", "local"}, 10*time.Millisecond)
+
+		AssertError(t, err)
+		AssertTrue(t, p.getServers()["remote"] == nil)
+	})
+
+	outer.Run("spills over when local servers are unhealthy", func(t *testing.T) {
+		p := newPool(loadbalancing.SpillOverWhenUnhealthy)
+		defer p.Close(ctx)
+		_, err := p.Borrow(ctx, getServers([]string{"remote", "local-down"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		AssertError(t, err)
+
+		conn, err := p.Borrow(ctx, getServers([]string{"remote", "local-down"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+
+		assertConnection(t, conn, err)
+		AssertStringEqual(t, conn.ServerName(), "remote")
+	})
+
+	outer.Run("never spills over", func(t *testing.T) {
+		p := newPool(loadbalancing.SpillOverNever)
+		defer p.Close(ctx)
+		_, err := p.Borrow(ctx, getServers([]string{"remote", "local-down"}), true, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		AssertError(t, err)
+
+		_, err = borrowWithin(p, []string{"remote", "local-down"}, 10*time.Millisecond)
+
+		AssertError(t, err)
+		AssertTrue(t, p.getServers()["remote"] == nil)
+	})
+}
+
 func TestPoolResourceUsage(ot *testing.T) {
 	maxAge := 1 * time.Second
 	birthdate := time.Now()
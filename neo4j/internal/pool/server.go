@@ -23,7 +23,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/events"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	ievents "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/events"
 	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
 )
@@ -38,12 +40,14 @@ type server struct {
 	failedConnectAt time.Time
 	roundRobin      uint32
 	closing         bool
+	stats           *serverStats
 }
 
 func NewServer() *server {
 	return &server{
-		idle: list.List{},
-		busy: list.List{},
+		idle:  list.List{},
+		busy:  list.List{},
+		stats: &serverStats{},
 	}
 }
 
@@ -70,11 +74,13 @@ func (s *server) healthCheck(
 	connection db.Connection,
 	idlenessTimeout time.Duration,
 	auth *db.ReAuthToken,
-	boltLogger log.BoltLogger) (healthy bool, _ error) {
+	boltLogger log.BoltLogger,
+	listener events.ConnectionListener) (healthy bool, _ error) {
 
 	connection.SetBoltLogger(boltLogger)
 	if itime.Since(connection.IdleDate()) > idlenessTimeout {
 		connection.ForceReset(ctx)
+		ievents.NotifyConnection(listener, events.ConnectionReset, connection, "")
 		if !connection.IsAlive() {
 			return false, ctx.Err()
 		}
@@ -137,14 +143,17 @@ func (s *server) calculatePenalty(now time.Time) uint32 {
 	return penalty
 }
 
-// Returns a busy connection, makes it idle
-func (s *server) returnBusy(ctx context.Context, c db.Connection) {
+// Returns a busy connection, makes it idle, unless the server is closing, in which case the caller must close the
+// connection once it has released the pool locks
+func (s *server) returnBusy(c db.Connection, pending *ievents.Pending) (mustClose bool) {
 	s.unregisterBusy(c)
 	if s.closing {
-		c.Close(ctx)
-	} else {
-		s.idle.PushFront(c)
+		s.stats.closed++
+		pending.AddConnection(events.ConnectionClosed, c, events.ServerDeactivatedReason)
+		return true
 	}
+	s.idle.PushFront(c)
+	return false
 }
 
 // Number of idle connections
@@ -180,26 +189,66 @@ func (s *server) size() int {
 	return s.busy.Len() + s.idle.Len() + s.reservations
 }
 
-func (s *server) removeIdleOlderThan(ctx context.Context, now time.Time, maxAge time.Duration) {
+// Removes idle connections older than maxAge, shortened by the lifetime jitter of each connection
+func (s *server) removeIdleOlderThan(now time.Time, maxAge, jitter time.Duration, pending *ievents.Pending) {
 	e := s.idle.Front()
 	for e != nil {
 		n := e.Next()
 		c := e.Value.(db.Connection)
 
 		age := now.Sub(c.Birthdate())
-		if age >= maxAge {
+		if age >= maxAge-lifetimeJitter(c, jitter) {
 			s.idle.Remove(e)
-			go c.Close(ctx)
+			closeInBackground(c)
+			s.stats.closed++
+			pending.AddConnection(events.ConnectionClosed, c, events.ExpiredReason)
 		}
 
 		e = n
 	}
 }
 
-func (s *server) closeAll(ctx context.Context) {
-	closeAndEmptyConnections(ctx, &s.idle)
+// Removes the connections idle for at least maxIdleTime, longest idle first, keeping at least minIdle idle connections
+func (s *server) removeIdleLongerThan(now time.Time, maxIdleTime time.Duration, minIdle int, pending *ievents.Pending) {
+	e := s.idle.Back()
+	for e != nil && s.idle.Len() > minIdle {
+		p := e.Prev()
+		c := e.Value.(db.Connection)
+
+		if now.Sub(c.IdleDate()) >= maxIdleTime {
+			s.idle.Remove(e)
+			closeInBackground(c)
+			s.stats.closed++
+			pending.AddConnection(events.ConnectionClosed, c, events.IdleTimeoutReason)
+		}
+
+		e = p
+	}
+}
+
+// Moves at most limit connections idle for at least idleTime to the busy list, longest idle first, and returns them
+func (s *server) takeIdleLongerThan(now time.Time, idleTime time.Duration, limit int) []db.Connection {
+	var taken []db.Connection
+	e := s.idle.Back()
+	for e != nil && len(taken) < limit {
+		p := e.Prev()
+		c := e.Value.(db.Connection)
+
+		if now.Sub(c.IdleDate()) >= idleTime {
+			s.idle.Remove(e)
+			s.busy.PushFront(c)
+			taken = append(taken, c)
+		}
+
+		e = p
+	}
+	return taken
+}
+
+func (s *server) closeAll(ctx context.Context, pending *ievents.Pending) {
+	s.stats.closed += closeAndEmptyConnections(ctx, &s.idle, pending, events.PoolClosedReason)
 	// Closing the busy connections could mean here that we do close from another thread.
-	closeAndEmptyConnections(ctx, &s.busy)
+	s.stats.closed += closeAndEmptyConnections(ctx, &s.busy, pending, events.PoolClosedReason)
 }
 
 func (s *server) executeForAllConnections(callback func(c db.Connection)) {
@@ -211,15 +260,29 @@ func (s *server) executeForAllConnections(callback func(c db.Connection)) {
 	}
 }
 
-func (s *server) startClosing(ctx context.Context) {
+func (s *server) startClosing(ctx context.Context, pending *ievents.Pending) {
 	s.closing = true
-	closeAndEmptyConnections(ctx, &s.idle)
+	s.stats.closed += closeAndEmptyConnections(ctx, &s.idle, pending, events.ServerDeactivatedReason)
 }
 
-func closeAndEmptyConnections(ctx context.Context, l *list.List) {
+// closeAndEmptyConnections returns the number of closed connections
+func closeAndEmptyConnections(ctx context.Context, l *list.List, pending *ievents.Pending, reason string) int64 {
+	closed := int64(l.Len())
 	for e := l.Front(); e != nil; e = e.Next() {
 		c := e.Value.(db.Connection)
 		go c.Close(ctx)
+		pending.AddConnection(events.ConnectionClosed, c, reason)
 	}
 	l.Init()
+	return closed
+}
+
+// closeInBackground closes the connection in another goroutine, to avoid blocking on the round trip to the server.
+// The closure outlives the operation that discarded the connection, whose context it can therefore not use.
+func closeInBackground(c db.Connection) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), backgroundCloseTimeout)
+		defer cancel()
+		c.Close(ctx)
+	}()
 }
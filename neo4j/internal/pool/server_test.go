@@ -78,7 +78,7 @@ func TestServer(ot *testing.T) {
 		c3 := s.getIdle()
 		assertNilConnection(t, c3)
 
-		s.returnBusy(context.Background(), c2)
+		s.returnBusy(c2, nil)
 		c3 = s.getIdle()
 		assertConnection(t, c3)
 	})
@@ -91,7 +91,7 @@ func TestServer(ot *testing.T) {
 
 		// Let the connection in the middle be too old
 		conns[1].Birth = now.Add(-20 * time.Second)
-		s.removeIdleOlderThan(context.Background(), now, 10*time.Second)
+		s.removeIdleOlderThan(now, 10*time.Second, 0, nil)
 		assertSize(t, s, 2)
 
 		// Should be able to borrow twice
@@ -103,11 +103,11 @@ func TestServer(ot *testing.T) {
 		assertNilConnection(t, b3)
 
 		// Return the connections and let all of them be too old
-		s.returnBusy(context.Background(), b1)
-		s.returnBusy(context.Background(), b2)
+		s.returnBusy(b1, nil)
+		s.returnBusy(b2, nil)
 		conns[0].Birth = now.Add(-20 * time.Second)
 		conns[2].Birth = now.Add(-20 * time.Second)
-		s.removeIdleOlderThan(context.Background(), now, 10*time.Second)
+		s.removeIdleOlderThan(now, 10*time.Second, 0, nil)
 
 		// Shouldn't be able to borrow anything and size should be zero
 		b1 = s.getIdle()
@@ -119,7 +119,7 @@ func TestServer(ot *testing.T) {
 		s := NewServer()
 		// Register and return three connections
 		_, _ = populateServer(s, time.Now(), 3, 3)
-		s.startClosing(context.Background())
+		s.startClosing(context.Background(), nil)
 		if s.idle.Len() != 0 {
 			t.Errorf("Expected 0 idle connections, found %d", s.idle.Len())
 		}
@@ -132,7 +132,7 @@ func TestServer(ot *testing.T) {
 		s := NewServer()
 		// Register and return three connections
 		_, _ = populateServer(s, time.Now(), 3, 3)
-		s.closeAll(context.Background())
+		s.closeAll(context.Background(), nil)
 		if s.idle.Len() != 0 {
 			t.Errorf("Expected 0 idle connections, found %d", s.idle.Len())
 		}
@@ -165,7 +165,7 @@ func TestServerPenalty(t *testing.T) {
 	// Return the busy connection to srv1
 	// Now srv2 should have higher penalty than srv1 since using srv2 would require a new
 	// connection.
-	srv1.returnBusy(context.Background(), c11)
+	srv1.returnBusy(c11, nil)
 	assertPenaltiesGreaterThan(srv2, srv1, now)
 
 	// Add an idle connection to srv2 to make both servers have one idle connection each.
@@ -179,9 +179,9 @@ func TestServerPenalty(t *testing.T) {
 	// Get the connection from srv1 and return it, now srv1 should have higher penalty.
 	ctx := context.Background()
 	idle := srv1.getIdle()
-	_, _ = srv1.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil)
+	_, _ = srv1.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil, nil)
 	testutil.AssertDeepEquals(t, idle, c11)
-	srv1.returnBusy(context.Background(), c11)
+	srv1.returnBusy(c11, nil)
 	assertPenaltiesGreaterThan(srv1, srv2, now)
 
 	// Add one more connection each to the servers
@@ -194,22 +194,22 @@ func TestServerPenalty(t *testing.T) {
 	assertPenaltiesGreaterThan(srv2, srv1, now)
 	// Get both idle connections from srv1
 	idle = srv1.getIdle()
-	_, _ = srv1.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil)
+	_, _ = srv1.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil, nil)
 	idle = srv1.getIdle()
-	_, _ = srv1.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil)
+	_, _ = srv1.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil, nil)
 	// Get one idle connection from srv2
 	idle = srv2.getIdle()
-	_, _ = srv2.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil)
+	_, _ = srv2.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil, nil)
 	// Since more connections are in use on srv1, it should have higher penalty even though
 	// srv2 was last used
 	assertPenaltiesGreaterThan(srv1, srv2, now)
 	// Return the connections
 	idle = srv2.getIdle()
-	_, _ = srv2.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil)
-	srv2.returnBusy(context.Background(), c21)
-	srv2.returnBusy(context.Background(), c22)
-	srv1.returnBusy(context.Background(), c11)
-	srv1.returnBusy(context.Background(), c12)
+	_, _ = srv2.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil, nil)
+	srv2.returnBusy(c21, nil)
+	srv2.returnBusy(c22, nil)
+	srv1.returnBusy(c11, nil)
+	srv1.returnBusy(c12, nil)
 	// Everything returned, srv2 should have higher penalty since it was last used
 	assertPenaltiesGreaterThan(srv2, srv1, now)
 
@@ -221,9 +221,9 @@ func TestServerPenalty(t *testing.T) {
 	testutil.AssertFalse(t, srv2.hasFailedConnect(now))
 	// Use srv2 to the max
 	idle = srv2.getIdle()
-	_, _ = srv2.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil)
+	_, _ = srv2.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil, nil)
 	idle = srv2.getIdle()
-	_, _ = srv2.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil)
+	_, _ = srv2.healthCheck(ctx, idle, DefaultConnectionLivenessCheckTimeout, nil, nil, nil)
 	// Even at this point we should prefer srv2
 	assertPenaltiesGreaterThan(srv1, srv2, now)
 
@@ -272,7 +272,7 @@ func TestIdlenessThreshold(outer *testing.T) {
 
 		idleConnection := srv.getIdle()
 		testutil.AssertNotNil(t, idleConnection)
-		healthy, err := srv.healthCheck(context.Background(), idleConnection, 1*time.Hour, nil, nil)
+		healthy, err := srv.healthCheck(context.Background(), idleConnection, 1*time.Hour, nil, nil, nil)
 
 		testutil.AssertNil(t, err)
 		testutil.AssertTrue(t, healthy)
@@ -296,7 +296,7 @@ func TestIdlenessThreshold(outer *testing.T) {
 
 		idleConnection := srv.getIdle()
 		testutil.AssertNotNil(t, idleConnection)
-		healthy, err := srv.healthCheck(context.Background(), idleConnection, 1*time.Hour, nil, nil)
+		healthy, err := srv.healthCheck(context.Background(), idleConnection, 1*time.Hour, nil, nil, nil)
 
 		testutil.AssertNil(t, err)
 		testutil.AssertFalse(t, healthy)
@@ -325,5 +325,5 @@ func populateServer(s *server, birth time.Time, idle, busy int) ([]*testutil.Con
 
 func registerIdle(srv *server, connection db.Connection) {
 	srv.registerBusy(connection)
-	srv.returnBusy(context.Background(), connection)
+	srv.returnBusy(connection, nil)
 }
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+	ievents "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/events"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+// WarmUp opens idle connections to the given servers, up to config.Config MinIdleConnectionsPerServer (and at
+// least one) per server, without exceeding the maximum pool size of each server.
+// Servers are connected to concurrently, the returned error combines the errors of all servers.
+func (p *Pool) WarmUp(ctx context.Context, serverNames []string, auth *idb.ReAuthToken) error {
+	minIdle := p.config.MinIdleConnectionsPerServer
+	if minIdle < 1 {
+		minIdle = 1
+	}
+	errs := make([]error, len(serverNames))
+	wg := sync.WaitGroup{}
+	wg.Add(len(serverNames))
+	for i, serverName := range serverNames {
+		go func(i int, serverName string) {
+			defer wg.Done()
+			errs[i] = p.fill(ctx, serverName, minIdle, auth)
+		}(i, serverName)
+	}
+	wg.Wait()
+	return errorutil.CombineAllErrors(errs...)
+}
+
+// KeepMinIdle makes the background maintenance of the pool keep config.Config MinIdleConnectionsPerServer idle
+// connections to every server returned by getServerNames, until the pool is closed.
+// Servers that recently failed to accept a connection are left alone until they are considered healthy again.
+func (p *Pool) KeepMinIdle(getServerNames func() []string, auth *idb.ReAuthToken) {
+	p.maintenanceMut.Lock()
+	defer p.maintenanceMut.Unlock()
+	p.minIdleServers = getServerNames
+	p.minIdleAuth = auth
+}
+
+func (p *Pool) maintainMinIdle(ctx context.Context, serverNames []string, auth *idb.ReAuthToken) {
+	now := itime.Now()
+	for _, serverName := range serverNames {
+		if p.isClosed() {
+			return
+		}
+		pending := ievents.NewPending(p.config.ConnectionListener)
+		p.serversMut.Lock()
+		srv := p.servers[serverName]
+		// Leave servers alone while their circuit breaker is not closed
+		skip := !p.circuitClosed(serverName)
+		if srv != nil && !skip {
+			// Replace expired connections rather than handing them over to the next borrower
+			srv.removeIdleOlderThan(now, p.config.MaxConnectionLifetime, p.config.MaxConnectionLifetimeJitter, pending)
+			skip = srv.closing || srv.hasFailedConnect(now)
+		}
+		p.serversMut.Unlock()
+		pending.Flush()
+		if skip {
+			continue
+		}
+		if err := p.fill(ctx, serverName, p.config.MinIdleConnectionsPerServer, auth); err != nil {
+			p.log.Warnf(log.Pool, p.logId, "Failed to maintain minimum idle connections to %s: %s", serverName, err)
+		}
+	}
+}
+
+// fill opens connections to the server until it holds minIdle idle connections or the server is full.
+func (p *Pool) fill(ctx context.Context, serverName string, minIdle int, auth *idb.ReAuthToken) error {
+	for {
+		if p.isClosed() {
+			return &errorutil.PoolClosed{}
+		}
+		p.serversMut.Lock()
+		srv := p.servers[serverName]
+		if srv == nil {
+			srv = NewServer()
+			srv.stats = p.statsOf(serverName)
+			p.servers[serverName] = srv
+		}
+		if srv.numIdle()+srv.reservations >= minIdle || srv.size() >= p.maxSize(serverName) {
+			p.serversMut.Unlock()
+			return nil
+		}
+		srv.reservations++
+		p.serversMut.Unlock()
+
+		p.log.Infof(log.Pool, p.logId, "Connecting to %s to keep %d idle connections", serverName, minIdle)
+		start := itime.Now()
+		c, err := p.connect(ctx, serverName, auth, p, nil)
+		p.serversMut.Lock()
+		srv.reservations--
+		if err != nil {
+			srv.stats.failedToCreate++
+			srv.stats.lastFailedToCreate = itime.Now()
+			if _, ok := err.(*db.FeatureNotSupportedError); !ok {
+				srv.notifyFailedConnect(itime.Now())
+			}
+			p.serversMut.Unlock()
+			p.settled.notify()
+			return err
+		}
+		srv.registerBusy(c)
+		srv.notifySuccessfulConnect()
+		srv.stats.created++
+		srv.stats.observeConnectionTime(itime.Since(start))
+		p.serversMut.Unlock()
+		p.makeAvailable(ctx, serverName, c)
+	}
+}
@@ -0,0 +1,132 @@
+//go:build internal_time_mock
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/bolt"
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+)
+
+func TestPoolMinIdle(outer *testing.T) {
+	countingConnect := func(connects *int32) Connect {
+		return func(_ context.Context, s string, _ *idb.ReAuthToken, _ bolt.ConnectionErrorListener, _ log.BoltLogger) (idb.Connection, error) {
+			atomic.AddInt32(connects, 1)
+			return &ConnFake{Name: s, Alive: true, Birth: itime.Now()}, nil
+		}
+	}
+
+	outer.Run("warms up servers up to the minimum of idle connections", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		connects := int32(0)
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3, MinIdleConnectionsPerServer: 2}
+		p := New(&conf, countingConnect(&connects), logger, "pool id")
+		defer p.Close(ctx)
+
+		AssertNoError(t, p.WarmUp(ctx, []string{"srv1"}, reAuthToken))
+		AssertNoError(t, p.WarmUp(ctx, []string{"srv1"}, reAuthToken))
+
+		AssertIntEqual(t, p.getServers()["srv1"].numIdle(), 2)
+		AssertIntEqual(t, int(connects), 2)
+	})
+
+	outer.Run("warms up at least one connection", func(t *testing.T) {
+		connects := int32(0)
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3}
+		p := New(&conf, countingConnect(&connects), logger, "pool id")
+		defer p.Close(ctx)
+
+		AssertNoError(t, p.WarmUp(ctx, []string{"srv1", "srv2"}, reAuthToken))
+
+		AssertIntEqual(t, p.getServers()["srv1"].numIdle(), 1)
+		AssertIntEqual(t, p.getServers()["srv2"].numIdle(), 1)
+	})
+
+	outer.Run("does not exceed the maximum pool size", func(t *testing.T) {
+		connects := int32(0)
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 2, MinIdleConnectionsPerServer: 2}
+		p := New(&conf, countingConnect(&connects), logger, "pool id")
+		defer p.Close(ctx)
+		conn, err := p.Borrow(ctx, getServers([]string{"srv1"}), false, nil, DefaultConnectionLivenessCheckTimeout, reAuthToken)
+		assertConnection(t, conn, err)
+
+		AssertNoError(t, p.WarmUp(ctx, []string{"srv1"}, reAuthToken))
+
+		AssertIntEqual(t, p.getServers()["srv1"].numIdle(), 1)
+		AssertIntEqual(t, int(connects), 2)
+	})
+
+	outer.Run("reports connection failures", func(t *testing.T) {
+		failure := errors.New("refused")
+		failingConnect := func(context.Context, string, *idb.ReAuthToken, bolt.ConnectionErrorListener, log.BoltLogger) (idb.Connection, error) {
+			return nil, failure
+		}
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 2}
+		p := New(&conf, failingConnect, logger, "pool id")
+		defer p.Close(ctx)
+
+		err := p.WarmUp(ctx, []string{"srv1"}, reAuthToken)
+
+		AssertError(t, err)
+		AssertTrue(t, p.getServers()["srv1"].hasFailedConnect(itime.Now()))
+	})
+
+	outer.Run("replaces expired idle connections", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		connects := int32(0)
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Minute, MaxConnectionPoolSize: 3, MinIdleConnectionsPerServer: 2}
+		p := New(&conf, countingConnect(&connects), logger, "pool id")
+		defer p.Close(ctx)
+		AssertNoError(t, p.WarmUp(ctx, []string{"srv1"}, reAuthToken))
+
+		itime.ForceTickTime(2 * time.Minute)
+		p.maintainMinIdle(ctx, []string{"srv1"}, reAuthToken)
+
+		AssertIntEqual(t, p.getServers()["srv1"].numIdle(), 2)
+		AssertIntEqual(t, int(connects), 4)
+		AssertIntEqual(t, int(p.Metrics().Servers["srv1"].Closed), 2)
+	})
+
+	outer.Run("leaves recently failed servers alone", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		connects := int32(0)
+		conf := config.Config{MaxConnectionLifetime: 1 * time.Hour, MaxConnectionPoolSize: 3, MinIdleConnectionsPerServer: 2}
+		p := New(&conf, countingConnect(&connects), logger, "pool id")
+		defer p.Close(ctx)
+		setIdleConnections(p, map[string][]idb.Connection{"srv1": {}})
+		p.getServers()["srv1"].notifyFailedConnect(itime.Now())
+
+		p.maintainMinIdle(ctx, []string{"srv1"}, reAuthToken)
+
+		AssertIntEqual(t, int(connects), 0)
+	})
+}
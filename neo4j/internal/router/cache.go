@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+	"time"
+
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
+)
+
+// backgroundRefreshTimeout bounds the refresh of routing tables imported from a routing.Cache
+const backgroundRefreshTimeout = 30 * time.Second
+
+// UseCache imports the routing tables stored under the key in the cache, then refreshes them in the background with
+// the given credentials. Imported tables are valid until they expire, their routers are used to fetch tables
+// whenever the initial router fails.
+// From then on, the routing tables are stored in the cache whenever one of them is fetched.
+func (r *Router) UseCache(cache routing.Cache, key string, auth *idb.ReAuthToken) {
+	tables, err := cache.Load(key)
+	if err != nil {
+		r.log.Warnf(log.Router, r.logId, "Could not load routing tables from cache: %s", err)
+	}
+	r.cacheMut.Lock()
+	r.cache = cache
+	r.cacheKey = key
+	r.cacheMut.Unlock()
+
+	for _, table := range tables {
+		if !r.importTable(table) {
+			continue
+		}
+		r.log.Infof(log.Router, r.logId, "Imported routing table for '%s' from cache, expiring at %s", table.Database, table.ExpiresAt)
+		go r.refreshInBackground(table.Database, auth)
+	}
+}
+
+// importTable stores the table unless the router already holds one for the database
+func (r *Router) importTable(table routing.Table) bool {
+	r.dbRoutersMut.Lock()
+	if r.dbRouters[table.Database] != nil {
+		r.dbRoutersMut.Unlock()
+		return false
+	}
+	current := &databaseRouter{
+		table: &idb.RoutingTable{
+			TimeToLive:   int(table.TimeToLive / time.Second),
+			DatabaseName: table.Database,
+			Routers:      copyOf(table.Routers),
+			Readers:      copyOf(table.Readers),
+			Writers:      copyOf(table.Writers),
+		},
+		dueUnix:      table.ExpiresAt.Unix(),
+		lastUsedUnix: itime.Now().Unix(),
+	}
+	r.dbRouters[table.Database] = current
+	change := changeOf(table.Database, nil, tableOf(table.Database, current))
+	r.dbRoutersMut.Unlock()
+	r.notifyChanges(change)
+	return true
+}
+
+func (r *Router) refreshInBackground(database string, auth *idb.ReAuthToken) {
+	ctx, cancel := context.WithTimeout(context.Background(), backgroundRefreshTimeout)
+	defer cancel()
+	r.dbRoutersMut.Lock()
+	dbRouter := cloneRouter(r.dbRouters[database])
+	r.dbRoutersMut.Unlock()
+	if _, _, err := r.updateTable(ctx, noBookmarks, database, auth, nil, dbRouter); err != nil {
+		r.log.Warnf(log.Router, r.logId, "Could not refresh routing table for '%s' imported from cache: %s", database, err)
+	}
+}
+
+func noBookmarks(context.Context) ([]string, error) {
+	return nil, nil
+}
+
+// exportTables stores all the routing tables in the cache, if any.
+// Tables are stored in the background, so that fetching a table does not wait for the cache. Exports requested while
+// the tables are being stored are coalesced into a single one.
+func (r *Router) exportTables() {
+	r.cacheMut.Lock()
+	defer r.cacheMut.Unlock()
+	if r.cache == nil {
+		return
+	}
+	r.exportPending = true
+	if !r.exporting {
+		r.exporting = true
+		go r.runExports()
+	}
+}
+
+func (r *Router) runExports() {
+	for {
+		r.cacheMut.Lock()
+		if !r.exportPending {
+			r.exporting = false
+			r.cacheMut.Unlock()
+			return
+		}
+		r.exportPending = false
+		cache, key := r.cache, r.cacheKey
+		r.cacheMut.Unlock()
+
+		r.dbRoutersMut.Lock()
+		tables := make([]routing.Table, 0, len(r.dbRouters))
+		for database, dbRouter := range r.dbRouters {
+			tables = append(tables, tableOf(database, dbRouter))
+		}
+		r.dbRoutersMut.Unlock()
+		if err := cache.Store(key, tables); err != nil {
+			r.log.Warnf(log.Router, r.logId, "Could not store routing tables in cache: %s", err)
+		}
+	}
+}
+
+// knownRouters returns the routers of all the routing tables the router holds, expired or not, except the given ones.
+func (r *Router) knownRouters(except ...string) []string {
+	r.dbRoutersMut.Lock()
+	defer r.dbRoutersMut.Unlock()
+	var routers []string
+	for _, dbRouter := range r.dbRouters {
+		for _, router := range dbRouter.table.Routers {
+			if !contains(except, router) && !contains(routers, router) {
+				routers = append(routers, router)
+			}
+		}
+	}
+	return routers
+}
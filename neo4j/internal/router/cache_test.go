@@ -0,0 +1,215 @@
+//go:build internal_time_mock
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	pool2 "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/pool"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
+)
+
+type cacheFake struct {
+	mut    sync.Mutex
+	tables map[string][]routing.Table
+}
+
+func (c *cacheFake) Load(key string) ([]routing.Table, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.tables[key], nil
+}
+
+func (c *cacheFake) Store(key string, tables []routing.Table) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.tables[key] = tables
+	return nil
+}
+
+func (c *cacheFake) stored(key string) []routing.Table {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.tables[key]
+}
+
+// awaitStored waits for the tables stored in the background under the key to match
+func (c *cacheFake) awaitStored(t *testing.T, key string, matches func([]routing.Table) bool) []routing.Table {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		tables := c.stored(key)
+		if matches(tables) {
+			return tables
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected tables to be stored, got %v", tables)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// blockingCacheFake blocks stores until released
+type blockingCacheFake struct {
+	cacheFake
+	release chan struct{}
+}
+
+func (c *blockingCacheFake) Store(key string, tables []routing.Table) error {
+	<-c.release
+	return c.cacheFake.Store(key, tables)
+}
+
+func TestRoutingTableCache(outer *testing.T) {
+	// serve makes the pool fake answer with the table of the router, failing for routers without table
+	serve := func(tables map[string]*db.RoutingTable) *poolFake {
+		mut := sync.Mutex{}
+		return &poolFake{
+			borrow: func(names []string, _ context.CancelFunc, _ log.BoltLogger) (db.Connection, error) {
+				mut.Lock()
+				defer mut.Unlock()
+				if table := tables[names[0]]; table != nil {
+					return &testutil.ConnFake{Table: table}, nil
+				}
+				return nil, errors.New("connection refused")
+			},
+		}
+	}
+	cachedTable := func(expiresAt time.Time) routing.Table {
+		return routing.Table{
+			Database:   "movies",
+			Routers:    []string{"cached-router"},
+			Readers:    []string{"cached-reader"},
+			Writers:    []string{"cached-writer"},
+			TimeToLive: 5 * time.Minute,
+			ExpiresAt:  expiresAt,
+		}
+	}
+	newRouter := func(pool *poolFake) *Router {
+		return New("rootRouter", nil, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
+	}
+
+	outer.Run("uses imported tables until they expire", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		cache := &cacheFake{tables: map[string][]routing.Table{"rootRouter:7687": {cachedTable(itime.Now().Add(time.Minute))}}}
+		router := newRouter(serve(nil))
+
+		router.UseCache(cache, "rootRouter:7687", nil)
+
+		readers, err := router.GetOrUpdateReaders(context.Background(), nilBookmarks, "movies", nil, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertDeepEquals(t, readers, []string{"cached-reader"})
+		itime.ForceTickTime(2 * time.Minute)
+		_, err = router.GetOrUpdateReaders(context.Background(), nilBookmarks, "movies", nil, nil)
+		testutil.AssertError(t, err)
+	})
+
+	outer.Run("refreshes imported tables in the background", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		cache := &cacheFake{tables: map[string][]routing.Table{"rootRouter:7687": {cachedTable(itime.Now().Add(time.Minute))}}}
+		router := newRouter(serve(map[string]*db.RoutingTable{
+			"cached-router": {TimeToLive: 300, Routers: []string{"router"}, Readers: []string{"reader"}, Writers: []string{"writer"}},
+		}))
+		refreshed := make(chan routing.Change, 1)
+		router.SubscribeChanges(func(change routing.Change) {
+			if change.Previous != nil {
+				refreshed <- change
+			}
+		})
+
+		router.UseCache(cache, "rootRouter:7687", nil)
+
+		select {
+		case change := <-refreshed:
+			testutil.AssertDeepEquals(t, change.Current.Readers, []string{"reader"})
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected the imported table to be refreshed")
+		}
+		stored := cache.awaitStored(t, "rootRouter:7687", func(tables []routing.Table) bool {
+			return len(tables) == 1 && len(tables[0].Writers) == 1 && tables[0].Writers[0] == "writer"
+		})
+		testutil.AssertIntEqual(t, len(stored), 1)
+	})
+
+	outer.Run("falls back to imported routers when the initial router fails", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		cache := &cacheFake{tables: map[string][]routing.Table{"rootRouter:7687": {cachedTable(itime.Now().Add(-time.Minute))}}}
+		router := newRouter(serve(map[string]*db.RoutingTable{
+			"cached-router": {TimeToLive: 300, DatabaseName: "books", Readers: []string{"reader"}},
+		}))
+		router.UseCache(cache, "rootRouter:7687", nil)
+
+		readers, err := router.GetOrUpdateReaders(context.Background(), nilBookmarks, "books", nil, nil)
+
+		testutil.AssertNoError(t, err)
+		testutil.AssertDeepEquals(t, readers, []string{"reader"})
+	})
+
+	outer.Run("stores tables without blocking fetches", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		cache := &blockingCacheFake{cacheFake: cacheFake{tables: map[string][]routing.Table{}}, release: make(chan struct{})}
+		defer close(cache.release)
+		router := newRouter(serve(map[string]*db.RoutingTable{
+			"rootRouter": {TimeToLive: 300, Routers: []string{"router"}, Readers: []string{"reader"}},
+		}))
+		router.UseCache(cache, "rootRouter:7687", nil)
+
+		for _, database := range []string{"movies", "books"} {
+			readers, err := router.GetOrUpdateReaders(context.Background(), nilBookmarks, database, nil, nil)
+			testutil.AssertNoError(t, err)
+			testutil.AssertDeepEquals(t, readers, []string{"reader"})
+		}
+	})
+
+	outer.Run("stores fetched tables", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		cache := &cacheFake{tables: map[string][]routing.Table{}}
+		router := newRouter(serve(map[string]*db.RoutingTable{
+			"rootRouter": {TimeToLive: 300, Routers: []string{"router"}, Readers: []string{"reader"}},
+		}))
+		router.UseCache(cache, "rootRouter:7687", nil)
+
+		_, err := router.GetOrUpdateReaders(context.Background(), nilBookmarks, "movies", nil, nil)
+
+		testutil.AssertNoError(t, err)
+		stored := cache.awaitStored(t, "rootRouter:7687", func(tables []routing.Table) bool {
+			return len(tables) > 0
+		})
+		testutil.AssertDeepEquals(t, stored, []routing.Table{{
+			Database:   "movies",
+			Routers:    []string{"router"},
+			Readers:    []string{"reader"},
+			TimeToLive: 5 * time.Minute,
+			ExpiresAt:  time.Unix(itime.Now().Add(5*time.Minute).Unix(), 0),
+		}})
+	})
+}
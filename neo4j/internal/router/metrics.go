@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"time"
+
+	imetrics "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/metrics"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+)
+
+// Routing table statistics of a database.
+// Not thread safe, guarded by the router's stats lock.
+type routingStats struct {
+	refreshes       int64
+	failedRefreshes int64
+	lastRefresh     time.Time
+	refreshTime     imetrics.Histogram
+}
+
+func (r *Router) onRefresh(database string, start time.Time, err error) {
+	r.statsMut.Lock()
+	defer r.statsMut.Unlock()
+	stats := r.stats[database]
+	if stats == nil {
+		stats = &routingStats{}
+		r.stats[database] = stats
+	}
+	if err != nil {
+		stats.failedRefreshes++
+		return
+	}
+	now := itime.Now()
+	stats.refreshes++
+	stats.lastRefresh = now
+	stats.refreshTime.Observe(now.Sub(start))
+}
+
+// Metrics returns a snapshot of the routing table metrics, keyed by database.
+func (r *Router) Metrics() map[string]metrics.RoutingTableMetrics {
+	r.statsMut.Lock()
+	defer r.statsMut.Unlock()
+	result := make(map[string]metrics.RoutingTableMetrics, len(r.stats))
+	for database, stats := range r.stats {
+		result[database] = metrics.RoutingTableMetrics{
+			Refreshes:       stats.refreshes,
+			FailedRefreshes: stats.failedRefreshes,
+			LastRefresh:     stats.lastRefresh,
+			RefreshTime:     stats.refreshTime.Snapshot(),
+		}
+	}
+	return result
+}
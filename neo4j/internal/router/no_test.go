@@ -21,6 +21,7 @@ import (
 	"context"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"sync"
 	"time"
 )
 
@@ -28,6 +29,7 @@ type poolFake struct {
 	borrow   func(names []string, cancel context.CancelFunc, logger log.BoltLogger) (db.Connection, error)
 	returned []db.Connection
 	cancel   context.CancelFunc
+	mut      sync.Mutex
 }
 
 func (p *poolFake) Borrow(_ context.Context, getServers func() []string, _ bool, logger log.BoltLogger, _ time.Duration, _ *db.ReAuthToken) (db.Connection, error) {
@@ -36,5 +38,7 @@ func (p *poolFake) Borrow(_ context.Context, getServers func() []string, _ bool,
 }
 
 func (p *poolFake) Return(_ context.Context, c db.Connection) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
 	p.returned = append(p.returned, c)
 }
@@ -0,0 +1,188 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
+)
+
+// refreshInterval is how often the refresher looks for routing tables to renew or evict
+const refreshInterval = 1 * time.Second
+
+// refreshPollInterval is how often the refresher checks whether refreshInterval has elapsed.
+// Elapsed time is measured with the itime clock, so that tests can drive the refresher by ticking a frozen clock.
+const refreshPollInterval = 100 * time.Millisecond
+
+// Bounds of the delay before the refresher retries a failed refresh, doubling with every consecutive failure
+const minRefreshBackOff = 1 * time.Second
+const maxRefreshBackOff = 1 * time.Minute
+
+type refresher struct {
+	ahead       time.Duration
+	idleTimeout time.Duration
+	auth        *idb.ReAuthToken
+	// ctx is cancelled when the refresher stops, which interrupts the refresh in flight
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// StartRefresher renews the routing tables of the databases used within idleTimeout in the background, with the
+// given credentials, up to ahead before they expire, so that requests do not pay for fetching them. Tables are renewed
+// at the earliest halfway through their time to live. Failed refreshes are retried with exponential back-off.
+// Tables of databases not used within idleTimeout are evicted instead, which makes CleanUp a no-op.
+// Must be called before the router is used.
+func (r *Router) StartRefresher(ahead, idleTimeout time.Duration, auth *idb.ReAuthToken) {
+	ctx, cancel := context.WithCancel(context.Background())
+	refresher := &refresher{
+		ahead:       ahead,
+		idleTimeout: idleTimeout,
+		auth:        auth,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	r.dbRoutersMut.Lock()
+	r.refresher = refresher
+	r.dbRoutersMut.Unlock()
+	refresher.done.Add(1)
+	go r.runRefresher(refresher, itime.Now())
+}
+
+// StopRefresher stops the refresher started by StartRefresher, if any.
+// The refresh in flight is cancelled, and StopRefresher returns once the refresher has stopped, so that the pool can
+// be closed without the refresher borrowing connections from it.
+func (r *Router) StopRefresher() {
+	r.dbRoutersMut.Lock()
+	refresher := r.refresher
+	r.dbRoutersMut.Unlock()
+	if refresher != nil {
+		refresher.cancel()
+		refresher.done.Wait()
+	}
+}
+
+// runRefresher looks for routing tables to renew or evict every refreshInterval from start, until the refresher stops
+func (r *Router) runRefresher(refresher *refresher, start time.Time) {
+	defer refresher.done.Done()
+	lastRun := start
+	for {
+		select {
+		case <-refresher.ctx.Done():
+			return
+		case <-time.After(refreshPollInterval):
+		}
+		if itime.Since(lastRun) < refreshInterval {
+			continue
+		}
+		r.refresh(refresher.ctx, refresher)
+		lastRun = itime.Now()
+	}
+}
+
+// refresh evicts the routing tables of the databases not used recently, then renews the tables due for a refresh
+func (r *Router) refresh(ctx context.Context, refresher *refresher) {
+	now := itime.Now()
+	var due []string
+	var evictions []routing.Change
+	r.dbRoutersMut.Lock()
+	for database, dbRouter := range r.dbRouters {
+		if now.Sub(time.Unix(dbRouter.lastUsedUnix, 0)) > refresher.idleTimeout {
+			r.log.Debugf(log.Router, r.logId, "Evicting routing table for '%s', not used since %s", database, time.Unix(dbRouter.lastUsedUnix, 0))
+			delete(r.dbRouters, database)
+			evictions = append(evictions, changeOf(database, dbRouter, routing.Table{Database: database}))
+			continue
+		}
+		if _, updating := r.updating[database]; !updating && refreshDue(dbRouter, now, refresher.ahead) {
+			due = append(due, database)
+		}
+	}
+	r.dbRoutersMut.Unlock()
+	if len(evictions) > 0 {
+		r.notifyChanges(evictions...)
+		r.exportTables()
+	}
+
+	for _, database := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		r.refreshTable(ctx, database, refresher.auth)
+	}
+}
+
+func refreshDue(dbRouter *databaseRouter, now time.Time, ahead time.Duration) bool {
+	if now.Unix() < dbRouter.retryUnix {
+		return false
+	}
+	if halfTimeToLive := time.Duration(dbRouter.table.TimeToLive) * time.Second / 2; halfTimeToLive < ahead {
+		ahead = halfTimeToLive
+	}
+	return now.Unix()+int64(ahead/time.Second) >= dbRouter.dueUnix
+}
+
+// refreshTable renews the routing table of the database, unless it is being fetched already.
+// Requests needing the table while it is renewed wait for it, as if it was fetched on their behalf.
+func (r *Router) refreshTable(ctx context.Context, database string, auth *idb.ReAuthToken) {
+	r.dbRoutersMut.Lock()
+	dbRouter := r.dbRouters[database]
+	if _, updating := r.updating[database]; updating || dbRouter == nil {
+		r.dbRoutersMut.Unlock()
+		return
+	}
+	dbRouter = cloneRouter(dbRouter)
+	r.updating[database] = make([]chan struct{}, 0)
+	r.dbRoutersMut.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, backgroundRefreshTimeout)
+	_, _, err := r.updateTable(ctx, noBookmarks, database, auth, nil, dbRouter)
+	cancel()
+
+	r.dbRoutersMut.Lock()
+	for _, waiter := range r.updating[database] {
+		close(waiter)
+	}
+	delete(r.updating, database)
+	if err != nil {
+		if current := r.dbRouters[database]; current != nil {
+			current.refreshFailures++
+			current.retryUnix = itime.Now().Add(refreshBackOff(current.refreshFailures)).Unix()
+		}
+	}
+	r.dbRoutersMut.Unlock()
+	if err != nil {
+		r.log.Warnf(log.Router, r.logId, "Could not refresh routing table for '%s' in the background: %s", database, err)
+	}
+}
+
+func refreshBackOff(failures int) time.Duration {
+	backOff := minRefreshBackOff
+	for i := 1; i < failures && backOff < maxRefreshBackOff; i++ {
+		backOff *= 2
+	}
+	if backOff > maxRefreshBackOff {
+		return maxRefreshBackOff
+	}
+	return backOff
+}
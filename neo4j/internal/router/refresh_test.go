@@ -0,0 +1,248 @@
+//go:build internal_time_mock
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	pool2 "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/pool"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
+)
+
+func TestRefresher(outer *testing.T) {
+	ctx := context.Background()
+
+	newRouter := func(fetches *int, fail *bool) (*Router, *refresher) {
+		pool := &poolFake{
+			borrow: func(names []string, cancel context.CancelFunc, _ log.BoltLogger) (db.Connection, error) {
+				*fetches++
+				if *fail {
+					return nil, errors.New("router down")
+				}
+				return &testutil.ConnFake{Table: &db.RoutingTable{TimeToLive: 100, Readers: []string{"reader"}, Routers: []string{"router"}}}, nil
+			},
+		}
+		router := New("router", nil, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
+		refresher := &refresher{ahead: 10 * time.Second, idleTimeout: 5 * time.Minute}
+		router.refresher = refresher
+		return router, refresher
+	}
+
+	outer.Run("renews tables ahead of expiry", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		fetches, fail := 0, false
+		router, refresher := newRouter(&fetches, &fail)
+		_, err := router.GetOrUpdateReaders(ctx, nilBookmarks, "db", nil, nil)
+		testutil.AssertNoError(t, err)
+
+		router.refresh(ctx, refresher)
+		testutil.AssertIntEqual(t, fetches, 1)
+
+		itime.ForceTickTime(91 * time.Second)
+		router.refresh(ctx, refresher)
+		testutil.AssertIntEqual(t, fetches, 2)
+		table, _ := router.Table("db")
+		testutil.AssertTrue(t, table.ExpiresAt.Equal(time.Unix(itime.Now().Add(100*time.Second).Unix(), 0)))
+
+		// No fetch on the request path, the table is still valid
+		itime.ForceTickTime(15 * time.Second)
+		_, err = router.GetOrUpdateReaders(ctx, nilBookmarks, "db", nil, nil)
+		testutil.AssertNoError(t, err)
+		testutil.AssertIntEqual(t, fetches, 2)
+	})
+
+	outer.Run("renews tables halfway through short time to live", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		fetches, fail := 0, false
+		router, refresher := newRouter(&fetches, &fail)
+		refresher.ahead = 5 * time.Minute
+		_, err := router.GetOrUpdateReaders(ctx, nilBookmarks, "db", nil, nil)
+		testutil.AssertNoError(t, err)
+
+		itime.ForceTickTime(49 * time.Second)
+		router.refresh(ctx, refresher)
+		testutil.AssertIntEqual(t, fetches, 1)
+
+		itime.ForceTickTime(1 * time.Second)
+		router.refresh(ctx, refresher)
+		testutil.AssertIntEqual(t, fetches, 2)
+	})
+
+	outer.Run("backs off on failure", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		fetches, fail := 0, false
+		router, refresher := newRouter(&fetches, &fail)
+		_, err := router.GetOrUpdateReaders(ctx, nilBookmarks, "db", nil, nil)
+		testutil.AssertNoError(t, err)
+		fail = true
+		itime.ForceTickTime(90 * time.Second)
+
+		router.refresh(ctx, refresher)
+		attempts := fetches
+		testutil.AssertTrue(t, attempts > 1)
+		router.refresh(ctx, refresher)
+		testutil.AssertIntEqual(t, fetches, attempts)
+
+		itime.ForceTickTime(1 * time.Second)
+		router.refresh(ctx, refresher)
+		testutil.AssertTrue(t, fetches > attempts)
+		attempts = fetches
+
+		// Waits twice as long after the second failure
+		itime.ForceTickTime(1 * time.Second)
+		router.refresh(ctx, refresher)
+		testutil.AssertIntEqual(t, fetches, attempts)
+		itime.ForceTickTime(1 * time.Second)
+		router.refresh(ctx, refresher)
+		testutil.AssertTrue(t, fetches > attempts)
+
+		// Failures reset once the table is renewed
+		fail = false
+		itime.ForceTickTime(4 * time.Second)
+		router.refresh(ctx, refresher)
+		table, _ := router.Table("db")
+		testutil.AssertTrue(t, table.ExpiresAt.After(itime.Now().Add(90*time.Second)))
+		testutil.AssertIntEqual(t, router.dbRouters["db"].refreshFailures, 0)
+	})
+
+	outer.Run("evicts tables not used recently", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		fetches, fail := 0, false
+		router, refresher := newRouter(&fetches, &fail)
+		_, err := router.GetOrUpdateReaders(ctx, nilBookmarks, "db1", nil, nil)
+		testutil.AssertNoError(t, err)
+		_, err = router.GetOrUpdateReaders(ctx, nilBookmarks, "db2", nil, nil)
+		testutil.AssertNoError(t, err)
+		var changes []routing.Change
+		router.SubscribeChanges(func(change routing.Change) {
+			changes = append(changes, change)
+		})
+
+		itime.ForceTickTime(3 * time.Minute)
+		_, err = router.GetOrUpdateReaders(ctx, nilBookmarks, "db2", nil, nil)
+		testutil.AssertNoError(t, err)
+		itime.ForceTickTime(3 * time.Minute)
+		router.refresh(ctx, refresher)
+
+		_, found := router.Table("db1")
+		testutil.AssertFalse(t, found)
+		_, found = router.Table("db2")
+		testutil.AssertTrue(t, found)
+		testutil.AssertLen(t, changes, 1)
+		testutil.AssertStringEqual(t, changes[0].Database, "db1")
+		testutil.AssertDeepEquals(t, changes[0].Current, routing.Table{Database: "db1"})
+		testutil.AssertDeepEquals(t, changes[0].RemovedReaders, []string{"reader"})
+		testutil.AssertDeepEquals(t, changes[0].RemovedRouters, []string{"router"})
+	})
+
+	outer.Run("stops once the refresh in flight is cancelled", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		fetches, fail := 0, false
+		router, _ := newRouter(&fetches, &fail)
+		_, err := router.GetOrUpdateReaders(ctx, nilBookmarks, "db", nil, nil)
+		testutil.AssertNoError(t, err)
+		refreshing, release := make(chan struct{}), make(chan struct{})
+		var once sync.Once
+		router.pool.(*poolFake).borrow = func([]string, context.CancelFunc, log.BoltLogger) (db.Connection, error) {
+			once.Do(func() { close(refreshing) })
+			<-release
+			return nil, errors.New("cancelled")
+		}
+		router.StartRefresher(10*time.Second, 5*time.Minute, nil)
+		itime.ForceTickTime(95 * time.Second)
+		<-refreshing
+
+		stopped := make(chan struct{})
+		go func() {
+			router.StopRefresher()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+			t.Fatal("should wait for the refresh in flight")
+		case <-time.After(50 * time.Millisecond):
+		}
+		testutil.AssertNotNil(t, router.refresher.ctx.Err())
+		close(release)
+		<-stopped
+	})
+
+	outer.Run("runs in the background on the itime clock", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		fetches, fail := 0, false
+		router, _ := newRouter(&fetches, &fail)
+		_, err := router.GetOrUpdateReaders(ctx, nilBookmarks, "db", nil, nil)
+		testutil.AssertNoError(t, err)
+		refreshed := make(chan struct{})
+		var once sync.Once
+		router.pool.(*poolFake).borrow = func([]string, context.CancelFunc, log.BoltLogger) (db.Connection, error) {
+			once.Do(func() { close(refreshed) })
+			return &testutil.ConnFake{Table: &db.RoutingTable{TimeToLive: 100, Readers: []string{"reader"}, Routers: []string{"router"}}}, nil
+		}
+		router.StartRefresher(10*time.Second, 5*time.Minute, nil)
+		defer router.StopRefresher()
+
+		time.Sleep(3 * refreshPollInterval)
+		select {
+		case <-refreshed:
+			t.Fatal("should not refresh before the clock ticks")
+		default:
+		}
+
+		itime.ForceTickTime(95 * time.Second)
+		<-refreshed
+	})
+
+	outer.Run("takes over clean up", func(t *testing.T) {
+		itime.ForceFreezeTime()
+		defer itime.ForceUnfreezeTime()
+		fetches, fail := 0, false
+		router, _ := newRouter(&fetches, &fail)
+		_, err := router.GetOrUpdateReaders(ctx, nilBookmarks, "db", nil, nil)
+		testutil.AssertNoError(t, err)
+
+		itime.ForceTickTime(2 * time.Minute)
+		router.CleanUp()
+
+		_, found := router.Table("db")
+		testutil.AssertTrue(t, found)
+	})
+}
+
+func TestRefreshBackOff(t *testing.T) {
+	testutil.AssertDeepEquals(t, refreshBackOff(1), 1*time.Second)
+	testutil.AssertDeepEquals(t, refreshBackOff(2), 2*time.Second)
+	testutil.AssertDeepEquals(t, refreshBackOff(4), 8*time.Second)
+	testutil.AssertDeepEquals(t, refreshBackOff(20), maxRefreshBackOff)
+}
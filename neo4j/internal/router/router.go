@@ -21,14 +21,20 @@ import (
 	"context"
 	"errors"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/events"
 	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+	ievents "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/events"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/racing"
+	"math/rand"
 	"sync"
 	"time"
 
 	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	itracing "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/tracing"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
 )
 
 const missingWriterRetries = 100
@@ -37,6 +43,11 @@ const missingReaderRetries = 100
 type databaseRouter struct {
 	dueUnix int64
 	table   *idb.RoutingTable
+	// lastUsedUnix is when the table was last needed by a request, or fetched for the first time
+	lastUsedUnix int64
+	// Consecutive failures of the refresher to renew the table, and when it may try again
+	refreshFailures int
+	retryUnix       int64
 }
 
 // Router is thread safe
@@ -49,9 +60,24 @@ type Router struct {
 	dbRoutersMut    sync.Mutex
 	sleep           func(time.Duration)
 	rootRouter      string
+	rootRouters     []string
+	shuffleRoots    bool
 	getRouters      func() []string
 	log             log.Logger
 	logId           string
+	tracer          tracing.Tracer
+	listener        events.ConnectionListener
+	stats           map[string]*routingStats
+	statsMut        sync.Mutex
+	listeners       map[int]routing.ChangeListener
+	nextListenerId  int
+	listenersMut    sync.Mutex
+	cache           routing.Cache
+	cacheKey        string
+	exporting       bool // whether tables are being stored in the cache, see exportTables
+	exportPending   bool // whether tables must be stored again once stored
+	cacheMut        sync.Mutex
+	refresher       *refresher
 }
 
 type Pool interface {
@@ -63,9 +89,10 @@ type Pool interface {
 	Return(ctx context.Context, c idb.Connection)
 }
 
-func New(rootRouter string, getRouters func() []string, routerContext map[string]string, pool Pool, idlenessTimeout time.Duration, logger log.Logger, logId string) *Router {
+func New(rootRouter string, getRouters func() []string, routerContext map[string]string, pool Pool, idlenessTimeout time.Duration, logger log.Logger, logId string, tracer tracing.Tracer, listener events.ConnectionListener) *Router {
 	r := &Router{
 		rootRouter:      rootRouter,
+		rootRouters:     []string{rootRouter},
 		getRouters:      getRouters,
 		routerContext:   routerContext,
 		pool:            pool,
@@ -76,6 +103,10 @@ func New(rootRouter string, getRouters func() []string, routerContext map[string
 		sleep:           time.Sleep,
 		log:             logger,
 		logId:           logId,
+		tracer:          tracer,
+		listener:        listener,
+		stats:           make(map[string]*routingStats),
+		listeners:       make(map[int]routing.ChangeListener),
 	}
 	r.log.Infof(log.Router, r.logId, "Created {context: %v}", routerContext)
 	return r
@@ -89,11 +120,13 @@ func (r *Router) readTable(
 	impersonatedUser string,
 	auth *idb.ReAuthToken,
 	boltLogger log.BoltLogger,
-) (*idb.RoutingTable, error) {
-	var (
-		table *idb.RoutingTable
-		err   error
-	)
+) (table *idb.RoutingTable, err error) {
+	start := itime.Now()
+	ctx, span := itracing.Start(ctx, r.tracer, tracing.RoutingTableRefreshOperation, r.spanAttributes(database, impersonatedUser))
+	defer func() {
+		r.onRefresh(database, start, err)
+		span.End(nil, err)
+	}()
 
 	// Try last known set of routers if there are any
 	if dbRouter != nil && len(dbRouter.table.Routers) > 0 {
@@ -106,10 +139,27 @@ func (r *Router) readTable(
 		return nil, err
 	}
 
-	// Try initial router if no routers or failed
+	// Try initial routers if no routers or failed
 	if table == nil {
-		r.log.Infof(log.Router, r.logId, "Reading routing table from initial router: %s", r.rootRouter)
-		table, err = readTable(ctx, r.pool, []string{r.rootRouter}, r.routerContext, r.idlenessTimeout, bookmarks, database, impersonatedUser, auth, boltLogger)
+		rootRouters := r.routersToSeed()
+		r.log.Infof(log.Router, r.logId, "Reading routing table from initial routers: %v", rootRouters)
+		table, err = readTable(ctx, r.pool, rootRouters, r.routerContext, r.idlenessTimeout, bookmarks, database, impersonatedUser, auth, boltLogger)
+	}
+	if errorutil.IsFatalDuringDiscovery(err) {
+		r.log.Error(log.Router, r.logId, err)
+		return nil, err
+	}
+
+	// Try routers of other databases if failed, e.g. routers imported from a cache while the initial router is down
+	if table == nil {
+		var tried []string
+		if dbRouter != nil {
+			tried = dbRouter.table.Routers
+		}
+		if routers := r.knownRouters(append(r.routersToSeed(), tried...)...); len(routers) > 0 {
+			r.log.Infof(log.Router, r.logId, "Reading routing table for '%s' from routers of other databases: %v", database, routers)
+			table, err = readTable(ctx, r.pool, routers, r.routerContext, r.idlenessTimeout, bookmarks, database, impersonatedUser, auth, boltLogger)
+		}
 	}
 	if errorutil.IsFatalDuringDiscovery(err) {
 		r.log.Error(log.Router, r.logId, err)
@@ -141,6 +191,43 @@ func (r *Router) readTable(
 	return table, nil
 }
 
+// SetInitialRouters sets the routers tried when no routing table is known or when the known routers fail, e.g. all
+// the hosts of a neo4j://a:7687,b:7687 URI. They are tried in order, or in random order when shuffle is true.
+// Must be called before the router is used.
+func (r *Router) SetInitialRouters(routers []string, shuffle bool) {
+	r.rootRouters = routers
+	r.shuffleRoots = shuffle
+}
+
+// InitialRouters returns the routers set with SetInitialRouters, in order.
+func (r *Router) InitialRouters() []string {
+	return append([]string(nil), r.rootRouters...)
+}
+
+func (r *Router) routersToSeed() []string {
+	routers := r.InitialRouters()
+	if r.shuffleRoots {
+		rand.Shuffle(len(routers), func(i, j int) {
+			routers[i], routers[j] = routers[j], routers[i]
+		})
+	}
+	return routers
+}
+
+func (r *Router) spanAttributes(database, impersonatedUser string) map[string]any {
+	if r.tracer == nil {
+		return nil
+	}
+	attributes := make(map[string]any, 2)
+	if database != idb.DefaultDatabase {
+		attributes[tracing.DatabaseAttribute] = database
+	}
+	if impersonatedUser != "" {
+		attributes[tracing.ImpersonatedUserAttribute] = impersonatedUser
+	}
+	return attributes
+}
+
 func (r *Router) getTable(database string) *idb.RoutingTable {
 	r.dbRoutersMut.Lock()
 	defer r.dbRoutersMut.Unlock()
@@ -150,13 +237,25 @@ func (r *Router) getTable(database string) *idb.RoutingTable {
 }
 
 func (r *Router) getOrUpdateTable(ctx context.Context, bookmarksFn func(context.Context) ([]string, error), database string, auth *idb.ReAuthToken, boltLogger log.BoltLogger) (*idb.RoutingTable, error) {
+	table, _, err := r.getOrUpdate(ctx, bookmarksFn, database, auth, boltLogger, false)
+	return table, err
+}
+
+// getOrUpdate returns the routing table of the database, fetching it first if the router does not hold a valid one.
+// When snapshot is true, it also returns a snapshot of the table taken under the same lock the table was obtained
+// with, so that it describes that very table even if another goroutine replaces or evicts it in the meantime.
+func (r *Router) getOrUpdate(ctx context.Context, bookmarksFn func(context.Context) ([]string, error), database string, auth *idb.ReAuthToken, boltLogger log.BoltLogger, snapshot bool) (*idb.RoutingTable, routing.Table, error) {
 	r.dbRoutersMut.Lock()
 	var unlock = new(sync.Once)
 	defer unlock.Do(r.dbRoutersMut.Unlock)
 	for {
 		dbRouter := r.dbRouters[database]
 		if table := r.getTableLocked(dbRouter); table != nil {
-			return table, nil
+			dbRouter.lastUsedUnix = itime.Now().Unix()
+			if snapshot {
+				return table, tableOf(database, dbRouter), nil
+			}
+			return table, routing.Table{}, nil
 		}
 		waiters, ok := r.updating[database]
 		if ok {
@@ -166,7 +265,7 @@ func (r *Router) getOrUpdateTable(ctx context.Context, bookmarksFn func(context.
 			unlock.Do(r.dbRoutersMut.Unlock)
 			select {
 			case <-ctx.Done():
-				return nil, racing.LockTimeoutError("timed out waiting for other goroutine to update routing table")
+				return nil, routing.Table{}, racing.LockTimeoutError("timed out waiting for other goroutine to update routing table")
 			case <-ch:
 				r.dbRoutersMut.Lock()
 				*unlock = sync.Once{}
@@ -177,7 +276,7 @@ func (r *Router) getOrUpdateTable(ctx context.Context, bookmarksFn func(context.
 		r.updating[database] = make([]chan struct{}, 0)
 		unlock.Do(r.dbRoutersMut.Unlock)
 
-		table, err := r.updateTable(ctx, bookmarksFn, database, auth, boltLogger, dbRouter)
+		table, stored, err := r.updateTable(ctx, bookmarksFn, database, auth, boltLogger, dbRouter)
 		r.dbRoutersMut.Lock()
 		*unlock = sync.Once{}
 		// notify all waiters
@@ -185,7 +284,10 @@ func (r *Router) getOrUpdateTable(ctx context.Context, bookmarksFn func(context.
 			close(waiter)
 		}
 		delete(r.updating, database)
-		return table, err
+		if current := r.dbRouters[database]; current != nil && err == nil {
+			current.lastUsedUnix = itime.Now().Unix()
+		}
+		return table, stored, err
 	}
 }
 
@@ -197,22 +299,23 @@ func (r *Router) getTableLocked(dbRouter *databaseRouter) *idb.RoutingTable {
 	return nil
 }
 
-func (r *Router) updateTable(ctx context.Context, bookmarksFn func(context.Context) ([]string, error), database string, auth *idb.ReAuthToken, boltLogger log.BoltLogger, dbRouter *databaseRouter) (*idb.RoutingTable, error) {
+// updateTable fetches and stores the routing table of the database, returning the table and a snapshot of it
+func (r *Router) updateTable(ctx context.Context, bookmarksFn func(context.Context) ([]string, error), database string, auth *idb.ReAuthToken, boltLogger log.BoltLogger, dbRouter *databaseRouter) (*idb.RoutingTable, routing.Table, error) {
 	bookmarks, err := bookmarksFn(ctx)
 	if err != nil {
-		return nil, err
+		return nil, routing.Table{}, err
 	}
 	table, err := r.readTable(ctx, dbRouter, bookmarks, database, "", auth, boltLogger)
 	if err != nil {
-		return nil, err
+		return nil, routing.Table{}, err
 	}
 
-	err = r.storeRoutingTable(ctx, database, table, itime.Now())
+	stored, err := r.storeRoutingTable(ctx, database, table, itime.Now())
 	if err != nil {
-		return nil, err
+		return nil, routing.Table{}, err
 	}
 
-	return table, nil
+	return table, stored, nil
 }
 
 func (r *Router) GetOrUpdateReaders(ctx context.Context, bookmarks func(context.Context) ([]string, error), database string, auth *idb.ReAuthToken, boltLogger log.BoltLogger) ([]string, error) {
@@ -295,7 +398,7 @@ func (r *Router) GetNameOfDefaultDatabase(ctx context.Context, bookmarks []strin
 	}
 	// Store the fresh routing table as well to avoid another roundtrip to receive servers from session.
 	now := itime.Now()
-	err = r.storeRoutingTable(ctx, table.DatabaseName, table, now)
+	_, err = r.storeRoutingTable(ctx, table.DatabaseName, table, now)
 	if err != nil {
 		return "", err
 	}
@@ -320,34 +423,61 @@ func (r *Router) Invalidate(database string) {
 
 func (r *Router) InvalidateWriter(db string, server string) {
 	r.dbRoutersMut.Lock()
-	defer r.dbRoutersMut.Unlock()
-
 	router := r.dbRouters[db]
 	if router == nil {
+		r.dbRoutersMut.Unlock()
 		return
 	}
+	previous := cloneRouter(router)
 	router.table.Writers = removeServerFromList(router.table.Writers, server)
+	change := changeOf(db, previous, tableOf(db, router))
+	r.dbRoutersMut.Unlock()
+
+	if change.WritersChanged() {
+		r.notifyDeactivated(server, db, events.WriterRole)
+	}
+	r.notifyChanges(change)
 }
 
 func (r *Router) InvalidateReader(db string, server string) {
 	r.dbRoutersMut.Lock()
-	defer r.dbRoutersMut.Unlock()
-
 	router := r.dbRouters[db]
 	if router == nil {
+		r.dbRoutersMut.Unlock()
 		return
 	}
+	previous := cloneRouter(router)
 	router.table.Readers = removeServerFromList(router.table.Readers, server)
+	change := changeOf(db, previous, tableOf(db, router))
+	r.dbRoutersMut.Unlock()
+
+	if len(change.RemovedReaders) > 0 {
+		r.notifyDeactivated(server, db, events.ReaderRole)
+	}
+	r.notifyChanges(change)
+}
+
+func (r *Router) notifyDeactivated(server, database string, role events.Role) {
+	ievents.Notify(r.listener, events.ConnectionEvent{
+		Type:     events.ServerDeactivated,
+		Server:   server,
+		Database: database,
+		Role:     role,
+	})
 }
 
 func (r *Router) InvalidateServer(server string) {
 	r.dbRoutersMut.Lock()
-	defer r.dbRoutersMut.Unlock()
-	for _, routing := range r.dbRouters {
-		routing.table.Routers = removeServerFromList(routing.table.Routers, server)
-		routing.table.Readers = removeServerFromList(routing.table.Readers, server)
-		routing.table.Writers = removeServerFromList(routing.table.Writers, server)
-	}
+	changes := make([]routing.Change, 0, len(r.dbRouters))
+	for db, router := range r.dbRouters {
+		previous := cloneRouter(router)
+		router.table.Routers = removeServerFromList(router.table.Routers, server)
+		router.table.Readers = removeServerFromList(router.table.Readers, server)
+		router.table.Writers = removeServerFromList(router.table.Writers, server)
+		changes = append(changes, changeOf(db, previous, tableOf(db, router)))
+	}
+	r.dbRoutersMut.Unlock()
+	r.notifyChanges(changes...)
 }
 
 func removeServerFromList(list []string, server string) []string {
@@ -360,27 +490,48 @@ func removeServerFromList(list []string, server string) []string {
 }
 
 func (r *Router) CleanUp() {
+	r.dbRoutersMut.Lock()
+	if r.refresher != nil {
+		// The refresher evicts the routing tables of the databases not used recently
+		r.dbRoutersMut.Unlock()
+		return
+	}
 	r.log.Debugf(log.Router, r.logId, "Cleaning up")
 	now := itime.Now().Unix()
-	r.dbRoutersMut.Lock()
-	defer r.dbRoutersMut.Unlock()
 
+	var changes []routing.Change
 	for dbName, dbRouter := range r.dbRouters {
 		if now > dbRouter.dueUnix {
 			delete(r.dbRouters, dbName)
+			changes = append(changes, changeOf(dbName, dbRouter, routing.Table{Database: dbName}))
 		}
 	}
+	r.dbRoutersMut.Unlock()
+	if len(changes) > 0 {
+		r.notifyChanges(changes...)
+		r.exportTables()
+	}
 }
 
-func (r *Router) storeRoutingTable(ctx context.Context, database string, table *idb.RoutingTable, now time.Time) error {
+// storeRoutingTable stores the routing table of the database and returns a snapshot of it
+func (r *Router) storeRoutingTable(ctx context.Context, database string, table *idb.RoutingTable, now time.Time) (routing.Table, error) {
 	r.dbRoutersMut.Lock()
-	defer r.dbRoutersMut.Unlock()
-	r.dbRouters[database] = &databaseRouter{
-		table:   table,
-		dueUnix: now.Add(time.Duration(table.TimeToLive) * time.Second).Unix(),
-	}
+	previous := r.dbRouters[database]
+	current := &databaseRouter{
+		table:        table,
+		dueUnix:      now.Add(time.Duration(table.TimeToLive) * time.Second).Unix(),
+		lastUsedUnix: now.Unix(),
+	}
+	if previous != nil {
+		current.lastUsedUnix = previous.lastUsedUnix
+	}
+	r.dbRouters[database] = current
+	change := changeOf(database, previous, tableOf(database, current))
+	r.dbRoutersMut.Unlock()
 	r.log.Debugf(log.Router, r.logId, "New routing table for '%s', TTL %d", database, table.TimeToLive)
-	return nil
+	r.notifyChanges(change)
+	r.exportTables()
+	return change.Current, nil
 }
 
 func wrapError(server string, err error) error {
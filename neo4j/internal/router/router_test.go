@@ -24,15 +24,19 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"sort"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/events"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	pool2 "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/pool"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
 	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
 )
 
 var logger = log.ToVoid()
@@ -57,7 +61,7 @@ func TestMultithreading(t *testing.T) {
 		// Need to lock here to make race detector happy
 		*now = now.Add(time.Duration(table.TimeToLive) * time.Second * 2)
 	})
-	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid")
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
 
 	dbName := "dbname"
 	wg := sync.WaitGroup{}
@@ -106,7 +110,7 @@ func TestRespectsTimeToLiveAndInvalidate(t *testing.T) {
 	}
 	itime.ForceFreezeTime()
 	defer itime.ForceUnfreezeTime()
-	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid")
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
 	dbName := "dbname"
 
 	// First access should trigger initial table read
@@ -160,7 +164,7 @@ func TestUsesRootRouterWhenPreviousRoutersFails(t *testing.T) {
 	}
 	itime.ForceFreezeTime()
 	defer itime.ForceUnfreezeTime()
-	router := New("rootRouter", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid")
+	router := New("rootRouter", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
 	dbName := "dbname"
 
 	// First access should trigger initial table read from root router
@@ -223,7 +227,7 @@ func TestUseGetRoutersHookWhenInitialRouterFails(t *testing.T) {
 	}
 	rootRouter := "rootRouter"
 	backupRouters := []string{"bup1", "bup2"}
-	router := New(rootRouter, func() []string { return backupRouters }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid")
+	router := New(rootRouter, func() []string { return backupRouters }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
 	dbName := "dbname"
 
 	// Trigger read of routing table
@@ -238,6 +242,50 @@ func TestUseGetRoutersHookWhenInitialRouterFails(t *testing.T) {
 	}
 }
 
+func TestTriesInitialRoutersInOrder(outer *testing.T) {
+	initialRouters := []string{"seed1", "seed2", "seed3"}
+
+	outer.Run("in the given order", func(t *testing.T) {
+		var tried []string
+		pool := &poolFake{
+			borrow: func(names []string, cancel context.CancelFunc, _ log.BoltLogger) (db.Connection, error) {
+				tried = append(tried, names...)
+				if names[0] == "seed2" {
+					return &testutil.ConnFake{Table: &db.RoutingTable{TimeToLive: 1, Readers: []string{"reader"}}}, nil
+				}
+				return nil, errors.New("fail")
+			},
+		}
+		router := New("seed1", nil, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
+		router.SetInitialRouters(initialRouters, false)
+
+		readers, err := router.GetOrUpdateReaders(context.Background(), nilBookmarks, "dbname", nil, nil)
+
+		testutil.AssertNoError(t, err)
+		testutil.AssertDeepEquals(t, readers, []string{"reader"})
+		testutil.AssertDeepEquals(t, tried, []string{"seed1", "seed2"})
+	})
+
+	outer.Run("in random order", func(t *testing.T) {
+		var tried []string
+		pool := &poolFake{
+			borrow: func(names []string, cancel context.CancelFunc, _ log.BoltLogger) (db.Connection, error) {
+				tried = append(tried, names...)
+				return nil, errors.New("fail")
+			},
+		}
+		router := New("seed1", nil, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
+		router.SetInitialRouters(initialRouters, true)
+
+		_, err := router.GetOrUpdateReaders(context.Background(), nilBookmarks, "dbname", nil, nil)
+
+		testutil.AssertError(t, err)
+		sort.Strings(tried)
+		testutil.AssertDeepEquals(t, tried, initialRouters)
+		testutil.AssertDeepEquals(t, router.InitialRouters(), initialRouters)
+	})
+}
+
 func TestWritersFailAfterNRetries(t *testing.T) {
 	numfetch := 0
 	tableNoWriters := &db.RoutingTable{TimeToLive: 1, Routers: []string{"rt1", "rt2"}, Readers: []string{"rd1"}}
@@ -249,7 +297,7 @@ func TestWritersFailAfterNRetries(t *testing.T) {
 		},
 	}
 	numsleep := 0
-	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid")
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
 	router.sleep = func(time.Duration) {
 		numsleep++
 	}
@@ -286,7 +334,7 @@ func TestWritersRetriesWhenNoWriters(t *testing.T) {
 		},
 	}
 	numsleep := 0
-	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid")
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
 	router.sleep = func(time.Duration) {
 		numsleep++
 	}
@@ -324,7 +372,7 @@ func TestReadersRetriesWhenNoReaders(t *testing.T) {
 		},
 	}
 	numsleep := 0
-	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid")
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
 	router.sleep = func(time.Duration) {
 		numsleep++
 	}
@@ -358,7 +406,7 @@ func TestCleanUp(t *testing.T) {
 	}
 	itime.ForceFreezeTime()
 	defer itime.ForceUnfreezeTime()
-	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid")
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
 
 	ctx := context.Background()
 	if _, err := router.GetOrUpdateReaders(ctx, nilBookmarks, "db1", nil, nil); err != nil {
@@ -379,11 +427,209 @@ func TestCleanUp(t *testing.T) {
 		t.Fatal("Should not have removed routing tables")
 	}
 
+	var changes []routing.Change
+	router.SubscribeChanges(func(change routing.Change) {
+		changes = append(changes, change)
+	})
 	itime.ForceTickTime(1 * time.Minute)
 	router.CleanUp()
 	if len(router.dbRouters) != 0 {
 		t.Fatal("Should have cleaned up")
 	}
+	// Subscribers are told about the removal of every table
+	testutil.AssertLen(t, changes, 2)
+	for _, change := range changes {
+		testutil.AssertDeepEquals(t, change.Current, routing.Table{Database: change.Database})
+		testutil.AssertDeepEquals(t, change.RemovedReaders, []string{"router1"})
+	}
 }
 
 func nilBookmarks(context.Context) ([]string, error) { return nil, nil }
+
+func TestTracesRoutingTableRefresh(t *testing.T) {
+	table := &db.RoutingTable{TimeToLive: 1, Routers: []string{"rt"}, Readers: []string{"rd"}, Writers: []string{"wr"}}
+	pool := &poolFake{
+		borrow: func(names []string, cancel context.CancelFunc, _ log.BoltLogger) (db.Connection, error) {
+			return &testutil.ConnFake{Table: table}, nil
+		},
+	}
+	tracer := &testutil.TracerFake{}
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", tracer, nil)
+
+	if _, err := router.GetOrUpdateReaders(context.Background(), nilBookmarks, "dbname", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tracer.Spans) != 1 {
+		t.Fatalf("Expected one span but got %d", len(tracer.Spans))
+	}
+	span := tracer.Spans[0]
+	if span.Operation != tracing.RoutingTableRefreshOperation || !span.Ended || span.Err != nil {
+		t.Errorf("Unexpected span: %+v", span)
+	}
+	if !reflect.DeepEqual(span.StartAttributes, map[string]any{tracing.DatabaseAttribute: "dbname"}) {
+		t.Errorf("Unexpected attributes: %v", span.StartAttributes)
+	}
+}
+
+func TestRoutingTableMetrics(t *testing.T) {
+	itime.ForceFreezeTime()
+	defer itime.ForceUnfreezeTime()
+	table := &db.RoutingTable{TimeToLive: 1, DatabaseName: "dbname", Routers: []string{"rt"}, Readers: []string{"rd"}, Writers: []string{"wr"}}
+	fail := true
+	pool := &poolFake{
+		borrow: func(names []string, cancel context.CancelFunc, _ log.BoltLogger) (db.Connection, error) {
+			if fail {
+				return nil, errors.New("unavailable")
+			}
+			return &testutil.ConnFake{Table: table}, nil
+		},
+	}
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
+
+	if _, err := router.GetOrUpdateReaders(context.Background(), nilBookmarks, "dbname", nil, nil); err == nil {
+		t.Fatal("Should have failed")
+	}
+	fail = false
+	if _, err := router.GetOrUpdateReaders(context.Background(), nilBookmarks, "dbname", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	routingMetrics := router.Metrics()["dbname"]
+	if routingMetrics.Refreshes != 1 || routingMetrics.FailedRefreshes != 1 {
+		t.Errorf("Unexpected refresh counts: %+v", routingMetrics)
+	}
+	if !routingMetrics.LastRefresh.Equal(itime.Now()) {
+		t.Errorf("Unexpected last refresh time %s", routingMetrics.LastRefresh)
+	}
+	if routingMetrics.RefreshTime.Count != 1 {
+		t.Errorf("Expected one refresh time observation but got %d", routingMetrics.RefreshTime.Count)
+	}
+}
+
+func TestNotifiesDeactivatedWriters(t *testing.T) {
+	table := &db.RoutingTable{TimeToLive: 1000, DatabaseName: "dbname", Routers: []string{"rt"}, Readers: []string{"rd"}, Writers: []string{"wr1", "wr2"}}
+	pool := &poolFake{
+		borrow: func(names []string, cancel context.CancelFunc, _ log.BoltLogger) (db.Connection, error) {
+			return &testutil.ConnFake{Table: table}, nil
+		},
+	}
+	var notified []events.ConnectionEvent
+	listener := events.ConnectionListenerFunc(func(event events.ConnectionEvent) {
+		notified = append(notified, event)
+	})
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, listener)
+	if _, err := router.GetOrUpdateWriters(context.Background(), nilBookmarks, "dbname", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	router.InvalidateWriter("dbname", "wr1")
+	router.InvalidateWriter("dbname", "wr1")
+
+	if len(notified) != 1 {
+		t.Fatalf("Expected one event but got %d", len(notified))
+	}
+	event := notified[0]
+	if event.Type != events.ServerDeactivated || event.Server != "wr1" || event.Database != "dbname" || event.Role != events.WriterRole {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+}
+
+func TestGetOrUpdateTableSnapshotsFetchedTable(t *testing.T) {
+	itime.ForceFreezeTime()
+	defer itime.ForceUnfreezeTime()
+	table := &db.RoutingTable{TimeToLive: 10, DatabaseName: "dbname", Routers: []string{"rt"}, Readers: []string{"rd"}, Writers: []string{"wr"}}
+	pool := &poolFake{
+		borrow: func(names []string, cancel context.CancelFunc, _ log.BoltLogger) (db.Connection, error) {
+			return &testutil.ConnFake{Table: table}, nil
+		},
+	}
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
+	evicted := false
+	// Evicts the table as soon as it is stored, before GetOrUpdateTable returns
+	router.SubscribeChanges(func(routing.Change) {
+		if !evicted {
+			evicted = true
+			router.Invalidate("dbname")
+			router.CleanUp()
+		}
+	})
+
+	snapshot, err := router.GetOrUpdateTable(context.Background(), nilBookmarks, "dbname", nil, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := router.Table("dbname"); found || !evicted {
+		t.Fatalf("Expected table to be evicted")
+	}
+	expected := routing.Table{
+		Database:   "dbname",
+		Routers:    []string{"rt"},
+		Readers:    []string{"rd"},
+		Writers:    []string{"wr"},
+		TimeToLive: 10 * time.Second,
+		ExpiresAt:  time.Unix(itime.Now().Add(10*time.Second).Unix(), 0),
+	}
+	if !reflect.DeepEqual(snapshot, expected) {
+		t.Errorf("Unexpected table: %+v", snapshot)
+	}
+}
+
+func TestRoutingTableChanges(t *testing.T) {
+	itime.ForceFreezeTime()
+	defer itime.ForceUnfreezeTime()
+	table := &db.RoutingTable{TimeToLive: 10, DatabaseName: "dbname", Routers: []string{"rt"}, Readers: []string{"rd1", "rd2"}, Writers: []string{"wr1"}}
+	pool := &poolFake{
+		borrow: func(names []string, cancel context.CancelFunc, _ log.BoltLogger) (db.Connection, error) {
+			return &testutil.ConnFake{Table: table}, nil
+		},
+	}
+	router := New("router", func() []string { return []string{} }, nil, pool, pool2.DefaultConnectionLivenessCheckTimeout, logger, "routerid", nil, nil)
+	var changes []routing.Change
+	unsubscribe := router.SubscribeChanges(func(change routing.Change) {
+		changes = append(changes, change)
+	})
+
+	snapshot, err := router.GetOrUpdateTable(context.Background(), nilBookmarks, "dbname", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := routing.Table{
+		Database:   "dbname",
+		Routers:    []string{"rt"},
+		Readers:    []string{"rd1", "rd2"},
+		Writers:    []string{"wr1"},
+		TimeToLive: 10 * time.Second,
+		ExpiresAt:  time.Unix(itime.Now().Add(10*time.Second).Unix(), 0),
+	}
+	if !reflect.DeepEqual(snapshot, expected) {
+		t.Errorf("Unexpected table: %+v", snapshot)
+	}
+	if len(changes) != 1 || changes[0].Previous != nil || !reflect.DeepEqual(changes[0].AddedWriters, []string{"wr1"}) {
+		t.Fatalf("Unexpected changes: %+v", changes)
+	}
+
+	router.InvalidateReader("dbname", "rd1")
+	if len(changes) != 2 || !reflect.DeepEqual(changes[1].RemovedReaders, []string{"rd1"}) || changes[1].WritersChanged() {
+		t.Fatalf("Unexpected changes: %+v", changes)
+	}
+	if !reflect.DeepEqual(changes[1].Previous.Readers, []string{"rd1", "rd2"}) {
+		t.Errorf("Previous table should be left untouched: %+v", changes[1].Previous)
+	}
+
+	table = &db.RoutingTable{TimeToLive: 10, DatabaseName: "dbname", Routers: []string{"rt"}, Readers: []string{"rd2"}, Writers: []string{"wr2"}}
+	router.Invalidate("dbname")
+	if _, err := router.GetOrUpdateTable(context.Background(), nilBookmarks, "dbname", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 3 || !changes[2].WritersChanged() || !reflect.DeepEqual(changes[2].RemovedWriters, []string{"wr1"}) {
+		t.Fatalf("Unexpected changes: %+v", changes)
+	}
+
+	unsubscribe()
+	router.InvalidateServer("rd2")
+	if len(changes) != 3 {
+		t.Errorf("Unsubscribed listener should not be called")
+	}
+}
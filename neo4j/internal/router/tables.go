@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/collections"
+	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/routing"
+)
+
+// Table returns a snapshot of the routing table of the database, if the router holds one.
+// The table may have expired.
+func (r *Router) Table(database string) (routing.Table, bool) {
+	r.dbRoutersMut.Lock()
+	defer r.dbRoutersMut.Unlock()
+	dbRouter := r.dbRouters[database]
+	if dbRouter == nil {
+		return routing.Table{}, false
+	}
+	return tableOf(database, dbRouter), true
+}
+
+// GetOrUpdateTable returns a snapshot of the routing table of the database, fetching the table first if the router
+// does not hold a valid one.
+func (r *Router) GetOrUpdateTable(ctx context.Context, bookmarks func(context.Context) ([]string, error), database string, auth *idb.ReAuthToken, boltLogger log.BoltLogger) (routing.Table, error) {
+	_, table, err := r.getOrUpdate(ctx, bookmarks, database, auth, boltLogger, true)
+	return table, err
+}
+
+// Servers returns the readers and writers of all the routing tables the router holds, expired or not.
+func (r *Router) Servers() []string {
+	r.dbRoutersMut.Lock()
+	defer r.dbRoutersMut.Unlock()
+	servers := collections.NewSet[string](nil)
+	for _, dbRouter := range r.dbRouters {
+		if dbRouter.table != nil {
+			servers.AddAll(dbRouter.table.Readers)
+			servers.AddAll(dbRouter.table.Writers)
+		}
+	}
+	return servers.Values()
+}
+
+// HasServer tells whether any routing table the router holds lists the server as router, reader or writer, expired
+// or not.
+func (r *Router) HasServer(server string) bool {
+	r.dbRoutersMut.Lock()
+	defer r.dbRoutersMut.Unlock()
+	for _, dbRouter := range r.dbRouters {
+		if table := dbRouter.table; table != nil &&
+			(contains(table.Routers, server) || contains(table.Readers, server) || contains(table.Writers, server)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SubscribeChanges registers a listener called with every change of the routing tables.
+// The returned function unregisters the listener.
+func (r *Router) SubscribeChanges(listener routing.ChangeListener) func() {
+	r.listenersMut.Lock()
+	defer r.listenersMut.Unlock()
+	id := r.nextListenerId
+	r.nextListenerId++
+	r.listeners[id] = listener
+	return func() {
+		r.listenersMut.Lock()
+		defer r.listenersMut.Unlock()
+		delete(r.listeners, id)
+	}
+}
+
+// notifyChanges calls the listeners with the given changes, leaving out the ones that do not add or remove any server.
+// Must not be called while holding the routing table lock, listeners may call back into the router.
+func (r *Router) notifyChanges(changes ...routing.Change) {
+	r.listenersMut.Lock()
+	listeners := make([]routing.ChangeListener, 0, len(r.listeners))
+	for _, listener := range r.listeners {
+		listeners = append(listeners, listener)
+	}
+	r.listenersMut.Unlock()
+	for _, change := range changes {
+		if change.IsEmpty() {
+			continue
+		}
+		for _, listener := range listeners {
+			listener(change)
+		}
+	}
+}
+
+func tableOf(database string, dbRouter *databaseRouter) routing.Table {
+	return routing.Table{
+		Database:   database,
+		Routers:    copyOf(dbRouter.table.Routers),
+		Readers:    copyOf(dbRouter.table.Readers),
+		Writers:    copyOf(dbRouter.table.Writers),
+		TimeToLive: time.Duration(dbRouter.table.TimeToLive) * time.Second,
+		ExpiresAt:  time.Unix(dbRouter.dueUnix, 0),
+	}
+}
+
+// changeOf computes the change of the routing table of the database, previousRouter being nil if there was no table
+func changeOf(database string, previousRouter *databaseRouter, current routing.Table) routing.Change {
+	change := routing.Change{Database: database, Current: current}
+	var previous routing.Table
+	if previousRouter != nil {
+		previous = tableOf(database, previousRouter)
+		change.Previous = &previous
+	}
+	change.AddedRouters, change.RemovedRouters = diff(previous.Routers, current.Routers)
+	change.AddedReaders, change.RemovedReaders = diff(previous.Readers, current.Readers)
+	change.AddedWriters, change.RemovedWriters = diff(previous.Writers, current.Writers)
+	return change
+}
+
+func diff(previous, current []string) (added []string, removed []string) {
+	for _, server := range current {
+		if !contains(previous, server) {
+			added = append(added, server)
+		}
+	}
+	for _, server := range previous {
+		if !contains(current, server) {
+			removed = append(removed, server)
+		}
+	}
+	return added, removed
+}
+
+func contains(servers []string, server string) bool {
+	for _, s := range servers {
+		if s == server {
+			return true
+		}
+	}
+	return false
+}
+
+func copyOf(servers []string) []string {
+	return append([]string(nil), servers...)
+}
+
+// cloneRouter copies the router so that it is not affected by servers being removed from the table in place
+func cloneRouter(dbRouter *databaseRouter) *databaseRouter {
+	if dbRouter == nil {
+		return nil
+	}
+	return &databaseRouter{
+		dueUnix: dbRouter.dueUnix,
+		table: &idb.RoutingTable{
+			TimeToLive:   dbRouter.table.TimeToLive,
+			DatabaseName: dbRouter.table.DatabaseName,
+			Routers:      copyOf(dbRouter.table.Routers),
+			Readers:      copyOf(dbRouter.table.Readers),
+			Writers:      copyOf(dbRouter.table.Writers),
+		},
+	}
+}
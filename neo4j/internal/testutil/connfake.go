@@ -19,6 +19,7 @@ package testutil
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/auth"
@@ -72,6 +73,7 @@ type ConnFake struct {
 	Idle               time.Time
 	ServerVersionValue string
 	ForceResetHook     func()
+	CloseHook          func(context.Context)
 	ReAuthHook         func(context.Context, *idb.ReAuthToken) error
 }
 
@@ -106,6 +108,9 @@ func (c *ConnFake) ForceReset(context.Context) {
 }
 
 func (c *ConnFake) Close(ctx context.Context) {
+	if c.CloseHook != nil {
+		c.CloseHook(ctx)
+	}
 }
 
 func (c *ConnFake) Birthdate() time.Time {
@@ -124,6 +129,10 @@ func (c *ConnFake) ServerVersion() string {
 	return c.ServerVersionValue
 }
 
+func (c *ConnFake) ConnectionId() string {
+	return fmt.Sprintf("bolt-%d", c.Id)
+}
+
 func (c *ConnFake) Buffer(context.Context, idb.StreamHandle) error {
 	if c.BufferHook != nil {
 		c.BufferHook()
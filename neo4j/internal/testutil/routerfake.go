@@ -21,6 +21,7 @@ import (
 	"context"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
 )
 
 type RouterFake struct {
@@ -35,6 +36,7 @@ type RouterFake struct {
 	Err                    error
 	CleanUpHook            func()
 	GetNameOfDefaultDbHook func(user string) (string, error)
+	KnownServers           []string
 }
 
 func (r *RouterFake) InvalidateReader(database string, server string) {
@@ -93,3 +95,20 @@ func (r *RouterFake) CleanUp() {
 		r.CleanUpHook()
 	}
 }
+
+func (r *RouterFake) Servers() []string {
+	return nil
+}
+
+func (r *RouterFake) HasServer(server string) bool {
+	for _, known := range r.KnownServers {
+		if known == server {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RouterFake) Metrics() map[string]metrics.RoutingTableMetrics {
+	return nil
+}
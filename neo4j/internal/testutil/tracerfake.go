@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package testutil
+
+import (
+	"context"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
+)
+
+// TracerFake records every span, in the order they are started
+type TracerFake struct {
+	Spans []*SpanFake
+}
+
+type SpanFake struct {
+	Operation       tracing.Operation
+	StartAttributes map[string]any
+	EndAttributes   map[string]any
+	Err             error
+	Ended           bool
+	// Parent is the span found in the context the span was started with
+	Parent *SpanFake
+}
+
+type spanFakeKey struct{}
+
+func (t *TracerFake) Start(ctx context.Context, operation tracing.Operation, attributes map[string]any) (context.Context, tracing.Span) {
+	span := &SpanFake{Operation: operation, StartAttributes: attributes}
+	span.Parent, _ = ctx.Value(spanFakeKey{}).(*SpanFake)
+	t.Spans = append(t.Spans, span)
+	return context.WithValue(ctx, spanFakeKey{}, span), span
+}
+
+// Operations returns the operation of every recorded span
+func (t *TracerFake) Operations() []tracing.Operation {
+	operations := make([]tracing.Operation, len(t.Spans))
+	for i, span := range t.Spans {
+		operations[i] = span.Operation
+	}
+	return operations
+}
+
+func (s *SpanFake) End(attributes map[string]any, err error) {
+	s.EndAttributes = attributes
+	s.Err = err
+	s.Ended = true
+}
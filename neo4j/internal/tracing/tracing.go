@@ -0,0 +1,116 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package tracing simplifies the calls to the user-provided tracing.Tracer.
+package tracing
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
+)
+
+// Start starts a span of the operation if the tracer is set.
+// A no-op span is returned otherwise.
+func Start(ctx context.Context, tracer tracing.Tracer, operation tracing.Operation, attributes map[string]any) (context.Context, tracing.Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return tracer.Start(ctx, operation, attributes)
+}
+
+// noopSpan is the span returned when no tracer is set.
+type noopSpan struct{}
+
+func (noopSpan) End(map[string]any, error) {}
+
+// parentKey identifies the parent of the spans started from a context, see MarkParent
+type parentKey struct{}
+
+// parent is the span of an operation, made of the context given to the tracer and of the one it returned
+type parent struct {
+	base context.Context
+	span context.Context
+	// outer is the parent of the span, if any
+	outer *parent
+}
+
+// MarkParent marks the context returned by the tracer for a span, given base, the context the tracer was called
+// with, so that spans started with WithParent are children of that span.
+func MarkParent(base, span context.Context) context.Context {
+	outer, _ := base.Value(parentKey{}).(*parent)
+	return context.WithValue(span, parentKey{}, &parent{base: base, span: span, outer: outer})
+}
+
+// WithParent returns a context that is cancelled along with ctx and holds its values, except for the values the
+// tracer set for the span of parentCtx, which is expected to be marked by MarkParent.
+// Spans started with the returned context are thereby children of the span of parentCtx, without the other values
+// of parentCtx hiding those of ctx.
+// ctx is returned as is when parentCtx is nil or not marked, or when ctx already derives from parentCtx.
+func WithParent(ctx context.Context, parentCtx context.Context) context.Context {
+	if parentCtx == nil {
+		return ctx
+	}
+	spanParent, marked := parentCtx.Value(parentKey{}).(*parent)
+	if !marked {
+		return ctx
+	}
+	ctxParent, _ := ctx.Value(parentKey{}).(*parent)
+	for ; ctxParent != nil; ctxParent = ctxParent.outer {
+		if ctxParent == spanParent {
+			return ctx
+		}
+	}
+	return &parentContext{Context: ctx, parent: spanParent}
+}
+
+// parentContext looks up the values the tracer set for the span of parent in parent, and delegates everything else
+// to the embedded context
+type parentContext struct {
+	context.Context
+	parent *parent
+}
+
+func (c *parentContext) Value(key any) any {
+	if key == (parentKey{}) {
+		return c.parent
+	}
+	// the tracer set the values that differ between the context it was given and the one it returned
+	if value := c.parent.span.Value(key); !sameValue(value, c.parent.base.Value(key)) {
+		return value
+	}
+	return c.Context.Value(key)
+}
+
+func sameValue(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	typ := reflect.TypeOf(a)
+	if typ != reflect.TypeOf(b) {
+		return false
+	}
+	switch typ.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+	}
+	if !typ.Comparable() {
+		return false
+	}
+	return a == b
+}
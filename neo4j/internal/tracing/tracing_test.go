@@ -0,0 +1,70 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+)
+
+type key string
+
+func TestWithParent(outer *testing.T) {
+	// startSpan mimics a tracer, which sets the current span in the returned context
+	startSpan := func(ctx context.Context, span string) context.Context {
+		return MarkParent(ctx, context.WithValue(ctx, key("span"), span))
+	}
+	sessionBase := context.WithValue(context.Background(), key("user"), "session")
+	sessionCtx := startSpan(sessionBase, "session")
+
+	outer.Run("takes the span from the parent and other values from the context", func(t *testing.T) {
+		callCtx := context.WithValue(context.WithValue(context.Background(), key("user"), "call"), key("span"), "caller")
+
+		ctx := WithParent(callCtx, sessionCtx)
+
+		testutil.AssertDeepEquals(t, ctx.Value(key("span")), "session")
+		testutil.AssertDeepEquals(t, ctx.Value(key("user")), "call")
+	})
+
+	outer.Run("hides the values of the parent the tracer did not set", func(t *testing.T) {
+		ctx := WithParent(context.Background(), sessionCtx)
+
+		testutil.AssertDeepEquals(t, ctx.Value(key("user")), nil)
+		testutil.AssertDeepEquals(t, ctx.Value(key("span")), "session")
+	})
+
+	outer.Run("leaves contexts deriving from the parent as is", func(t *testing.T) {
+		transactionCtx := startSpan(WithParent(context.Background(), sessionCtx), "transaction")
+
+		ctx := WithParent(transactionCtx, sessionCtx)
+
+		testutil.AssertTrue(t, ctx == transactionCtx)
+		testutil.AssertDeepEquals(t, ctx.Value(key("span")), "transaction")
+		queryCtx := WithParent(context.Background(), transactionCtx)
+		testutil.AssertDeepEquals(t, queryCtx.Value(key("span")), "transaction")
+	})
+
+	outer.Run("leaves contexts as is without parent", func(t *testing.T) {
+		ctx := context.Background()
+
+		testutil.AssertTrue(t, WithParent(ctx, nil) == ctx)
+		testutil.AssertTrue(t, WithParent(ctx, sessionBase) == ctx)
+	})
+}
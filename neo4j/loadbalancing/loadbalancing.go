@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package loadbalancing defines how the driver picks a server among the candidates able to serve a connection
+// acquisition, e.g. the readers of a database. See config.Config's LoadBalancer and Locality.
+package loadbalancing
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// LoadBalancer orders the servers a connection is about to be acquired from.
+// The connection pool tries the servers in the returned order, leaving out the servers that are not returned.
+//
+// Order is called for every connection acquisition, sometimes concurrently, and must therefore be fast and safe for
+// concurrent use. It must not modify the given slice.
+type LoadBalancer interface {
+	Order(servers []Server) []string
+}
+
+// LoadBalancerFunc adapts a function to the LoadBalancer interface.
+type LoadBalancerFunc func(servers []Server) []string
+
+func (f LoadBalancerFunc) Order(servers []Server) []string {
+	return f(servers)
+}
+
+// Server is a candidate server along with the statistics the connection pool keeps about it.
+type Server struct {
+	// Address is the address of the server (host:port)
+	Address string
+	// Idle is the number of connections to the server available in the pool
+	Idle int
+	// InUse is the number of connections to the server currently borrowed from the pool
+	InUse int
+	// Creating is the number of connections to the server currently being established
+	Creating int
+	// MaxSize is the maximum number of connections to the server
+	MaxSize int
+	// FailedRecently tells whether establishing a connection to the server recently failed
+	FailedRecently bool
+	// ConnectionTime is the exponentially weighted moving average of the time it took to establish connections to
+	// the server, including TCP and TLS handshakes and authentication. Zero until a connection is established.
+	ConnectionTime time.Duration
+	// RoundTripTime is the exponentially weighted moving average of the round-trip times observed with the server
+	// when resetting connections. Zero until a round trip is observed.
+	RoundTripTime time.Duration
+}
+
+// Full tells whether no connection can be acquired from the server without waiting.
+func (s Server) Full() bool {
+	return s.Idle == 0 && s.Idle+s.InUse+s.Creating >= s.MaxSize
+}
+
+// RoundRobin returns a LoadBalancer rotating over the servers, regardless of their load.
+// Servers that recently failed are tried last.
+func RoundRobin() LoadBalancer {
+	var counter uint32
+	return LoadBalancerFunc(func(servers []Server) []string {
+		if len(servers) == 0 {
+			return nil
+		}
+		offset := int(atomic.AddUint32(&counter, 1) % uint32(len(servers)))
+		rotated := make([]Server, 0, len(servers))
+		rotated = append(rotated, servers[offset:]...)
+		rotated = append(rotated, servers[:offset]...)
+		return orderBy(rotated, func(Server, Server) bool { return false })
+	})
+}
+
+// LeastInFlight returns a LoadBalancer preferring the servers with the fewest connections in use (or being
+// established), then the servers with idle connections.
+// Servers that recently failed are tried last.
+func LeastInFlight() LoadBalancer {
+	return LoadBalancerFunc(func(servers []Server) []string {
+		return orderBy(servers, func(s1, s2 Server) bool {
+			inFlight1, inFlight2 := s1.InUse+s1.Creating, s2.InUse+s2.Creating
+			if inFlight1 != inFlight2 {
+				return inFlight1 < inFlight2
+			}
+			return s1.Idle > s2.Idle
+		})
+	})
+}
+
+// LowestLatency returns a LoadBalancer preferring the servers with the lowest round-trip time, falling back to the
+// connection time of servers whose round-trip time is not known yet. Servers without any latency observation are
+// tried first, so that their latency gets known. Full servers are tried after the others.
+// Servers that recently failed are tried last.
+func LowestLatency() LoadBalancer {
+	latencyOf := func(server Server) time.Duration {
+		if server.RoundTripTime > 0 {
+			return server.RoundTripTime
+		}
+		return server.ConnectionTime
+	}
+	return LoadBalancerFunc(func(servers []Server) []string {
+		return orderBy(servers, func(s1, s2 Server) bool {
+			if s1.Full() != s2.Full() {
+				return !s1.Full()
+			}
+			return latencyOf(s1) < latencyOf(s2)
+		})
+	})
+}
+
+// orderBy sorts the servers that did not fail recently with less, then appends the servers that did.
+// The order of equal servers is preserved.
+func orderBy(servers []Server, less func(s1, s2 Server) bool) []string {
+	sorted := make([]Server, len(servers))
+	copy(sorted, servers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].FailedRecently != sorted[j].FailedRecently {
+			return !sorted[i].FailedRecently
+		}
+		return less(sorted[i], sorted[j])
+	})
+	addresses := make([]string, len(sorted))
+	for i, server := range sorted {
+		addresses[i] = server.Address
+	}
+	return addresses
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalancing
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRoundRobin(t *testing.T) {
+	servers := []Server{{Address: "a"}, {Address: "b", FailedRecently: true}, {Address: "c"}}
+	loadBalancer := RoundRobin()
+
+	orders := [][]string{loadBalancer.Order(servers), loadBalancer.Order(servers), loadBalancer.Order(servers)}
+
+	expected := [][]string{{"c", "a", "b"}, {"c", "a", "b"}, {"a", "c", "b"}}
+	if !reflect.DeepEqual(orders, expected) {
+		t.Errorf("expected %v, got %v", expected, orders)
+	}
+	if order := loadBalancer.Order(nil); len(order) != 0 {
+		t.Errorf("expected no server, got %v", order)
+	}
+}
+
+func TestLeastInFlight(t *testing.T) {
+	servers := []Server{
+		{Address: "busy", InUse: 3},
+		{Address: "connecting", InUse: 1, Creating: 1},
+		{Address: "failed", FailedRecently: true},
+		{Address: "quiet", InUse: 1},
+		{Address: "quiet with idle", InUse: 1, Idle: 2},
+	}
+
+	order := LeastInFlight().Order(servers)
+
+	expected := []string{"quiet with idle", "quiet", "connecting", "busy", "failed"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v, got %v", expected, order)
+	}
+}
+
+func TestLowestLatency(t *testing.T) {
+	servers := []Server{
+		{Address: "slow", RoundTripTime: 20 * time.Millisecond, MaxSize: 10},
+		{Address: "full", RoundTripTime: 1 * time.Millisecond, InUse: 10, MaxSize: 10},
+		{Address: "fast", RoundTripTime: 2 * time.Millisecond, ConnectionTime: 50 * time.Millisecond, MaxSize: 10},
+		{Address: "failed", FailedRecently: true, MaxSize: 10},
+		{Address: "connected only", ConnectionTime: 10 * time.Millisecond, MaxSize: 10},
+		{Address: "unknown", MaxSize: 10},
+	}
+
+	order := LowestLatency().Order(servers)
+
+	expected := []string{"unknown", "fast", "connected only", "slow", "full", "failed"}
+	if !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected %v, got %v", expected, order)
+	}
+}
+
+func TestOrderDoesNotModifyServers(t *testing.T) {
+	servers := []Server{{Address: "a", InUse: 2}, {Address: "b"}}
+
+	LeastInFlight().Order(servers)
+
+	if servers[0].Address != "a" || servers[1].Address != "b" {
+		t.Errorf("expected servers to be left untouched, got %v", servers)
+	}
+}
+
+func TestLocality(t *testing.T) {
+	locality := &Locality{
+		LocalZone: "eu-west-1a",
+		Zones:     map[string]string{"a:7687": "eu-west-1a", "b:7687": "eu-west-1b"},
+		ZoneOf: func(address string) string {
+			if address == "c:7687" {
+				return "eu-west-1a"
+			}
+			return ""
+		},
+	}
+
+	zones := []string{locality.Zone("a:7687"), locality.Zone("b:7687"), locality.Zone("c:7687"), locality.Zone("d:7687")}
+	local := []bool{locality.IsLocal("a:7687"), locality.IsLocal("b:7687"), locality.IsLocal("c:7687"), locality.IsLocal("d:7687")}
+
+	expectedZones := []string{"eu-west-1a", "eu-west-1b", "eu-west-1a", ""}
+	if !reflect.DeepEqual(zones, expectedZones) {
+		t.Errorf("expected %v, got %v", expectedZones, zones)
+	}
+	expectedLocal := []bool{true, false, true, false}
+	if !reflect.DeepEqual(local, expectedLocal) {
+		t.Errorf("expected %v, got %v", expectedLocal, local)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package loadbalancing
+
+// Locality tells the driver which zone, e.g. availability zone, every server and the application are in, so that
+// connections are acquired from the servers in the same zone as the application rather than from remote servers.
+// See config.Config's Locality.
+type Locality struct {
+	// LocalZone is the zone the application runs in
+	LocalZone string
+	// Zones maps server addresses (host:port) to zones
+	Zones map[string]string
+	// ZoneOf returns the zone of the server with the given address (host:port), or an empty string if it is not
+	// known. It is called for the servers missing from Zones, for every connection acquisition, sometimes
+	// concurrently, and must therefore be fast and safe for concurrent use.
+	ZoneOf func(address string) string
+	// SpillOver decides when connections are acquired from servers outside the local zone
+	SpillOver SpillOver
+}
+
+// SpillOver decides when connections are acquired from servers outside the local zone.
+// Servers outside the local zone are always used when none of the candidate servers is in the local zone.
+type SpillOver int
+
+const (
+	// SpillOverWhenUnavailable tries the servers of the local zone first, then the other servers, whenever no
+	// connection can be acquired from the local zone without waiting, e.g. because its servers are all full or fail.
+	SpillOverWhenUnavailable SpillOver = iota
+	// SpillOverWhenUnhealthy only acquires connections from the servers of the local zone, waiting for connections
+	// to be returned when they are full, unless they all recently failed to accept a connection or have their
+	// circuit breaker open.
+	SpillOverWhenUnhealthy
+	// SpillOverNever only acquires connections from the servers of the local zone.
+	SpillOverNever
+)
+
+// Zone returns the zone of the server with the given address, or an empty string if it is not known.
+func (l *Locality) Zone(address string) string {
+	if zone, found := l.Zones[address]; found {
+		return zone
+	}
+	if l.ZoneOf != nil {
+		return l.ZoneOf(address)
+	}
+	return ""
+}
+
+// IsLocal tells whether the server with the given address is in the local zone.
+func (l *Locality) IsLocal(address string) bool {
+	return l.Zone(address) == l.LocalZone
+}
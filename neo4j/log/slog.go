@@ -0,0 +1,162 @@
+//go:build go1.21
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Attribute keys of the records emitted by the loggers returned by ToSlog and BoltToSlog.
+const (
+	SlogComponentKey     = "component"
+	SlogInstanceIdKey    = "instance_id"
+	SlogConnectionIdKey  = "connection_id"
+	SlogServerAddressKey = "server_address"
+	SlogMessageTypeKey   = "message_type"
+	SlogDirectionKey     = "direction"
+	SlogErrorKey         = "error"
+)
+
+// SlogOption configures the Logger returned by ToSlog.
+type SlogOption func(*slogLogger)
+
+// WithSlogLevel sets the minimum level of the records emitted for all components.
+// By default, all records are passed to the slog.Logger, which applies its own filtering.
+func WithSlogLevel(level Level) SlogOption {
+	return func(l *slogLogger) {
+		l.level = level
+	}
+}
+
+// WithSlogComponentLevel overrides the minimum level of the records emitted for the given component, such as
+// Router or Pool.
+//
+// For example, the following only logs debug records of the router, given that the slog.Logger handler is enabled
+// for debug records:
+//
+//	log.ToSlog(logger, log.WithSlogLevel(log.INFO), log.WithSlogComponentLevel(log.Router, log.DEBUG))
+func WithSlogComponentLevel(component string, level Level) SlogOption {
+	return func(l *slogLogger) {
+		l.componentLevels[component] = level
+	}
+}
+
+// ToSlog returns a Logger that emits structured records to the given slog.Logger.
+// Records carry the component name and instance id as attributes, as well as the connection id and server address
+// when the component is a connection.
+func ToSlog(logger *slog.Logger, options ...SlogOption) Logger {
+	result := &slogLogger{
+		delegate:        logger,
+		level:           DEBUG,
+		componentLevels: make(map[string]Level),
+	}
+	for _, option := range options {
+		option(result)
+	}
+	return result
+}
+
+type slogLogger struct {
+	delegate        *slog.Logger
+	level           Level
+	componentLevels map[string]Level
+}
+
+func (l *slogLogger) Error(name, id string, err error) {
+	if !l.enabled(name, ERROR, slog.LevelError) {
+		return
+	}
+	attrs := append(componentAttrs(name, id), slog.Any(SlogErrorKey, err))
+	l.delegate.LogAttrs(context.Background(), slog.LevelError, err.Error(), attrs...)
+}
+
+func (l *slogLogger) Warnf(name, id string, msg string, args ...any) {
+	l.logf(name, id, WARNING, slog.LevelWarn, msg, args)
+}
+
+func (l *slogLogger) Infof(name, id string, msg string, args ...any) {
+	l.logf(name, id, INFO, slog.LevelInfo, msg, args)
+}
+
+func (l *slogLogger) Debugf(name, id string, msg string, args ...any) {
+	l.logf(name, id, DEBUG, slog.LevelDebug, msg, args)
+}
+
+func (l *slogLogger) logf(name, id string, level Level, slogLevel slog.Level, msg string, args []any) {
+	if !l.enabled(name, level, slogLevel) {
+		return
+	}
+	l.delegate.LogAttrs(context.Background(), slogLevel, fmt.Sprintf(msg, args...), componentAttrs(name, id)...)
+}
+
+func (l *slogLogger) enabled(name string, level Level, slogLevel slog.Level) bool {
+	minLevel, found := l.componentLevels[name]
+	if !found {
+		minLevel = l.level
+	}
+	return level <= minLevel && l.delegate.Enabled(context.Background(), slogLevel)
+}
+
+func componentAttrs(name, id string) []slog.Attr {
+	attrs := []slog.Attr{slog.String(SlogComponentKey, name), slog.String(SlogInstanceIdKey, id)}
+	if connectionId, serverAddress, ok := strings.Cut(id, "@"); ok {
+		attrs = append(attrs, slog.String(SlogConnectionIdKey, connectionId), slog.String(SlogServerAddressKey, serverAddress))
+	}
+	return attrs
+}
+
+// BoltToSlog returns a BoltLogger that emits every Bolt message as a debug record to the given slog.Logger.
+// Records carry the message type (such as RUN or PULL), its direction ("client" or "server"), as well as the
+// connection id and server address as attributes.
+func BoltToSlog(logger *slog.Logger) BoltLogger {
+	return &slogBoltLogger{delegate: logger}
+}
+
+type slogBoltLogger struct {
+	delegate *slog.Logger
+}
+
+func (l *slogBoltLogger) LogClientMessage(id, msg string, args ...any) {
+	l.log("client", id, msg, args)
+}
+
+func (l *slogBoltLogger) LogServerMessage(id, msg string, args ...any) {
+	l.log("server", id, msg, args)
+}
+
+func (l *slogBoltLogger) log(direction, id, msg string, args []any) {
+	if !l.delegate.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	messageType, _, _ := strings.Cut(msg, " ")
+	attrs := []slog.Attr{
+		slog.String(SlogComponentKey, "bolt"),
+		slog.String(SlogDirectionKey, direction),
+		slog.String(SlogMessageTypeKey, messageType),
+	}
+	// the id is empty during the handshake
+	if connectionId, serverAddress, ok := strings.Cut(id, "@"); ok {
+		attrs = append(attrs, slog.String(SlogConnectionIdKey, connectionId), slog.String(SlogServerAddressKey, serverAddress))
+	}
+	l.delegate.LogAttrs(context.Background(), slog.LevelDebug, fmt.Sprintf(msg, args...), attrs...)
+}
@@ -0,0 +1,122 @@
+//go:build go1.21
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package log
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+type recordingHandler struct {
+	level   slog.Level
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	h.records = append(h.records, record)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler {
+	return h
+}
+
+func (h *recordingHandler) WithGroup(string) slog.Handler {
+	return h
+}
+
+func attrsOf(record slog.Record) map[string]string {
+	attrs := make(map[string]string)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs[attr.Key] = attr.Value.String()
+		return true
+	})
+	return attrs
+}
+
+func TestToSlog(outer *testing.T) {
+	outer.Run("emits records with component attributes", func(t *testing.T) {
+		handler := &recordingHandler{level: slog.LevelDebug}
+		logger := ToSlog(slog.New(handler))
+
+		logger.Infof(Bolt5, "bolt-1@localhost:7687", "connected to %s", "localhost")
+		logger.Error(Pool, "2", errors.New("oopsie"))
+
+		if len(handler.records) != 2 {
+			t.Fatalf("expected 2 records, got %d", len(handler.records))
+		}
+		info := handler.records[0]
+		if info.Level != slog.LevelInfo || info.Message != "connected to localhost" {
+			t.Errorf("unexpected record %v", info)
+		}
+		attrs := attrsOf(info)
+		if attrs[SlogComponentKey] != Bolt5 || attrs[SlogConnectionIdKey] != "bolt-1" || attrs[SlogServerAddressKey] != "localhost:7687" {
+			t.Errorf("unexpected attributes %v", attrs)
+		}
+		failure := handler.records[1]
+		if failure.Level != slog.LevelError || attrsOf(failure)[SlogErrorKey] != "oopsie" {
+			t.Errorf("unexpected record %v", failure)
+		}
+	})
+
+	outer.Run("filters records by component level", func(t *testing.T) {
+		handler := &recordingHandler{level: slog.LevelDebug}
+		logger := ToSlog(slog.New(handler), WithSlogLevel(WARNING), WithSlogComponentLevel(Router, DEBUG))
+
+		logger.Debugf(Pool, "1", "dropped")
+		logger.Debugf(Router, "1", "kept")
+
+		if len(handler.records) != 1 || handler.records[0].Message != "kept" {
+			t.Errorf("expected only the router record, got %v", handler.records)
+		}
+	})
+
+	outer.Run("honours the handler level", func(t *testing.T) {
+		handler := &recordingHandler{level: slog.LevelWarn}
+		logger := ToSlog(slog.New(handler))
+
+		logger.Infof(Pool, "1", "dropped")
+
+		if len(handler.records) != 0 {
+			t.Errorf("expected no record, got %v", handler.records)
+		}
+	})
+}
+
+func TestBoltToSlog(t *testing.T) {
+	handler := &recordingHandler{level: slog.LevelDebug}
+	logger := BoltToSlog(slog.New(handler))
+
+	logger.LogClientMessage("bolt-1@localhost:7687", "RUN %q", "RETURN 1")
+
+	if len(handler.records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(handler.records))
+	}
+	attrs := attrsOf(handler.records[0])
+	if attrs[SlogMessageTypeKey] != "RUN" || attrs[SlogDirectionKey] != "client" || attrs[SlogConnectionIdKey] != "bolt-1" {
+		t.Errorf("unexpected attributes %v", attrs)
+	}
+}
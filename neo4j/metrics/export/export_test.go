@@ -0,0 +1,159 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package export
+
+import (
+	"encoding/json"
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type fakeDriver struct {
+	neo4j.DriverWithContext
+	metrics metrics.DriverMetrics
+	err     error
+}
+
+func (d *fakeDriver) Metrics() (metrics.DriverMetrics, error) {
+	return d.metrics, d.err
+}
+
+func histogramOf(durations ...time.Duration) metrics.Histogram {
+	histogram := metrics.Histogram{
+		Bounds: []time.Duration{10 * time.Millisecond, 1 * time.Second},
+		Counts: make([]uint64, 2),
+	}
+	for _, duration := range durations {
+		for i, bound := range histogram.Bounds {
+			if duration <= bound {
+				histogram.Counts[i]++
+			}
+		}
+		histogram.Count++
+		histogram.Sum += duration
+	}
+	return histogram
+}
+
+var driverMetrics = metrics.DriverMetrics{
+	LogId: "d1",
+	Pool: metrics.PoolMetrics{
+		Servers: map[string]metrics.ServerMetrics{
+			"server1:7687": {Idle: 2, InUse: 1, Created: 4, Closed: 1, CircuitState: metrics.CircuitOpen, CircuitOpenings: 2},
+		},
+		Waiting:         3,
+		Acquired:        10,
+		Timeouts:        1,
+		AcquisitionTime: histogramOf(5*time.Millisecond, 500*time.Millisecond),
+	},
+	RoutingTables: map[string]metrics.RoutingTableMetrics{
+		"movies": {Refreshes: 2, FailedRefreshes: 1, RefreshTime: histogramOf(20 * time.Millisecond)},
+	},
+	Transactions: map[string]metrics.TransactionMetrics{
+		"movies": {Executions: 5, Retries: 2, Failures: 1},
+	},
+	Queries: map[metrics.QueryKey]metrics.QueryMetrics{
+		{API: metrics.ExecuteQueryAPI, Database: "movies"}: {Succeeded: 7, Failed: 1, Latency: histogramOf(2 * time.Millisecond)},
+	},
+}
+
+func TestCollector(outer *testing.T) {
+	outer.Parallel()
+
+	scrape := func(t *testing.T, drivers ...neo4j.DriverWithContext) string {
+		registry := prometheus.NewPedanticRegistry()
+		registry.MustRegister(NewCollector(drivers...))
+		server := httptest.NewServer(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		defer server.Close()
+		response, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+
+	outer.Run("exposes driver metrics", func(t *testing.T) {
+		body := scrape(t, &fakeDriver{metrics: driverMetrics})
+
+		for _, line := range []string{
+			`neo4j_driver_pool_connections{driver_id="d1",server="server1:7687",state="idle"} 2`,
+			`neo4j_driver_pool_connections{driver_id="d1",server="server1:7687",state="in_use"} 1`,
+			`neo4j_driver_pool_connections_created_total{driver_id="d1",server="server1:7687"} 4`,
+			`neo4j_driver_pool_connections_closed_total{driver_id="d1",server="server1:7687"} 1`,
+			`neo4j_driver_pool_circuit_breaker_state{driver_id="d1",server="server1:7687",state="open"} 1`,
+			`neo4j_driver_pool_circuit_breaker_state{driver_id="d1",server="server1:7687",state="closed"} 0`,
+			`neo4j_driver_pool_circuit_breaker_openings_total{driver_id="d1",server="server1:7687"} 2`,
+			`neo4j_driver_pool_acquisitions_waiting{driver_id="d1"} 3`,
+			`neo4j_driver_pool_acquisitions_total{driver_id="d1",outcome="timeout"} 1`,
+			`neo4j_driver_pool_acquisition_duration_seconds_bucket{driver_id="d1",le="0.01"} 1`,
+			`neo4j_driver_pool_acquisition_duration_seconds_bucket{driver_id="d1",le="1"} 2`,
+			`neo4j_driver_pool_acquisition_duration_seconds_count{driver_id="d1"} 2`,
+			`neo4j_driver_routing_table_refreshes_total{database="movies",driver_id="d1",outcome="failure"} 1`,
+			`neo4j_driver_transaction_function_retries_total{database="movies",driver_id="d1"} 2`,
+			`neo4j_driver_queries_total{api="execute_query",database="movies",driver_id="d1",outcome="success"} 7`,
+			`neo4j_driver_query_duration_seconds_count{api="execute_query",database="movies",driver_id="d1"} 1`,
+		} {
+			if !strings.Contains(body, line+"\n") {
+				t.Errorf("expected scrape to contain %q, got:\n%s", line, body)
+			}
+		}
+	})
+
+	outer.Run("skips closed drivers", func(t *testing.T) {
+		body := scrape(t, &fakeDriver{err: &neo4j.UsageError{Message: "closed"}})
+
+		if strings.Contains(body, "neo4j_driver") {
+			t.Errorf("expected no driver metrics, got:\n%s", body)
+		}
+	})
+}
+
+func TestPublishExpvar(t *testing.T) {
+	PublishExpvar("neo4j_test_driver", &fakeDriver{metrics: driverMetrics})
+
+	var published struct {
+		LogId   string
+		Pool    struct{ Waiting int }
+		Queries map[string]map[string]struct{ Succeeded int64 }
+	}
+	if err := json.Unmarshal([]byte(expvar.Get("neo4j_test_driver").String()), &published); err != nil {
+		t.Fatal(err)
+	}
+
+	if published.LogId != "d1" || published.Pool.Waiting != 3 {
+		t.Errorf("unexpected published metrics %+v", published)
+	}
+	if succeeded := published.Queries["execute_query"]["movies"].Succeeded; succeeded != 7 {
+		t.Errorf("expected 7 successful queries, got %d", succeeded)
+	}
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package export
+
+import (
+	"expvar"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+)
+
+// PublishExpvar publishes the metrics of the given driver as an expvar variable of the given name.
+// The variable is computed every time it is read, e.g. when the /debug/vars endpoint is queried. It is null once
+// the driver is closed.
+//
+// Like expvar.Publish, PublishExpvar panics if a variable of the same name is already published.
+func PublishExpvar(name string, driver neo4j.DriverWithContext) {
+	expvar.Publish(name, expvar.Func(func() any {
+		driverMetrics, err := driver.Metrics()
+		if err != nil {
+			return nil
+		}
+		return toExpvar(driverMetrics)
+	}))
+}
+
+// expvarMetrics is the JSON friendly form of metrics.DriverMetrics.
+// Queries are keyed by API, then by database.
+type expvarMetrics struct {
+	LogId         string
+	Pool          metrics.PoolMetrics
+	RoutingTables map[string]metrics.RoutingTableMetrics
+	Transactions  map[string]metrics.TransactionMetrics
+	Queries       map[metrics.API]map[string]metrics.QueryMetrics
+}
+
+func toExpvar(driverMetrics metrics.DriverMetrics) expvarMetrics {
+	queries := make(map[metrics.API]map[string]metrics.QueryMetrics)
+	for key, queryMetrics := range driverMetrics.Queries {
+		byDatabase := queries[key.API]
+		if byDatabase == nil {
+			byDatabase = make(map[string]metrics.QueryMetrics)
+			queries[key.API] = byDatabase
+		}
+		byDatabase[key.Database] = queryMetrics
+	}
+	return expvarMetrics{
+		LogId:         driverMetrics.LogId,
+		Pool:          driverMetrics.Pool,
+		RoutingTables: driverMetrics.RoutingTables,
+		Transactions:  driverMetrics.Transactions,
+		Queries:       queries,
+	}
+}
@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package export exposes driver metrics (see neo4j.DriverWithContext Metrics) to monitoring systems, as Prometheus
+// collectors and expvar variables.
+//
+// Every series is labelled with the log ID of the driver (driver_id), so that several drivers can be exported by
+// the same process, as well as with the server address (server) or the database name (database) it relates to.
+//
+// The package is a separate module, so that applications not exporting metrics do not depend on the Prometheus
+// client. It is released together with the driver and builds against the driver sources of the same revision.
+package export
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "neo4j_driver"
+
+var (
+	poolConnectionsDesc = prometheus.NewDesc(namespace+"_pool_connections",
+		"Number of connections in the pool, by state (idle, in_use or creating).",
+		[]string{"driver_id", "server", "state"}, nil)
+	poolConnectionsCreatedDesc = prometheus.NewDesc(namespace+"_pool_connections_created_total",
+		"Number of connections successfully established.",
+		[]string{"driver_id", "server"}, nil)
+	poolConnectionsFailedDesc = prometheus.NewDesc(namespace+"_pool_connections_failed_total",
+		"Number of connection attempts that failed.",
+		[]string{"driver_id", "server"}, nil)
+	poolConnectionsClosedDesc = prometheus.NewDesc(namespace+"_pool_connections_closed_total",
+		"Number of connections closed by the pool.",
+		[]string{"driver_id", "server"}, nil)
+	poolCircuitStateDesc = prometheus.NewDesc(namespace+"_pool_circuit_breaker_state",
+		"State of the circuit breaker of the server, 1 for the current state (closed, open or half_open) and 0 for the others.",
+		[]string{"driver_id", "server", "state"}, nil)
+	poolCircuitOpeningsDesc = prometheus.NewDesc(namespace+"_pool_circuit_breaker_openings_total",
+		"Number of times the circuit breaker of the server opened.",
+		[]string{"driver_id", "server"}, nil)
+	poolWaitingDesc = prometheus.NewDesc(namespace+"_pool_acquisitions_waiting",
+		"Number of connection acquisitions waiting for a connection to be returned to the pool.",
+		[]string{"driver_id"}, nil)
+	poolAcquisitionsDesc = prometheus.NewDesc(namespace+"_pool_acquisitions_total",
+		"Number of connection acquisitions, by outcome (success, timeout or rejected).",
+		[]string{"driver_id", "outcome"}, nil)
+	poolAcquisitionDurationDesc = prometheus.NewDesc(namespace+"_pool_acquisition_duration_seconds",
+		"Duration of successful connection acquisitions.",
+		[]string{"driver_id"}, nil)
+	routingRefreshesDesc = prometheus.NewDesc(namespace+"_routing_table_refreshes_total",
+		"Number of routing table refreshes, by outcome (success or failure).",
+		[]string{"driver_id", "database", "outcome"}, nil)
+	routingRefreshDurationDesc = prometheus.NewDesc(namespace+"_routing_table_refresh_duration_seconds",
+		"Duration of successful routing table refreshes.",
+		[]string{"driver_id", "database"}, nil)
+	transactionExecutionsDesc = prometheus.NewDesc(namespace+"_transaction_function_executions_total",
+		"Number of transaction function executions.",
+		[]string{"driver_id", "database"}, nil)
+	transactionRetriesDesc = prometheus.NewDesc(namespace+"_transaction_function_retries_total",
+		"Number of transaction function attempts after the first one.",
+		[]string{"driver_id", "database"}, nil)
+	transactionFailuresDesc = prometheus.NewDesc(namespace+"_transaction_function_failures_total",
+		"Number of transaction function executions that failed, even after retrying.",
+		[]string{"driver_id", "database"}, nil)
+	queriesDesc = prometheus.NewDesc(namespace+"_queries_total",
+		"Number of queries, by API and outcome (success or failure).",
+		[]string{"driver_id", "database", "api", "outcome"}, nil)
+	queryDurationDesc = prometheus.NewDesc(namespace+"_query_duration_seconds",
+		"Duration of successful queries, from the moment they are sent until their summary is received.",
+		[]string{"driver_id", "database", "api"}, nil)
+)
+
+// NewCollector returns a Prometheus collector of the metrics of the given drivers.
+// Metrics are retrieved upon every collection. Closed drivers are skipped.
+func NewCollector(drivers ...neo4j.DriverWithContext) prometheus.Collector {
+	return &collector{drivers: drivers}
+}
+
+type collector struct {
+	drivers []neo4j.DriverWithContext
+}
+
+func (c *collector) Describe(descs chan<- *prometheus.Desc) {
+	descs <- poolConnectionsDesc
+	descs <- poolConnectionsCreatedDesc
+	descs <- poolConnectionsFailedDesc
+	descs <- poolConnectionsClosedDesc
+	descs <- poolCircuitStateDesc
+	descs <- poolCircuitOpeningsDesc
+	descs <- poolWaitingDesc
+	descs <- poolAcquisitionsDesc
+	descs <- poolAcquisitionDurationDesc
+	descs <- routingRefreshesDesc
+	descs <- routingRefreshDurationDesc
+	descs <- transactionExecutionsDesc
+	descs <- transactionRetriesDesc
+	descs <- transactionFailuresDesc
+	descs <- queriesDesc
+	descs <- queryDurationDesc
+}
+
+func (c *collector) Collect(values chan<- prometheus.Metric) {
+	for _, driver := range c.drivers {
+		driverMetrics, err := driver.Metrics()
+		if err != nil {
+			continue
+		}
+		collectDriver(values, driverMetrics)
+	}
+}
+
+func collectDriver(values chan<- prometheus.Metric, driverMetrics metrics.DriverMetrics) {
+	id := driverMetrics.LogId
+	gauge := func(desc *prometheus.Desc, value int, labels ...string) {
+		values <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(value), labels...)
+	}
+	counter := func(desc *prometheus.Desc, value int64, labels ...string) {
+		values <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(value), labels...)
+	}
+	histogram := func(desc *prometheus.Desc, histogram metrics.Histogram, labels ...string) {
+		values <- prometheus.MustNewConstHistogram(desc, histogram.Count, histogram.Sum.Seconds(), buckets(histogram), labels...)
+	}
+
+	pool := driverMetrics.Pool
+	for server, serverMetrics := range pool.Servers {
+		gauge(poolConnectionsDesc, serverMetrics.Idle, id, server, "idle")
+		gauge(poolConnectionsDesc, serverMetrics.InUse, id, server, "in_use")
+		gauge(poolConnectionsDesc, serverMetrics.Creating, id, server, "creating")
+		counter(poolConnectionsCreatedDesc, serverMetrics.Created, id, server)
+		counter(poolConnectionsFailedDesc, serverMetrics.FailedToCreate, id, server)
+		counter(poolConnectionsClosedDesc, serverMetrics.Closed, id, server)
+		for _, state := range []metrics.CircuitState{metrics.CircuitClosed, metrics.CircuitOpen, metrics.CircuitHalfOpen} {
+			current := 0
+			if serverMetrics.CircuitState == state {
+				current = 1
+			}
+			gauge(poolCircuitStateDesc, current, id, server, string(state))
+		}
+		counter(poolCircuitOpeningsDesc, serverMetrics.CircuitOpenings, id, server)
+	}
+	gauge(poolWaitingDesc, pool.Waiting, id)
+	counter(poolAcquisitionsDesc, pool.Acquired, id, "success")
+	counter(poolAcquisitionsDesc, pool.Timeouts, id, "timeout")
+	counter(poolAcquisitionsDesc, pool.Rejected, id, "rejected")
+	histogram(poolAcquisitionDurationDesc, pool.AcquisitionTime, id)
+
+	for database, routingMetrics := range driverMetrics.RoutingTables {
+		counter(routingRefreshesDesc, routingMetrics.Refreshes, id, database, "success")
+		counter(routingRefreshesDesc, routingMetrics.FailedRefreshes, id, database, "failure")
+		histogram(routingRefreshDurationDesc, routingMetrics.RefreshTime, id, database)
+	}
+
+	for database, transactionMetrics := range driverMetrics.Transactions {
+		counter(transactionExecutionsDesc, transactionMetrics.Executions, id, database)
+		counter(transactionRetriesDesc, transactionMetrics.Retries, id, database)
+		counter(transactionFailuresDesc, transactionMetrics.Failures, id, database)
+	}
+
+	for key, queryMetrics := range driverMetrics.Queries {
+		api := string(key.API)
+		counter(queriesDesc, queryMetrics.Succeeded, id, key.Database, api, "success")
+		counter(queriesDesc, queryMetrics.Failed, id, key.Database, api, "failure")
+		histogram(queryDurationDesc, queryMetrics.Latency, id, key.Database, api)
+	}
+}
+
+func buckets(histogram metrics.Histogram) map[float64]uint64 {
+	result := make(map[float64]uint64, len(histogram.Bounds))
+	for i, bound := range histogram.Bounds {
+		if i < len(histogram.Counts) {
+			result[bound.Seconds()] = histogram.Counts[i]
+		}
+	}
+	return result
+}
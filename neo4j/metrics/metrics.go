@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package metrics defines the statistics the driver exposes about its connection pool, routing tables,
+// transactions and queries.
+package metrics
+
+import "time"
+
+// DriverMetrics is a snapshot of the statistics of a driver.
+// Counters are cumulative since the creation of the driver.
+type DriverMetrics struct {
+	// LogId identifies the driver in the logs.
+	LogId string
+	// Pool holds the connection pool metrics.
+	Pool PoolMetrics
+	// RoutingTables holds the routing table metrics, keyed by database name.
+	// The empty database name denotes the resolution of home databases.
+	// It is always empty for drivers that do not route (bolt:// URI scheme).
+	RoutingTables map[string]RoutingTableMetrics
+	// Transactions holds the transaction function metrics, keyed by database name.
+	// The empty database name denotes transactions run against the home database, before it has been resolved.
+	Transactions map[string]TransactionMetrics
+	// Queries holds the query metrics, keyed by API and database name.
+	Queries map[QueryKey]QueryMetrics
+}
+
+// RoutingTableMetrics holds the routing table metrics of a single database.
+type RoutingTableMetrics struct {
+	// Refreshes is the number of successful routing table refreshes.
+	Refreshes int64
+	// FailedRefreshes is the number of routing table refreshes that failed.
+	FailedRefreshes int64
+	// LastRefresh is the time of the last successful refresh, zero if none.
+	LastRefresh time.Time
+	// RefreshTime is the distribution of the duration of successful refreshes.
+	RefreshTime Histogram
+}
+
+// TransactionMetrics holds the transaction function metrics of a single database.
+type TransactionMetrics struct {
+	// Executions is the number of transaction function executions, i.e. calls to SessionWithContext's
+	// ExecuteRead and ExecuteWrite, as well as to neo4j.ExecuteQuery.
+	Executions int64
+	// Retries is the number of additional attempts, after the first one, of transaction functions.
+	Retries int64
+	// Failures is the number of transaction function executions that failed, even after retrying.
+	Failures int64
+}
+
+// API identifies the driver API a query ran with.
+type API string
+
+const (
+	// ManagedTransactionAPI identifies queries run in transaction functions.
+	ManagedTransactionAPI API = "managed_transaction"
+	// UnmanagedTransactionAPI identifies queries run in explicit transactions.
+	UnmanagedTransactionAPI API = "unmanaged_transaction"
+	// AutoCommitTransactionAPI identifies queries run with SessionWithContext.Run.
+	AutoCommitTransactionAPI API = "auto_commit_transaction"
+	// ExecuteQueryAPI identifies queries run with neo4j.ExecuteQuery.
+	ExecuteQueryAPI API = "execute_query"
+)
+
+// QueryKey identifies a group of queries in QueryMetrics.
+type QueryKey struct {
+	API      API
+	Database string
+}
+
+// QueryMetrics holds the metrics of a group of queries.
+type QueryMetrics struct {
+	// Succeeded is the number of queries whose summary was received.
+	Succeeded int64
+	// Failed is the number of queries that failed.
+	Failed int64
+	// Latency is the distribution of the duration of successful queries, from the moment they are sent until the
+	// moment their summary is received.
+	Latency Histogram
+}
+
+// PoolMetrics is a snapshot of the state and of the statistics of the connection pool.
+// Counters are cumulative since the creation of the driver.
+type PoolMetrics struct {
+	// Servers holds the metrics of the servers the pool connected to, keyed by server address.
+	// The metrics of a server are dropped once the pool holds no connection to it and no routing table lists it
+	// anymore, unless its circuit breaker is open. Counters start over if the pool connects to the server again.
+	Servers map[string]ServerMetrics
+	// MaxSizePerServer is the maximum number of connections the pool holds per server, as configured with
+	// config.Config MaxConnectionPoolSize.
+	MaxSizePerServer int
+	// Waiting is the number of connection acquisitions currently queued, waiting for a connection to be returned
+	// to the pool.
+	Waiting int
+	// Acquired is the number of successful connection acquisitions.
+	Acquired int64
+	// Timeouts is the number of connection acquisitions that failed because they could not complete within
+	// config.Config ConnectionAcquisitionTimeout (or before their context expired).
+	Timeouts int64
+	// Rejected is the number of connection acquisitions that failed because the pool was full and the
+	// acquisition was not allowed to wait, or because config.Config MaxConnectionAcquisitionQueueSize acquisitions
+	// were already waiting.
+	Rejected int64
+	// AcquisitionTime is the distribution of the duration of successful connection acquisitions.
+	AcquisitionTime Histogram
+}
+
+// ServerMetrics holds the connection pool metrics of a single server.
+type ServerMetrics struct {
+	// Idle is the number of connections currently available in the pool.
+	Idle int
+	// InUse is the number of connections currently borrowed from the pool.
+	InUse int
+	// Creating is the number of connections currently being established.
+	Creating int
+	// MaxSize is the maximum number of connections the pool holds for the server, as configured with
+	// config.Config MaxConnectionPoolSizePerServer or else config.Config MaxConnectionPoolSize.
+	MaxSize int
+	// Created is the number of connections successfully established.
+	Created int64
+	// FailedToCreate is the number of connection attempts that failed.
+	FailedToCreate int64
+	// Closed is the number of connections closed by the pool.
+	Closed int64
+	// LastFailedToCreate is the time of the last failed connection attempt, zero if none.
+	// The pool deprioritizes servers that recently failed to accept a connection.
+	LastFailedToCreate time.Time
+	// CircuitState is the state of the circuit breaker of the server.
+	// It is always CircuitClosed when circuit breakers are disabled, see config.Config CircuitBreakerFailureThreshold.
+	CircuitState CircuitState
+	// CircuitOpenings is the number of times the circuit breaker of the server opened.
+	CircuitOpenings int64
+}
+
+// CircuitState is the state of the circuit breaker of a server, see config.Config CircuitBreakerFailureThreshold.
+type CircuitState string
+
+const (
+	// CircuitClosed means that connections are acquired from the server as usual
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen means that no connection is acquired from the server until the cool-down elapses
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen means that a limited number of connection acquisitions are let through to probe the server
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// Histogram is a distribution of durations.
+type Histogram struct {
+	// Bounds are the upper bounds of the buckets, in increasing order.
+	Bounds []time.Duration
+	// Counts holds, for each bound of the same index, the number of observations lower than or equal to the bound.
+	// Counts are therefore cumulative, the same way as Prometheus buckets are.
+	Counts []uint64
+	// Count is the total number of observations.
+	Count uint64
+	// Sum is the sum of all observations.
+	Sum time.Duration
+}
@@ -125,3 +125,40 @@ func (n *NotificationDisabledCategories) DisabledCategories() []NotificationCate
 func (n *NotificationDisabledClassifications) DisabledClassifications() []NotificationClassification {
 	return n.classifications
 }
+
+// StrictPolicy defines which notifications the driver turns into errors.
+// When a query result summary carries a notification matching either one of the categories or one of the GQLSTATUS
+// codes of the policy, consuming the result fails with a neo4j.NotificationError.
+// Can be used for NotificationsStrictPolicy of config.Config.
+//
+// The policy only applies to notifications the server actually sends, which depends on NotificationsMinSeverity
+// and NotificationsDisabledCategories (or NotificationsDisabledClassifications).
+// Note that servers which are not GQL-aware do not send GQLSTATUS codes: their notifications can only be matched
+// by category.
+type StrictPolicy struct {
+	// Categories lists the categories (also known as classifications) of the notifications that result in errors.
+	Categories []NotificationCategory
+	// GqlStatuses lists the GQLSTATUS codes of the notifications that result in errors, such as "01N50"
+	// (unknown label).
+	GqlStatuses []string
+}
+
+// IsEmpty returns true if the policy does not turn any notification into an error.
+func (p *StrictPolicy) IsEmpty() bool {
+	return len(p.Categories) == 0 && len(p.GqlStatuses) == 0
+}
+
+// Matches returns true if a notification with the given GQLSTATUS code and classification results in an error.
+func (p *StrictPolicy) Matches(gqlStatus string, classification NotificationClassification) bool {
+	for _, status := range p.GqlStatuses {
+		if status == gqlStatus {
+			return true
+		}
+	}
+	for _, category := range p.Categories {
+		if category == classification {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultHotOperatorThreshold is the share of the total database hits above which ProfiledPlanAsDot highlights
+// an operator.
+const DefaultHotOperatorThreshold = 0.2
+
+// PlanAsText renders the plan as a table, in the same fashion as cypher-shell does.
+// The first column lays out the plan tree, the root operator coming first.
+// Returns an empty string if the plan is nil.
+func PlanAsText(plan Plan) string {
+	if plan == nil {
+		return ""
+	}
+	return renderPlanTable(fromPlan(plan))
+}
+
+// ProfiledPlanAsText renders the profiled plan as a table, in the same fashion as cypher-shell does.
+// Alongside the tree of operators, the table includes the produced rows, database hits, page cache statistics
+// and time of each operator, followed by the total of database hits.
+// Returns an empty string if the plan is nil.
+func ProfiledPlanAsText(plan ProfiledPlan) string {
+	if plan == nil {
+		return ""
+	}
+	return renderPlanTable(fromProfiledPlan(plan))
+}
+
+// PlanAsJSON serializes the plan tree to JSON.
+// Returns "null" if the plan is nil.
+func PlanAsJSON(plan Plan) ([]byte, error) {
+	if plan == nil {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(fromPlan(plan))
+}
+
+// ProfiledPlanAsJSON serializes the profiled plan tree, including its statistics, to JSON.
+// Returns "null" if the plan is nil.
+func ProfiledPlanAsJSON(plan ProfiledPlan) ([]byte, error) {
+	if plan == nil {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(fromProfiledPlan(plan))
+}
+
+// PlanAsDot renders the plan as a Graphviz DOT digraph, where edges go from each operator to its children.
+// Returns an empty string if the plan is nil.
+func PlanAsDot(plan Plan) string {
+	if plan == nil {
+		return ""
+	}
+	return renderPlanDot(fromPlan(plan), 0)
+}
+
+// ProfiledPlanAsDot renders the profiled plan as a Graphviz DOT digraph, where edges go from each operator to its
+// children.
+// Operators accounting for at least hotThreshold (a value between 0 and 1) of the total database hits are
+// highlighted. DefaultHotOperatorThreshold is a reasonable starting point.
+// Returns an empty string if the plan is nil.
+func ProfiledPlanAsDot(plan ProfiledPlan, hotThreshold float64) string {
+	if plan == nil {
+		return ""
+	}
+	return renderPlanDot(fromProfiledPlan(plan), hotThreshold)
+}
+
+// planNode is the common representation of Plan and ProfiledPlan used for rendering
+type planNode struct {
+	Operator        string         `json:"operator"`
+	Arguments       map[string]any `json:"arguments,omitempty"`
+	Identifiers     []string       `json:"identifiers,omitempty"`
+	DbHits          *int64         `json:"dbHits,omitempty"`
+	Records         *int64         `json:"rows,omitempty"`
+	PageCacheHits   *int64         `json:"pageCacheHits,omitempty"`
+	PageCacheMisses *int64         `json:"pageCacheMisses,omitempty"`
+	Time            *int64         `json:"time,omitempty"`
+	Children        []*planNode    `json:"children,omitempty"`
+}
+
+func fromPlan(plan Plan) *planNode {
+	children := plan.Children()
+	node := &planNode{
+		Operator:    plan.Operator(),
+		Arguments:   plan.Arguments(),
+		Identifiers: plan.Identifiers(),
+		Children:    make([]*planNode, len(children)),
+	}
+	for i, child := range children {
+		node.Children[i] = fromPlan(child)
+	}
+	return node
+}
+
+func fromProfiledPlan(plan ProfiledPlan) *planNode {
+	dbHits, records := plan.DbHits(), plan.Records()
+	pageCacheHits, pageCacheMisses := plan.PageCacheHits(), plan.PageCacheMisses()
+	elapsed := plan.Time()
+	children := plan.Children()
+	node := &planNode{
+		Operator:        plan.Operator(),
+		Arguments:       plan.Arguments(),
+		Identifiers:     plan.Identifiers(),
+		DbHits:          &dbHits,
+		Records:         &records,
+		PageCacheHits:   &pageCacheHits,
+		PageCacheMisses: &pageCacheMisses,
+		Time:            &elapsed,
+		Children:        make([]*planNode, len(children)),
+	}
+	for i, child := range children {
+		node.Children[i] = fromProfiledPlan(child)
+	}
+	return node
+}
+
+func (n *planNode) profiled() bool {
+	return n.DbHits != nil
+}
+
+// name strips the planner suffix of the operator (e.g. "Filter@neo4j" becomes "Filter")
+func (n *planNode) name() string {
+	if i := strings.LastIndexByte(n.Operator, '@'); i > 0 {
+		return n.Operator[:i]
+	}
+	return n.Operator
+}
+
+func (n *planNode) details() string {
+	if details, ok := n.Arguments["Details"].(string); ok {
+		return details
+	}
+	identifiers := make([]string, len(n.Identifiers))
+	copy(identifiers, n.Identifiers)
+	sort.Strings(identifiers)
+	return strings.Join(identifiers, ", ")
+}
+
+func (n *planNode) estimatedRows() string {
+	switch rows := n.Arguments["EstimatedRows"].(type) {
+	case float64:
+		return strconv.FormatInt(int64(math.Round(rows)), 10)
+	case int64:
+		return strconv.FormatInt(rows, 10)
+	default:
+		return ""
+	}
+}
+
+func (n *planNode) totalDbHits() int64 {
+	total := *n.DbHits
+	for _, child := range n.Children {
+		total += child.totalDbHits()
+	}
+	return total
+}
+
+// walk visits the plan tree depth-first, in the order cypher-shell lists operators.
+// The right-hand side of a branching operator is visited first, one level deeper, then the left-hand side
+// continues at the level of its parent.
+func (n *planNode) walk(level int, visit func(node *planNode, level int)) {
+	visit(n, level)
+	for i := len(n.Children) - 1; i >= 0; i-- {
+		n.Children[i].walk(level+i, visit)
+	}
+}
+
+func renderPlanTable(root *planNode) string {
+	headers := []string{"Operator", "Details", "Estimated Rows"}
+	if root.profiled() {
+		headers = append(headers, "Rows", "DB Hits", "Page Cache Hits/Misses", "Time (ms)")
+	}
+	var rows [][]string
+	root.walk(0, func(node *planNode, level int) {
+		row := []string{
+			strings.Repeat("| ", level) + "+" + node.name(),
+			node.details(),
+			node.estimatedRows(),
+		}
+		if node.profiled() {
+			row = append(row,
+				strconv.FormatInt(*node.Records, 10),
+				strconv.FormatInt(*node.DbHits, 10),
+				fmt.Sprintf("%d/%d", *node.PageCacheHits, *node.PageCacheMisses),
+				strconv.FormatFloat(float64(*node.Time)/1e6, 'f', 3, 64),
+			)
+		}
+		rows = append(rows, row)
+	})
+
+	// widths are counted in runes, as fmt pads strings
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = utf8.RuneCountInString(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if width := utf8.RuneCountInString(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	var builder strings.Builder
+	separator := func() {
+		for _, width := range widths {
+			builder.WriteString("+")
+			builder.WriteString(strings.Repeat("-", width+2))
+		}
+		builder.WriteString("+\n")
+	}
+	line := func(cells []string) {
+		for i, cell := range cells {
+			// operator and details are left-aligned, statistics are right-aligned
+			if i < 2 {
+				builder.WriteString(fmt.Sprintf("| %-*s ", widths[i], cell))
+			} else {
+				builder.WriteString(fmt.Sprintf("| %*s ", widths[i], cell))
+			}
+		}
+		builder.WriteString("|\n")
+	}
+
+	separator()
+	line(headers)
+	separator()
+	for _, row := range rows {
+		line(row)
+	}
+	separator()
+	if root.profiled() {
+		builder.WriteString(fmt.Sprintf("\nTotal database accesses: %d\n", root.totalDbHits()))
+	}
+	return builder.String()
+}
+
+func renderPlanDot(root *planNode, hotThreshold float64) string {
+	var totalDbHits int64
+	if root.profiled() {
+		totalDbHits = root.totalDbHits()
+	}
+	var builder strings.Builder
+	builder.WriteString("digraph plan {\n")
+	builder.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+	id := 0
+	var render func(node *planNode) int
+	render = func(node *planNode) int {
+		nodeId := id
+		id++
+		label := node.name()
+		if details := node.details(); details != "" {
+			label += "\n" + details
+		}
+		attributes := ""
+		if node.profiled() {
+			label += fmt.Sprintf("\nrows: %d\ndb hits: %d", *node.Records, *node.DbHits)
+			if totalDbHits > 0 && float64(*node.DbHits)/float64(totalDbHits) >= hotThreshold {
+				attributes = ", style=filled, fillcolor=\"#f4a582\", color=\"#b2182b\""
+			}
+		}
+		builder.WriteString(fmt.Sprintf("  n%d [label=%s%s];\n", nodeId, dotQuote(label), attributes))
+		for _, child := range node.Children {
+			childId := render(child)
+			builder.WriteString(fmt.Sprintf("  n%d -> n%d;\n", nodeId, childId))
+		}
+		return nodeId
+	}
+	render(root)
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func dotQuote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(s) + `"`
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+)
+
+func TestPlanRendering(outer *testing.T) {
+	profiledPlan := &profile{profile: &db.ProfiledPlan{
+		Operator:    "ProduceResults@neo4j",
+		Arguments:   map[string]any{"Details": "n", "EstimatedRows": 10.0},
+		Identifiers: []string{"n"},
+		DbHits:      0,
+		Records:     3,
+		Time:        1_500_000,
+		Children: []db.ProfiledPlan{{
+			Operator:    "CartesianProduct@neo4j",
+			Identifiers: []string{"n", "m"},
+			DbHits:      0,
+			Records:     3,
+			Children: []db.ProfiledPlan{
+				{Operator: "AllNodesScan@neo4j", Arguments: map[string]any{"Details": "n"}, DbHits: 4, Records: 3},
+				{Operator: "NodeByLabelScan@neo4j", Arguments: map[string]any{"Details": "m:Person"}, DbHits: 96, Records: 1},
+			},
+		}},
+	}}
+
+	outer.Run("renders profiled plan as table", func(t *testing.T) {
+		expected := `+--------------------+----------+----------------+------+---------+------------------------+-----------+
+| Operator           | Details  | Estimated Rows | Rows | DB Hits | Page Cache Hits/Misses | Time (ms) |
++--------------------+----------+----------------+------+---------+------------------------+-----------+
+| +ProduceResults    | n        |             10 |    3 |       0 |                    0/0 |     1.500 |
+| +CartesianProduct  | m, n     |                |    3 |       0 |                    0/0 |     0.000 |
+| | +NodeByLabelScan | m:Person |                |    1 |      96 |                    0/0 |     0.000 |
+| +AllNodesScan      | n        |                |    3 |       4 |                    0/0 |     0.000 |
++--------------------+----------+----------------+------+---------+------------------------+-----------+
+
+Total database accesses: 100
+`
+		AssertStringEqual(t, ProfiledPlanAsText(profiledPlan), expected)
+	})
+
+	outer.Run("renders plan as table without statistics", func(t *testing.T) {
+		plan := &plan{plan: &db.Plan{Operator: "ProduceResults@neo4j", Identifiers: []string{"n"}}}
+
+		actual := PlanAsText(plan)
+
+		AssertStringEqual(t, actual, `+-----------------+---------+----------------+
+| Operator        | Details | Estimated Rows |
++-----------------+---------+----------------+
+| +ProduceResults | n       |                |
++-----------------+---------+----------------+
+`)
+	})
+
+	outer.Run("aligns non-ASCII details", func(t *testing.T) {
+		plan := &plan{plan: &db.Plan{
+			Operator:  "ProduceResults@neo4j",
+			Arguments: map[string]any{"Details": "n"},
+			Children: []db.Plan{
+				{Operator: "NodeByLabelScan@neo4j", Arguments: map[string]any{"Details": "n:Größenordnung"}},
+			},
+		}}
+
+		actual := PlanAsText(plan)
+
+		AssertStringEqual(t, actual, `+------------------+-----------------+----------------+
+| Operator         | Details         | Estimated Rows |
++------------------+-----------------+----------------+
+| +ProduceResults  | n               |                |
+| +NodeByLabelScan | n:Größenordnung |                |
++------------------+-----------------+----------------+
+`)
+	})
+
+	outer.Run("serializes profiled plan to JSON", func(t *testing.T) {
+		rawJson, err := ProfiledPlanAsJSON(profiledPlan)
+		AssertNoError(t, err)
+
+		var actual map[string]any
+		AssertNoError(t, json.Unmarshal(rawJson, &actual))
+		AssertStringEqual(t, actual["operator"].(string), "ProduceResults@neo4j")
+		AssertDeepEquals(t, actual["rows"], 3.0)
+		children := actual["children"].([]any)
+		AssertLen(t, children, 1)
+		grandChildren := children[0].(map[string]any)["children"].([]any)
+		AssertLen(t, grandChildren, 2)
+		AssertDeepEquals(t, grandChildren[1].(map[string]any)["dbHits"], 96.0)
+	})
+
+	outer.Run("serializes plan to JSON without statistics", func(t *testing.T) {
+		rawJson, err := PlanAsJSON(&plan{plan: &db.Plan{Operator: "Filter@neo4j"}})
+		AssertNoError(t, err)
+
+		AssertStringEqual(t, string(rawJson), `{"operator":"Filter@neo4j"}`)
+	})
+
+	outer.Run("renders profiled plan as DOT with hot operators highlighted", func(t *testing.T) {
+		dot := ProfiledPlanAsDot(profiledPlan, DefaultHotOperatorThreshold)
+
+		AssertTrue(t, strings.HasPrefix(dot, "digraph plan {\n"))
+		AssertStringContain(t, dot, `n0 [label="ProduceResults\nn\nrows: 3\ndb hits: 0"];`)
+		AssertStringContain(t, dot, `n2 [label="AllNodesScan\nn\nrows: 3\ndb hits: 4"];`)
+		AssertStringContain(t, dot, `n3 [label="NodeByLabelScan\nm:Person\nrows: 1\ndb hits: 96", style=filled`)
+		AssertStringContain(t, dot, "n0 -> n1;")
+		AssertStringContain(t, dot, "n1 -> n3;")
+	})
+
+	outer.Run("renders nil plans", func(t *testing.T) {
+		AssertStringEqual(t, PlanAsText(nil), "")
+		AssertStringEqual(t, ProfiledPlanAsDot(nil, DefaultHotOperatorThreshold), "")
+		rawJson, err := PlanAsJSON(nil)
+		AssertNoError(t, err)
+		AssertStringEqual(t, string(rawJson), "null")
+	})
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package resolver provides built-in config.ServerAddressResolver implementations resolving the address a driver is
+// created with through DNS, so that clusters behind service discovery can be reached without custom code.
+// See config.Config's AddressResolver.
+//
+// The driver only calls its address resolver when it cannot read a routing table from the routers it knows, there is
+// no periodic resolution in the background. Resolved addresses are cached, and only looked up again by the first
+// call once Config RefreshInterval has elapsed. The Go resolver does not expose the TTL of DNS records,
+// RefreshInterval is therefore expected to match the TTL of the records.
+// When a resolution fails, the last resolved addresses are used, or the address itself if there are none.
+package resolver
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+)
+
+// DNSResolver performs DNS lookups. It is implemented by *net.Resolver and can be injected for testing.
+type DNSResolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Config holds the settings of the built-in resolvers.
+type Config struct {
+	// Resolver performs the DNS lookups.
+	//
+	// default: net.DefaultResolver
+	Resolver DNSResolver
+	// RefreshInterval is how long resolved addresses are used before being resolved again, on the next call of the
+	// resolver.
+	//
+	// default: 30 * time.Second
+	RefreshInterval time.Duration
+	// Timeout bounds every DNS lookup.
+	//
+	// default: 5 * time.Second
+	Timeout time.Duration
+}
+
+const defaultPort = "7687"
+
+// SRV returns a resolver looking up the DNS SRV records of the address, e.g. _neo4j._tcp.example.com for a driver
+// created with neo4j://example.com. Addresses starting with an underscore are looked up as is, e.g. a driver
+// created with neo4j://_bolt._tcp.example.com looks up _bolt._tcp.example.com.
+// The resolved addresses are the targets and ports of the records, ordered by priority and randomized by weight.
+func SRV(configurers ...func(*Config)) config.ServerAddressResolver {
+	return newCachingResolver(func(ctx context.Context, resolver DNSResolver, address config.ServerAddress) ([]config.ServerAddress, error) {
+		var records []*net.SRV
+		var err error
+		if host := address.Hostname(); strings.HasPrefix(host, "_") {
+			_, records, err = resolver.LookupSRV(ctx, "", "", host)
+		} else {
+			_, records, err = resolver.LookupSRV(ctx, "neo4j", "tcp", host)
+		}
+		if err != nil {
+			return nil, err
+		}
+		addresses := make([]config.ServerAddress, len(records))
+		for i, record := range records {
+			addresses[i] = newAddress(strings.TrimSuffix(record.Target, "."), strconv.Itoa(int(record.Port)))
+		}
+		return addresses, nil
+	}, configurers)
+}
+
+// Host returns a resolver expanding the host of the address into all its IP addresses (A and AAAA records), keeping
+// the port of the address.
+// The driver connects to the IP addresses but keeps the host name for TLS: with the +s schemes, the server
+// certificates are verified against the host name, as they are without the resolver.
+func Host(configurers ...func(*Config)) config.ServerAddressResolver {
+	return newCachingResolver(func(ctx context.Context, resolver DNSResolver, address config.ServerAddress) ([]config.ServerAddress, error) {
+		hosts, err := resolver.LookupHost(ctx, address.Hostname())
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(hosts)
+		port := address.Port()
+		if port == "" {
+			port = defaultPort
+		}
+		addresses := make([]config.ServerAddress, len(hosts))
+		for i, host := range hosts {
+			addresses[i] = &resolvedAddress{URL: newAddress(host, port), hostName: address.Hostname()}
+		}
+		return addresses, nil
+	}, configurers)
+}
+
+type lookup func(ctx context.Context, resolver DNSResolver, address config.ServerAddress) ([]config.ServerAddress, error)
+
+type resolution struct {
+	addresses []config.ServerAddress
+	expiresAt time.Time
+}
+
+// flight is a lookup in progress, the callers resolving the same address wait for it instead of looking it up again
+type flight struct {
+	done      chan struct{}
+	addresses []config.ServerAddress
+}
+
+func newCachingResolver(lookup lookup, configurers []func(*Config)) config.ServerAddressResolver {
+	conf := Config{
+		Resolver:        net.DefaultResolver,
+		RefreshInterval: 30 * time.Second,
+		Timeout:         5 * time.Second,
+	}
+	for _, configurer := range configurers {
+		configurer(&conf)
+	}
+	// mut guards resolutions and flights, it is not held during lookups so that they do not hold up one another
+	mut := sync.Mutex{}
+	resolutions := make(map[string]resolution)
+	flights := make(map[string]*flight)
+	return func(address config.ServerAddress) []config.ServerAddress {
+		key := net.JoinHostPort(address.Hostname(), address.Port())
+		mut.Lock()
+		previous, found := resolutions[key]
+		if found && itime.Now().Before(previous.expiresAt) {
+			mut.Unlock()
+			return previous.addresses
+		}
+		if current, inFlight := flights[key]; inFlight {
+			mut.Unlock()
+			<-current.done
+			return current.addresses
+		}
+		current := &flight{done: make(chan struct{})}
+		flights[key] = current
+		mut.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.Timeout)
+		addresses, err := lookup(ctx, conf.Resolver, address)
+		cancel()
+
+		mut.Lock()
+		delete(flights, key)
+		switch {
+		case err == nil && len(addresses) > 0:
+			resolutions[key] = resolution{addresses: addresses, expiresAt: itime.Now().Add(conf.RefreshInterval)}
+			current.addresses = addresses
+		case found:
+			current.addresses = previous.addresses
+		default:
+			current.addresses = []config.ServerAddress{address}
+		}
+		mut.Unlock()
+		close(current.done)
+		return current.addresses
+	}
+}
+
+func newAddress(host, port string) *url.URL {
+	return &url.URL{Host: net.JoinHostPort(host, port)}
+}
+
+// resolvedAddress is an IP address resolved from a host name, against which TLS verifies the server certificate
+type resolvedAddress struct {
+	*url.URL
+	hostName string
+}
+
+// TlsServerName returns the host name the address was resolved from.
+func (a *resolvedAddress) TlsServerName() string {
+	return a.hostName
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package resolver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+)
+
+type dnsResolverFake struct {
+	srv     map[string][]*net.SRV
+	hosts   map[string][]string
+	err     error
+	lookups []string
+	// blocked holds the hosts the lookups of which wait until their channel is closed
+	blocked map[string]chan struct{}
+	mut     sync.Mutex
+}
+
+func (r *dnsResolverFake) LookupSRV(_ context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	if service != "" {
+		name = "_" + service + "._" + proto + "." + name
+	}
+	r.lookups = append(r.lookups, name)
+	if r.err != nil {
+		return "", nil, r.err
+	}
+	return name, r.srv[name], nil
+}
+
+func (r *dnsResolverFake) LookupHost(_ context.Context, host string) ([]string, error) {
+	r.mut.Lock()
+	r.lookups = append(r.lookups, host)
+	blocked := r.blocked[host]
+	r.mut.Unlock()
+	if blocked != nil {
+		<-blocked
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.hosts[host], nil
+}
+
+func (r *dnsResolverFake) looksUp(host string) bool {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	for _, lookup := range r.lookups {
+		if lookup == host {
+			return true
+		}
+	}
+	return false
+}
+
+func hostsOf(addresses []config.ServerAddress) []string {
+	hosts := make([]string, len(addresses))
+	for i, address := range addresses {
+		hosts[i] = net.JoinHostPort(address.Hostname(), address.Port())
+	}
+	return hosts
+}
+
+func addressOf(host string) config.ServerAddress {
+	return &url.URL{Host: host}
+}
+
+func TestSRV(outer *testing.T) {
+	records := map[string][]*net.SRV{
+		"_neo4j._tcp.example.com": {
+			{Target: "core1.example.com.", Port: 7687},
+			{Target: "core2.example.com.", Port: 7688},
+		},
+		"_bolt._tcp.example.com": {{Target: "core3.example.com.", Port: 7689}},
+	}
+
+	outer.Run("looks up the neo4j service of the host", func(t *testing.T) {
+		dns := &dnsResolverFake{srv: records}
+		resolve := SRV(func(conf *Config) { conf.Resolver = dns })
+
+		addresses := resolve(addressOf("example.com:7687"))
+
+		expected := []string{"core1.example.com:7687", "core2.example.com:7688"}
+		if !reflect.DeepEqual(hostsOf(addresses), expected) {
+			t.Errorf("expected %v, got %v", expected, hostsOf(addresses))
+		}
+	})
+
+	outer.Run("looks up service names as is", func(t *testing.T) {
+		dns := &dnsResolverFake{srv: records}
+		resolve := SRV(func(conf *Config) { conf.Resolver = dns })
+
+		addresses := resolve(addressOf("_bolt._tcp.example.com:7687"))
+
+		expected := []string{"core3.example.com:7689"}
+		if !reflect.DeepEqual(hostsOf(addresses), expected) {
+			t.Errorf("expected %v, got %v", expected, hostsOf(addresses))
+		}
+	})
+
+	outer.Run("falls back to the address without records", func(t *testing.T) {
+		dns := &dnsResolverFake{err: errors.New("no such host")}
+		resolve := SRV(func(conf *Config) { conf.Resolver = dns })
+
+		addresses := resolve(addressOf("example.com:7687"))
+
+		expected := []string{"example.com:7687"}
+		if !reflect.DeepEqual(hostsOf(addresses), expected) {
+			t.Errorf("expected %v, got %v", expected, hostsOf(addresses))
+		}
+	})
+}
+
+func TestHost(outer *testing.T) {
+	outer.Run("expands all the addresses of the host", func(t *testing.T) {
+		dns := &dnsResolverFake{hosts: map[string][]string{"example.com": {"10.0.0.2", "10.0.0.1", "fd00::1"}}}
+		resolve := Host(func(conf *Config) { conf.Resolver = dns })
+
+		addresses := resolve(addressOf("example.com:7688"))
+
+		expected := []string{"10.0.0.1:7688", "10.0.0.2:7688", "[fd00::1]:7688"}
+		if !reflect.DeepEqual(hostsOf(addresses), expected) {
+			t.Errorf("expected %v, got %v", expected, hostsOf(addresses))
+		}
+	})
+
+	outer.Run("keeps the host name for TLS", func(t *testing.T) {
+		dns := &dnsResolverFake{hosts: map[string][]string{"example.com": {"10.0.0.1"}}}
+		resolve := Host(func(conf *Config) { conf.Resolver = dns })
+
+		addresses := resolve(addressOf("example.com:7687"))
+
+		named, ok := addresses[0].(interface{ TlsServerName() string })
+		if !ok || named.TlsServerName() != "example.com" {
+			t.Errorf("expected TLS server name example.com for %v", addresses[0])
+		}
+	})
+
+	outer.Run("resolves again once the refresh interval elapsed", func(t *testing.T) {
+		dns := &dnsResolverFake{hosts: map[string][]string{"example.com": {"10.0.0.1"}}}
+		resolve := Host(func(conf *Config) {
+			conf.Resolver = dns
+			conf.RefreshInterval = 10 * time.Millisecond
+		})
+
+		resolve(addressOf("example.com:7687"))
+		resolve(addressOf("example.com:7687"))
+		if len(dns.lookups) != 1 {
+			t.Errorf("expected cached addresses to be used, got lookups %v", dns.lookups)
+		}
+		time.Sleep(20 * time.Millisecond)
+		dns.hosts["example.com"] = []string{"10.0.0.2"}
+		addresses := resolve(addressOf("example.com:7687"))
+
+		expected := []string{"10.0.0.2:7687"}
+		if len(dns.lookups) != 2 || !reflect.DeepEqual(hostsOf(addresses), expected) {
+			t.Errorf("expected %v after a second lookup, got %v after lookups %v", expected, hostsOf(addresses), dns.lookups)
+		}
+	})
+
+	outer.Run("looks up every address once at a time without holding up other addresses", func(t *testing.T) {
+		release := make(chan struct{})
+		dns := &dnsResolverFake{
+			hosts:   map[string][]string{"slow.example.com": {"10.0.0.1"}, "fast.example.com": {"10.0.0.2"}},
+			blocked: map[string]chan struct{}{"slow.example.com": release},
+		}
+		resolve := Host(func(conf *Config) { conf.Resolver = dns })
+
+		results := make(chan []config.ServerAddress, 2)
+		for i := 0; i < 2; i++ {
+			go func() {
+				results <- resolve(addressOf("slow.example.com:7687"))
+			}()
+		}
+		for !dns.looksUp("slow.example.com") {
+			time.Sleep(time.Millisecond)
+		}
+		addresses := resolve(addressOf("fast.example.com:7687"))
+		if expected := []string{"10.0.0.2:7687"}; !reflect.DeepEqual(hostsOf(addresses), expected) {
+			t.Errorf("expected %v, got %v", expected, hostsOf(addresses))
+		}
+		close(release)
+		for i := 0; i < 2; i++ {
+			if expected, actual := []string{"10.0.0.1:7687"}, hostsOf(<-results); !reflect.DeepEqual(actual, expected) {
+				t.Errorf("expected %v, got %v", expected, actual)
+			}
+		}
+		if len(dns.lookups) != 2 {
+			t.Errorf("expected a single lookup of each host, got lookups %v", dns.lookups)
+		}
+	})
+
+	outer.Run("keeps the last resolved addresses when resolution fails", func(t *testing.T) {
+		dns := &dnsResolverFake{hosts: map[string][]string{"example.com": {"10.0.0.1"}}}
+		resolve := Host(func(conf *Config) {
+			conf.Resolver = dns
+			conf.RefreshInterval = -1
+		})
+		resolve(addressOf("example.com:7687"))
+		dns.err = errors.New("temporary failure")
+
+		addresses := resolve(addressOf("example.com:7687"))
+
+		expected := []string{"10.0.0.1:7687"}
+		if !reflect.DeepEqual(hostsOf(addresses), expected) {
+			t.Errorf("expected %v, got %v", expected, hostsOf(addresses))
+		}
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
 	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
 )
 
 type ResultWithContext interface {
@@ -75,6 +76,18 @@ type resultWithContext struct {
 	peeked               bool
 	txState              *transactionState
 	afterConsumptionHook func()
+	summaryNotified      bool
+	span                 tracing.Span
+	// buffered holds what remains of the result once buffered, see buffer
+	buffered *bufferedResult
+}
+
+// bufferedResult is what remains of a result pulled into memory, detached from its connection
+type bufferedResult struct {
+	keys    []string
+	records []*Record
+	summary *db.Summary
+	err     error
 }
 
 func newResultWithContext(
@@ -84,7 +97,7 @@ func newResultWithContext(
 	params map[string]any,
 	txState *transactionState,
 	afterConsumptionHook func(),
-) ResultWithContext {
+) *resultWithContext {
 	return &resultWithContext{
 		conn:                 connection,
 		streamHandle:         stream,
@@ -96,6 +109,9 @@ func newResultWithContext(
 }
 
 func (r *resultWithContext) Keys() ([]string, error) {
+	if r.buffered != nil {
+		return r.buffered.keys, nil
+	}
 	return r.conn.Keys(r.streamHandle)
 }
 
@@ -181,7 +197,8 @@ func (r *resultWithContext) Single(ctx context.Context) (*Record, error) {
 	if r.record != nil {
 		// There were more records, consume the stream since the user didn't
 		// expect more records and should therefore not use them.
-		r.summary, _ = r.conn.Consume(ctx, r.streamHandle)
+		r.summary, _ = r.consume(ctx)
+		r.notifySummary()
 		r.err = &UsageError{Message: "Result contains more than one record"}
 		r.record = nil
 		return nil, r.err
@@ -207,11 +224,15 @@ func (r *resultWithContext) Consume(ctx context.Context) (ResultSummary, error)
 	}
 
 	r.record = nil
-	r.summary, r.err = r.conn.Consume(ctx, r.streamHandle)
+	r.summary, r.err = r.consume(ctx)
 	if r.err != nil {
+		r.endSpan(nil, r.err)
 		return nil, errorutil.WrapError(r.err)
 	}
 	r.callAfterConsumptionHook()
+	if r.notifySummary(); r.err != nil {
+		return nil, r.err
+	}
 	return r.toResultSummary(), nil
 }
 
@@ -223,13 +244,113 @@ func (r *resultWithContext) legacy() Result {
 	return &result{delegate: r}
 }
 
+// buffer pulls the remaining records of the result into memory, detaching the result from its connection so that
+// the connection can run other queries or be returned to the pool. The result completes as if it had been consumed:
+// its summary is passed on to the transaction and its query span ends.
 func (r *resultWithContext) buffer(ctx context.Context) {
-	if r.err = r.conn.Buffer(ctx, r.streamHandle); r.err == nil {
-		r.callAfterConsumptionHook()
+	if r.buffered != nil {
+		return
+	}
+	if r.summary != nil || r.err != nil || r.peeked && r.peekedSummary != nil {
+		// the stream has been fully received already
+		r.detach(nil)
+		r.notifySummaryOf(r.peekedSummary)
+		return
+	}
+	buffered := &bufferedResult{}
+	buffered.keys, _ = r.conn.Keys(r.streamHandle)
+	r.buffered = buffered
+	if err := r.conn.Buffer(ctx, r.streamHandle); err != nil {
+		buffered.err = err
+		r.err = err
+		r.endSpan(nil, err)
+		return
+	}
+	for {
+		record, summary, err := r.conn.Next(ctx, r.streamHandle)
+		if record != nil {
+			buffered.records = append(buffered.records, record)
+			continue
+		}
+		buffered.summary, buffered.err = summary, err
+		break
+	}
+	if buffered.err != nil {
+		r.endSpan(nil, buffered.err)
+		return
+	}
+	r.callAfterConsumptionHook()
+	r.notifySummaryOf(buffered.summary)
+}
+
+// discard discards the remaining records of the result and receives its summary, detaching the result from its
+// connection. The result completes as if it had been consumed.
+func (r *resultWithContext) discard(ctx context.Context) {
+	if r.buffered != nil {
+		return
+	}
+	if r.summary != nil || r.err != nil || r.peeked && r.peekedSummary != nil {
+		// the stream has been fully received already
+		r.detach(nil)
+		r.notifySummaryOf(r.peekedSummary)
+		return
+	}
+	buffered := &bufferedResult{}
+	buffered.keys, _ = r.conn.Keys(r.streamHandle)
+	buffered.summary, buffered.err = r.conn.Consume(ctx, r.streamHandle)
+	r.buffered = buffered
+	r.peeked, r.peekedRecord = false, nil
+	if buffered.err != nil {
+		r.err = buffered.err
+		r.endSpan(nil, buffered.err)
+		return
 	}
+	r.callAfterConsumptionHook()
+	r.notifySummaryOf(buffered.summary)
 }
 
-func (r *resultWithContext) toResultSummary() ResultSummary {
+// detach stops the result from reading from its connection, without receiving anything more from the server, before
+// the connection is returned to the pool and reset, possibly in the background.
+// A result that has not been fully received fails with err, the records it has not received yet are lost.
+func (r *resultWithContext) detach(err error) {
+	if r.buffered != nil {
+		return
+	}
+	buffered := &bufferedResult{summary: r.summary, err: r.err}
+	buffered.keys, _ = r.conn.Keys(r.streamHandle)
+	if r.peeked && r.peekedSummary != nil {
+		buffered.summary = r.peekedSummary
+	}
+	if buffered.summary == nil && buffered.err == nil {
+		buffered.err = err
+		r.endSpan(nil, err)
+	}
+	r.buffered = buffered
+}
+
+// next returns the next record or the summary of the result, from memory once buffered
+func (r *resultWithContext) next(ctx context.Context) (*Record, *db.Summary, error) {
+	if r.buffered == nil {
+		return r.conn.Next(ctx, r.streamHandle)
+	}
+	if len(r.buffered.records) > 0 {
+		record := r.buffered.records[0]
+		r.buffered.records = r.buffered.records[1:]
+		return record, nil, nil
+	}
+	return nil, r.buffered.summary, r.buffered.err
+}
+
+// consume discards the remaining records and returns the summary of the result, from memory once buffered
+func (r *resultWithContext) consume(ctx context.Context) (*db.Summary, error) {
+	if r.buffered == nil {
+		return r.conn.Consume(ctx, r.streamHandle)
+	}
+	r.buffered.records = nil
+	return r.buffered.summary, r.buffered.err
+}
+
+func (r *resultWithContext) toResultSummary() *resultSummary {
 	return &resultSummary{
 		sum:    r.summary,
 		cypher: r.cypher,
@@ -243,17 +364,22 @@ func (r *resultWithContext) advance(ctx context.Context) {
 		r.summary, r.peekedSummary = r.peekedSummary, nil
 		r.peeked = false
 	} else {
-		r.record, r.summary, r.err = r.conn.Next(ctx, r.streamHandle)
+		r.record, r.summary, r.err = r.next(ctx)
 		if r.err != nil {
+			r.endSpan(nil, r.err)
 			r.txState.onError(r.err)
 		}
 	}
+	r.notifySummary()
 }
 
 func (r *resultWithContext) peek(ctx context.Context) {
 	if !r.peeked {
-		r.peekedRecord, r.peekedSummary, r.err = r.conn.Next(ctx, r.streamHandle)
+		r.peekedRecord, r.peekedSummary, r.err = r.next(ctx)
 		r.peeked = true
+		if r.err != nil {
+			r.endSpan(nil, r.err)
+		}
 	}
 }
 
@@ -276,7 +402,45 @@ func (r *resultWithContext) callAfterConsumptionHook() {
 	r.afterConsumptionHook = nil
 }
 
-func (r *resultWithContext) errorHandler(error) {
+// notifySummary lets the transaction know about the summary, the first time it is received
+// The transaction may reject the summary, in which case the result fails.
+func (r *resultWithContext) notifySummary() {
+	r.notifySummaryOf(r.summary)
+}
+
+func (r *resultWithContext) notifySummaryOf(summary *db.Summary) {
+	if summary == nil || r.summaryNotified {
+		return
+	}
+	r.summaryNotified = true
+	err := r.txState.onSummary(&resultSummary{sum: summary, cypher: r.cypher, params: r.params})
+	r.endSpan(map[string]any{tracing.QueryCountersAttribute: summary.Counters}, err)
+	if err != nil && r.err == nil {
+		// the result has been fully received nonetheless
+		r.callAfterConsumptionHook()
+		r.err = err
+	}
+}
+
+// trackQuery ends the given query span along with the result, which has just become available
+func (r *resultWithContext) trackQuery(span tracing.Span) {
+	if slowQuery, ok := span.(*slowQuerySpan); ok {
+		slowQuery.onResultAvailable()
+	}
+	r.span = span
+}
+
+// endSpan ends the tracing of the query, if not done already
+func (r *resultWithContext) endSpan(attributes map[string]any, err error) {
+	if r.span == nil {
+		return
+	}
+	r.span.End(attributes, err)
+	r.span = nil
+}
+
+func (r *resultWithContext) errorHandler(err error) {
+	r.endSpan(nil, err)
 	if r.err == nil {
 		r.err = &UsageError{Message: resultFailedError}
 	}
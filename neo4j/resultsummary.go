@@ -19,6 +19,7 @@ package neo4j
 
 import (
 	"fmt"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
 	inotifications "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/notifications"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/notifications"
@@ -645,6 +646,36 @@ func (s *resultSummary) GqlStatusObjects() []GqlStatusObject {
 	return []GqlStatusObject{&gqlStatusObject{gqlStatusObject: inotifications.ToGqlStatusObjectFromSummary(s.sum.StreamSummary)}}
 }
 
+// notifyHandlerOf adapts the user-provided handler to be called with every notification of a result summary
+func notifyHandlerOf(handler config.NotificationHandler) func(*resultSummary) error {
+	return func(s *resultSummary) error {
+		if s.sum.GqlStatusObjects != nil {
+			for _, status := range s.sum.GqlStatusObjects {
+				if status.IsNotification {
+					handler(s.cypher, *inotifications.ToNotification(status), status)
+				}
+			}
+			return nil
+		}
+		for _, notification := range s.sum.Notifications {
+			handler(s.cypher, notification, *inotifications.ToGqlStatusObject(notification))
+		}
+		return nil
+	}
+}
+
+// strictPolicyCheckOf fails result summaries that include a notification matching the policy
+func strictPolicyCheckOf(policy notifications.StrictPolicy) func(*resultSummary) error {
+	return func(s *resultSummary) error {
+		for _, status := range s.GqlStatusObjects() {
+			if status.IsNotification() && policy.Matches(status.GqlStatus(), status.Classification()) {
+				return &NotificationError{Query: s.cypher, GqlStatusObject: status}
+			}
+		}
+		return nil
+	}
+}
+
 func calculateGqlStatusWeight(gqlStatusObject GqlStatusObject) int {
 	status := gqlStatusObject.GqlStatus()[:2]
 	switch status {
@@ -19,6 +19,8 @@ package neo4j
 
 import (
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/notifications"
 	"reflect"
 	"testing"
 )
@@ -106,3 +108,99 @@ func TestCounters(st *testing.T) {
 		}
 	})
 }
+
+func TestNotifyHandler(outer *testing.T) {
+	type notified struct {
+		query        string
+		notification db.Notification
+		status       db.GqlStatusObject
+	}
+
+	collect := func(received *[]notified) func(string, db.Notification, db.GqlStatusObject) {
+		return func(query string, notification db.Notification, status db.GqlStatusObject) {
+			*received = append(*received, notified{query, notification, status})
+		}
+	}
+
+	outer.Run("calls handler with notifications and derived GQL status objects", func(t *testing.T) {
+		var received []notified
+		summary := &resultSummary{cypher: "MATCH (n), (m) RETURN n, m", sum: &db.Summary{
+			Notifications: []db.Notification{{Code: "Neo.ClientNotification.Statement.CartesianProduct", Severity: "WARNING", Category: "PERFORMANCE"}},
+		}}
+
+		notifyHandlerOf(collect(&received))(summary)
+
+		if len(received) != 1 {
+			t.Fatalf("Expected 1 notification, got %d", len(received))
+		}
+		AssertStringEqual(t, received[0].query, "MATCH (n), (m) RETURN n, m")
+		AssertStringEqual(t, received[0].notification.Code, "Neo.ClientNotification.Statement.CartesianProduct")
+		AssertStringEqual(t, received[0].status.GqlStatus, "01N42")
+		AssertStringEqual(t, received[0].status.Classification, "PERFORMANCE")
+	})
+
+	outer.Run("calls handler with GQL status objects that are notifications only", func(t *testing.T) {
+		var received []notified
+		summary := &resultSummary{cypher: "RETURN 1", sum: &db.Summary{
+			GqlStatusObjects: []db.GqlStatusObject{
+				{GqlStatus: "00000", StatusDescription: "note: successful completion"},
+				{GqlStatus: "01N50", Code: "Neo.ClientNotification.Statement.UnknownLabelWarning", Severity: "WARNING", IsNotification: true},
+			},
+		}}
+
+		notifyHandlerOf(collect(&received))(summary)
+
+		if len(received) != 1 {
+			t.Fatalf("Expected 1 notification, got %d", len(received))
+		}
+		AssertStringEqual(t, received[0].status.GqlStatus, "01N50")
+		AssertStringEqual(t, received[0].notification.Code, "Neo.ClientNotification.Statement.UnknownLabelWarning")
+		AssertStringEqual(t, received[0].notification.Severity, "WARNING")
+	})
+}
+
+func TestStrictPolicyCheck(outer *testing.T) {
+	cartesianProduct := db.Notification{
+		Code:     "Neo.ClientNotification.Statement.CartesianProduct",
+		Severity: "WARNING",
+		Category: "PERFORMANCE",
+	}
+	unknownLabel := db.GqlStatusObject{
+		GqlStatus:      "01N50",
+		Severity:       "WARNING",
+		Classification: "UNRECOGNIZED",
+		IsNotification: true,
+	}
+
+	outer.Run("fails on notification of matching category", func(t *testing.T) {
+		check := strictPolicyCheckOf(notifications.StrictPolicy{Categories: []notifications.NotificationCategory{notifications.Performance}})
+
+		err := check(&resultSummary{cypher: "MATCH (n), (m) RETURN n, m", sum: &db.Summary{Notifications: []db.Notification{cartesianProduct}}})
+
+		AssertTrue(t, IsNotificationError(err))
+		notificationErr := err.(*NotificationError)
+		AssertStringEqual(t, notificationErr.Query, "MATCH (n), (m) RETURN n, m")
+		AssertDeepEquals(t, notificationErr.GqlStatusObject.Classification(), notifications.Performance)
+	})
+
+	outer.Run("fails on notification of matching GQL status", func(t *testing.T) {
+		check := strictPolicyCheckOf(notifications.StrictPolicy{GqlStatuses: []string{"01N50"}})
+
+		err := check(&resultSummary{sum: &db.Summary{GqlStatusObjects: []db.GqlStatusObject{unknownLabel}}})
+
+		AssertTrue(t, IsNotificationError(err))
+		AssertStringEqual(t, err.(*NotificationError).GqlStatusObject.GqlStatus(), "01N50")
+	})
+
+	outer.Run("ignores notifications not matching", func(t *testing.T) {
+		check := strictPolicyCheckOf(notifications.StrictPolicy{
+			Categories:  []notifications.NotificationCategory{notifications.Deprecation},
+			GqlStatuses: []string{"01N00"},
+		})
+
+		err := check(&resultSummary{sum: &db.Summary{GqlStatusObjects: []db.GqlStatusObject{unknownLabel}}})
+		AssertNoError(t, err)
+		err = check(&resultSummary{sum: &db.Summary{Notifications: []db.Notification{cartesianProduct}}})
+		AssertNoError(t, err)
+	})
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache persists the routing tables of a driver, so that a driver created later, e.g. after a process restart, can
+// bootstrap from the last known tables instead of depending on the initial router only.
+// See config.Config's RoutingTableCache.
+//
+// Tables are stored under a key identifying the cluster, i.e. the address of the initial router, so that a cache
+// can be shared by drivers connected to different clusters.
+// Implementations are expected to be safe for concurrent use.
+type Cache interface {
+	// Load returns the tables stored under the key, or no table if there is none.
+	Load(key string) ([]Table, error)
+	// Store replaces the tables stored under the key.
+	Store(key string, tables []Table) error
+}
+
+// NewFileCache returns a Cache storing routing tables as JSON in the file at the given path.
+// The file is created upon the first Store, and replaced atomically by every Store.
+func NewFileCache(path string) Cache {
+	return &fileCache{path: path}
+}
+
+type fileCache struct {
+	path string
+	mut  sync.Mutex
+}
+
+func (c *fileCache) Load(key string) ([]Table, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	tablesByKey, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+	return tablesByKey[key], nil
+}
+
+func (c *fileCache) Store(key string, tables []Table) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	tablesByKey, err := c.read()
+	var corruptErr *corruptFileError
+	if errors.As(err, &corruptErr) {
+		// the file is replaced below, start over rather than failing every store from now on
+		tablesByKey, err = make(map[string][]Table), nil
+	}
+	if err != nil {
+		return err
+	}
+	tablesByKey[key] = tables
+	content, err := json.Marshal(tablesByKey)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(content); err != nil {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+		return err
+	}
+	if err = file.Close(); err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return os.Rename(file.Name(), c.path)
+}
+
+// Must be called while holding the lock
+func (c *fileCache) read() (map[string][]Table, error) {
+	content, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string][]Table), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tablesByKey := make(map[string][]Table)
+	if err = json.Unmarshal(content, &tablesByKey); err != nil {
+		return nil, &corruptFileError{path: c.path, cause: err}
+	}
+	return tablesByKey, nil
+}
+
+type corruptFileError struct {
+	path  string
+	cause error
+}
+
+func (e *corruptFileError) Error() string {
+	return fmt.Sprintf("routing table cache file %s is corrupt: %v", e.path, e.cause)
+}
+
+func (e *corruptFileError) Unwrap() error {
+	return e.cause
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package routing
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileCache(outer *testing.T) {
+	table := Table{
+		Database:   "movies",
+		Routers:    []string{"router:7687"},
+		Readers:    []string{"reader:7687"},
+		Writers:    []string{"writer:7687"},
+		TimeToLive: 5 * time.Minute,
+		ExpiresAt:  time.Unix(1700000000, 0).UTC(),
+	}
+
+	outer.Run("loads nothing before storing", func(t *testing.T) {
+		cache := NewFileCache(filepath.Join(t.TempDir(), "routing.json"))
+
+		tables, err := cache.Load("cluster:7687")
+
+		if err != nil || len(tables) != 0 {
+			t.Errorf("expected no table and no error, got %v and %v", tables, err)
+		}
+	})
+
+	outer.Run("loads stored tables across instances", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "routing.json")
+		if err := NewFileCache(path).Store("cluster:7687", []Table{table}); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewFileCache(path).Store("other:7687", nil); err != nil {
+			t.Fatal(err)
+		}
+
+		tables, err := NewFileCache(path).Load("cluster:7687")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tables, []Table{table}) {
+			t.Errorf("expected %v, got %v", []Table{table}, tables)
+		}
+	})
+
+	outer.Run("fails to load corrupted files", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "routing.json")
+		if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := NewFileCache(path).Load("cluster:7687")
+
+		if err == nil {
+			t.Error("expected an error")
+		}
+	})
+	outer.Run("overwrites corrupted files when storing", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "routing.json")
+		if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cache := NewFileCache(path)
+
+		if err := cache.Store("cluster:7687", []Table{table}); err != nil {
+			t.Fatal(err)
+		}
+
+		tables, err := cache.Load("cluster:7687")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tables, []Table{table}) {
+			t.Errorf("expected %v, got %v", []Table{table}, tables)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package routing exposes the routing tables the driver maintains for clusters, i.e. when connecting with one of
+// the neo4j:// URI schemes.
+// See DriverWithContext's RoutingTable and SubscribeRoutingTableChanges.
+package routing
+
+import "time"
+
+// Table is a read-only snapshot of the routing table of a database.
+type Table struct {
+	// Database is the name of the database the table routes to
+	Database string
+	// Routers are the servers the table is fetched from
+	Routers []string
+	// Readers are the servers read queries are sent to
+	Readers []string
+	// Writers are the servers write queries are sent to
+	Writers []string
+	// TimeToLive is the validity of the table, as reported by the server
+	TimeToLive time.Duration
+	// ExpiresAt is when the table must be fetched again at the latest
+	ExpiresAt time.Time
+}
+
+// Change describes how the servers of a routing table changed, either after the table has been fetched or after
+// servers have been removed from it because of failures.
+type Change struct {
+	// Database is the name of the database the table routes to
+	Database string
+	// Previous is the table before the change, nil if the table had not been fetched before
+	Previous *Table
+	// Current is the table after the change, without any server when the table has been evicted
+	Current        Table
+	AddedRouters   []string
+	RemovedRouters []string
+	AddedReaders   []string
+	RemovedReaders []string
+	AddedWriters   []string
+	RemovedWriters []string
+}
+
+// WritersChanged tells whether writers have been added or removed, e.g. after a leader switch.
+func (c Change) WritersChanged() bool {
+	return len(c.AddedWriters) > 0 || len(c.RemovedWriters) > 0
+}
+
+// IsEmpty tells whether no server has been added or removed.
+func (c Change) IsEmpty() bool {
+	return len(c.AddedRouters) == 0 && len(c.RemovedRouters) == 0 &&
+		len(c.AddedReaders) == 0 && len(c.RemovedReaders) == 0 &&
+		!c.WritersChanged()
+}
+
+// ChangeListener is called with each change of a routing table.
+//
+// Listeners run after the router has released the routing tables, on the goroutine that updated the table: a session
+// fetching it, the background refresher, or a query that failed on a server and removed it from the table.
+// Changes of different databases may therefore be reported concurrently.
+type ChangeListener func(change Change)
@@ -19,18 +19,23 @@ package neo4j
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/racing"
 	"math"
 	"time"
 
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/collections"
 	idb "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/retry"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/telemetry"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	itracing "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/tracing"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/notifications"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
 )
 
 // TransactionWork represents a unit of work that will be executed against the provided
@@ -117,6 +122,10 @@ type SessionConfig struct {
 	//		session, all queries within that session are executed with the explicit database name 'movies' supplied.
 	//		Any change to the user’s home database is reflected only in sessions created after such change takes effect.
 	//		This behavior requires additional network communication.
+	//		To avoid it, the driver caches the home database of each user (and impersonated user). Sessions using a
+	//		cached home database route their first query with it but let the server pick the database, then use the
+	//		database the server reports in the result summary explicitly. The cached home database is replaced when the
+	//		server reports another database, and fetched again when routing to it fails.
 	//		In clustered environments, it is strongly recommended to avoid a single point of failure.
 	//		For instance, by ensuring that the connection URI resolves to multiple endpoints.
 	//		For older Bolt protocol versions, the behavior is the same as described for the bolt schemes above.
@@ -172,6 +181,11 @@ type SessionConfig struct {
 	// NotificationsDisabledClassifications is part of the GQL compliant notifications preview feature
 	// (see README on what it means in terms of support and compatibility guarantees)
 	NotificationsDisabledClassifications notifications.NotificationDisabledClassifications
+	// NotificationHandler is called for each notification the server reports in the result summaries of this
+	// session.
+	// By default, the driver's settings are used.
+	// Else, this option overrides the driver's settings.
+	NotificationHandler config.NotificationHandler
 	// Auth is used to overwrite the authentication information for the session.
 	// This requires the server to support re-authentication on the protocol level.
 	// `nil` will make the driver use the authentication information from the driver configuration.
@@ -216,6 +230,20 @@ type sessionWithContext struct {
 	config        SessionConfig
 	auth          *idb.ReAuthToken
 	closed        bool
+	notifyHandler config.NotificationHandler
+	span          tracing.Span
+	// spanCtx is the context returned by the tracer for span, the spans of the session are started as its children
+	spanCtx       context.Context
+	stats         *driverStats
+	homeDatabases *homeDatabaseCache
+	// homeDbKey is the key the home database is cached under, once resolved. It is nil when it cannot be cached.
+	homeDbKey *homeDatabaseKey
+	// optimisticHomeDb is set while the home database taken from homeDatabases has not been confirmed by the server.
+	// The database is then used to route queries, but not selected on connections: the server runs them on the
+	// actual home database, which it reports in the result summaries.
+	optimisticHomeDb bool
+	// usesHomeDb is set when the session has no DatabaseName configured and runs queries on the home database
+	usesHomeDb bool
 }
 
 func newSessionWithContext(
@@ -234,6 +262,11 @@ func newSessionWithContext(
 		fetchSize = sessConfig.FetchSize
 	}
 
+	notificationHandler := config.NotificationHandler
+	if sessConfig.NotificationHandler != nil {
+		notificationHandler = sessConfig.NotificationHandler
+	}
+
 	return &sessionWithContext{
 		driverConfig:  config,
 		router:        router,
@@ -242,12 +275,14 @@ func newSessionWithContext(
 		bookmarks:     newSessionBookmarks(sessConfig.BookmarkManager, sessConfig.Bookmarks),
 		config:        sessConfig,
 		resolveHomeDb: sessConfig.DatabaseName == "",
+		usesHomeDb:    sessConfig.DatabaseName == "",
 		sleep:         racing.Sleep,
 		log:           logger,
 		logId:         logId,
 		throttleTime:  time.Second * 1,
 		fetchSize:     fetchSize,
 		auth:          token,
+		notifyHandler: notificationHandler,
 	}
 }
 
@@ -313,9 +348,14 @@ func (s *sessionWithContext) BeginTransaction(ctx context.Context, configurers .
 		return nil, err
 	}
 
+	ctx, span := s.startSpan(ctx, tracing.TransactionOperation, s.defaultMode, 0)
+
 	// Get a connection from the pool. This could fail in clustered environment.
+	acquisitionStart := itime.Now()
 	conn, err := s.getConnection(ctx, s.defaultMode, s.driverConfig.ConnectionLivenessCheckTimeout)
+	acquisition := itime.Since(acquisitionStart)
 	if err != nil {
+		span.End(nil, err)
 		return nil, errorutil.WrapError(err)
 	}
 
@@ -326,6 +366,7 @@ func (s *sessionWithContext) BeginTransaction(ctx context.Context, configurers .
 	beginBookmarks, err := s.getBookmarks(ctx)
 	if err != nil {
 		s.pool.Return(ctx, conn)
+		span.End(nil, err)
 		return nil, errorutil.WrapError(err)
 	}
 	txHandle, err := conn.TxBegin(ctx,
@@ -343,11 +384,12 @@ func (s *sessionWithContext) BeginTransaction(ctx context.Context, configurers .
 		}, true)
 	if err != nil {
 		s.pool.Return(ctx, conn)
+		span.End(nil, err)
 		return nil, errorutil.WrapError(err)
 	}
 
 	// Create transaction wrapper
-	txState := &transactionState{}
+	txState := s.newTransactionState(ctx, telemetry.UnmanagedTransaction, config.Metadata, acquisition)
 	tx := &explicitTransaction{
 		conn:      conn,
 		fetchSize: s.fetchSize,
@@ -360,11 +402,15 @@ func (s *sessionWithContext) BeginTransaction(ctx context.Context, configurers .
 			return
 		}
 		// On run failure, transaction closed (rolled back or committed)
+		s.checkHomeDatabase(tx.txState.err)
 		bookmarkErr := s.retrieveBookmarks(ctx, tx.conn, beginBookmarks)
+		// results must not read from the connection once returned to the pool
+		tx.txState.detachResults()
 		s.pool.Return(ctx, tx.conn)
 		tx.txState.err = errorutil.CombineAllErrors(tx.txState.err, bookmarkErr)
 		tx.conn = nil
 		s.explicitTx = nil
+		span.End(nil, tx.txState.err)
 	}
 	tx.onClosed = onClose
 	txState.resultErrorHandlers = append(txState.resultErrorHandlers, func(error) { onClose() })
@@ -440,13 +486,30 @@ func (s *sessionWithContext) runRetriable(
 		MaxDeadConnections:      s.driverConfig.MaxConnectionPoolSize,
 		DatabaseName:            s.config.DatabaseName,
 	}
+	if tracer := s.driverConfig.Tracer; tracer != nil {
+		state.Sleep = func(ctx context.Context, delay time.Duration) error {
+			ctx, span := tracer.Start(ctx, tracing.RetryWaitOperation, map[string]any{
+				tracing.RetryAttemptAttribute: len(state.Errs) + 1,
+			})
+			err := s.sleep(ctx, delay)
+			span.End(nil, err)
+			return err
+		}
+	}
+	attempts := 0
 	for state.Continue(ctx) {
-		if hasCompleted, result := s.executeTransactionFunction(ctx, mode, config, &state, work, blockingTxBegin, api); hasCompleted {
+		attempts++
+		attemptCtx, span := s.startSpan(ctx, tracing.TransactionOperation, mode, attempts)
+		if hasCompleted, result := s.executeTransactionFunction(attemptCtx, mode, config, &state, work, blockingTxBegin, api); hasCompleted {
+			span.End(nil, nil)
+			s.stats.onTransactionFunction(s.config.DatabaseName, attempts, nil)
 			return result, nil
 		}
+		span.End(nil, state.Errs[len(state.Errs)-1])
 	}
 
 	err := state.ProduceError()
+	s.stats.onTransactionFunction(s.config.DatabaseName, attempts, err)
 	s.log.Error(log.Session, s.logId, err)
 	return nil, err
 }
@@ -460,7 +523,9 @@ func (s *sessionWithContext) executeTransactionFunction(
 	blockingTxBegin bool,
 	api telemetry.API) (bool, any) {
 
+	acquisitionStart := itime.Now()
 	conn, err := s.getConnection(ctx, mode, s.driverConfig.ConnectionLivenessCheckTimeout)
+	acquisition := itime.Since(acquisitionStart)
 	if err != nil {
 		state.OnFailure(ctx, err, conn, false)
 		return false, nil
@@ -472,8 +537,13 @@ func (s *sessionWithContext) executeTransactionFunction(
 		})
 	}
 
+	var txState *transactionState
 	// handle transaction function panic as well
 	defer func() {
+		if txState != nil {
+			// results must not read from the connection once returned to the pool
+			txState.detachResults()
+		}
 		s.pool.Return(ctx, conn)
 	}()
 
@@ -501,19 +571,27 @@ func (s *sessionWithContext) executeTransactionFunction(
 		return false, nil
 	}
 
-	tx := managedTransaction{conn: conn, fetchSize: s.fetchSize, txHandle: txHandle, txState: &transactionState{}}
+	txState = s.newTransactionState(ctx, api, config.Metadata, acquisition)
+	tx := managedTransaction{conn: conn, fetchSize: s.fetchSize, txHandle: txHandle, txState: txState}
 	x, err := work(&tx)
 	if err != nil {
 		// If the client returns a client specific error that means that
 		// client wants to rollback. We don't do an explicit rollback here
 		// but instead rely on the pool invoking reset on the connection,
 		// that will do an implicit rollback.
+		s.checkHomeDatabase(err)
 		state.OnFailure(ctx, err, conn, false)
 		return false, nil
 	}
 
+	if err = tx.txState.consumeResults(ctx); err != nil {
+		s.checkHomeDatabase(err)
+		state.OnFailure(ctx, err, conn, false)
+		return false, nil
+	}
 	err = conn.TxCommit(ctx, txHandle)
 	if err != nil {
+		s.checkHomeDatabase(err)
 		state.OnFailure(ctx, err, conn, true)
 		return false, nil
 	}
@@ -544,7 +622,19 @@ func (s *sessionWithContext) getServers(mode idb.AccessMode) func() []string {
 	}
 }
 
-func (s *sessionWithContext) getConnection(ctx context.Context, mode idb.AccessMode, livenessCheckTimeout time.Duration) (idb.Connection, error) {
+func (s *sessionWithContext) getConnection(ctx context.Context, mode idb.AccessMode, livenessCheckTimeout time.Duration) (conn idb.Connection, err error) {
+	ctx, span := s.startSpan(ctx, tracing.ConnectionAcquisitionOperation, mode, 0)
+	defer func() {
+		var attributes map[string]any
+		if conn != nil && s.driverConfig.Tracer != nil {
+			attributes = map[string]any{tracing.ServerAddressAttribute: conn.ServerName()}
+			if s.config.DatabaseName != idb.DefaultDatabase {
+				attributes[tracing.DatabaseAttribute] = s.config.DatabaseName
+			}
+		}
+		span.End(attributes, err)
+	}()
+
 	timeout := s.driverConfig.ConnectionAcquisitionTimeout
 	if timeout > 0 {
 		var cancel context.CancelFunc
@@ -559,12 +649,22 @@ func (s *sessionWithContext) getConnection(ctx context.Context, mode idb.AccessM
 	if err := s.resolveHomeDatabase(ctx); err != nil {
 		return nil, errorutil.WrapError(err)
 	}
-	_, err := s.getOrUpdateServers(ctx, mode)
-	if err != nil {
-		return nil, errorutil.WrapError(err)
+	if _, err := s.getOrUpdateServers(ctx, mode); err != nil {
+		if !s.optimisticHomeDb {
+			return nil, errorutil.WrapError(err)
+		}
+		// The cached home database may be stale, e.g. it has been dropped, resolve it again
+		s.log.Infof(log.Session, s.logId, "Could not route to cached home database '%s', resolving it again: %s", s.config.DatabaseName, err)
+		s.discardHomeDatabase()
+		if err := s.resolveHomeDatabase(ctx); err != nil {
+			return nil, errorutil.WrapError(err)
+		}
+		if _, err := s.getOrUpdateServers(ctx, mode); err != nil {
+			return nil, errorutil.WrapError(err)
+		}
 	}
 
-	conn, err := s.pool.Borrow(
+	conn, err = s.pool.Borrow(
 		ctx,
 		s.getServers(mode),
 		timeout != 0,
@@ -576,7 +676,7 @@ func (s *sessionWithContext) getConnection(ctx context.Context, mode idb.AccessM
 	}
 
 	// Select database on server
-	if s.config.DatabaseName != idb.DefaultDatabase {
+	if s.config.DatabaseName != idb.DefaultDatabase && !s.optimisticHomeDb {
 		dbSelector, ok := conn.(idb.DatabaseSelector)
 		if !ok {
 			s.pool.Return(ctx, conn)
@@ -629,10 +729,12 @@ func (s *sessionWithContext) Run(ctx context.Context,
 		return nil, err
 	}
 
+	acquisitionStart := itime.Now()
 	conn, err := s.getConnection(ctx, s.defaultMode, s.driverConfig.ConnectionLivenessCheckTimeout)
 	if err != nil {
 		return nil, errorutil.WrapError(err)
 	}
+	acquisition := itime.Since(acquisitionStart)
 
 	if !s.driverConfig.TelemetryDisabled {
 		conn.Telemetry(telemetry.AutoCommitTransaction, nil)
@@ -643,8 +745,10 @@ func (s *sessionWithContext) Run(ctx context.Context,
 		s.pool.Return(ctx, conn)
 		return nil, errorutil.WrapError(err)
 	}
+	txState := s.newTransactionState(s.spanCtx, telemetry.AutoCommitTransaction, config.Metadata, acquisition)
+	runCtx, span := txState.startQuery(ctx, conn, cypher, params)
 	stream, err := conn.Run(
-		ctx,
+		runCtx,
 		idb.Command{
 			Cypher:    cypher,
 			Params:    params,
@@ -664,18 +768,22 @@ func (s *sessionWithContext) Run(ctx context.Context,
 		},
 	)
 	if err != nil {
+		s.checkHomeDatabase(err)
 		s.pool.Return(ctx, conn)
+		span.End(nil, err)
 		return nil, errorutil.WrapError(err)
 	}
 
+	result := newResultWithContext(conn, stream, cypher, params, txState, func() {
+		if err := s.retrieveBookmarks(ctx, conn, runBookmarks); err != nil {
+			s.log.Warnf(log.Session, s.logId, "could not retrieve bookmarks after result consumption: %s\n"+
+				"the result of the initiating auto-commit transaction may not be visible to subsequent operations", err.Error())
+		}
+	})
+	result.trackQuery(span)
 	s.autocommitTx = &autocommitTransaction{
 		conn: conn,
-		res: newResultWithContext(conn, stream, cypher, params, &transactionState{}, func() {
-			if err := s.retrieveBookmarks(ctx, conn, runBookmarks); err != nil {
-				s.log.Warnf(log.Session, s.logId, "could not retrieve bookmarks after result consumption: %s\n"+
-					"the result of the initiating auto-commit transaction may not be visible to subsequent operations", err.Error())
-			}
-		}),
+		res:  result,
 		onClosed: func() {
 			s.pool.Return(ctx, conn)
 			s.autocommitTx = nil
@@ -685,6 +793,35 @@ func (s *sessionWithContext) Run(ctx context.Context,
 	return s.autocommitTx.res, nil
 }
 
+// newTransactionState creates the state of a transaction, the queries of which are traced as children of the span
+// of spanCtx.
+func (s *sessionWithContext) newTransactionState(spanCtx context.Context, api telemetry.API, metadata map[string]any, acquisition time.Duration) *transactionState {
+	txState := &transactionState{
+		tracer:      s.driverConfig.Tracer,
+		stats:       s.stats,
+		api:         api,
+		slowQueries: newSlowQueryLog(s.driverConfig, s.log, s.logId),
+		metadata:    metadata,
+		acquisition: acquisition,
+	}
+	if s.driverConfig.Tracer != nil {
+		txState.spanCtx = spanCtx
+	}
+	if s.notifyHandler != nil {
+		txState.summaryHandlers = append(txState.summaryHandlers, notifyHandlerOf(s.notifyHandler))
+	}
+	if strictPolicy := s.driverConfig.NotificationsStrictPolicy; !strictPolicy.IsEmpty() {
+		txState.summaryHandlers = append(txState.summaryHandlers, strictPolicyCheckOf(strictPolicy))
+	}
+	if s.optimisticHomeDb {
+		txState.summaryHandlers = append(txState.summaryHandlers, s.confirmHomeDatabase)
+	}
+	if s.usesHomeDb {
+		txState.resultErrorHandlers = append(txState.resultErrorHandlers, s.checkHomeDatabase)
+	}
+	return txState
+}
+
 func (s *sessionWithContext) Close(ctx context.Context) error {
 	if s.closed {
 		// Safeguard against closing more than once
@@ -714,9 +851,49 @@ func (s *sessionWithContext) Close(ctx context.Context) error {
 	<-poolCleanUpChan
 	<-routerCleanUpChan
 	s.closed = true
+	if s.span != nil {
+		s.span.End(nil, txErr)
+	}
 	return txErr
 }
 
+// startSessionSpan starts tracing the session, the operations of which are then traced as children of its span.
+func (s *sessionWithContext) startSessionSpan(ctx context.Context) {
+	if ctx == nil {
+		// the context of NewSession used to be unused, tracers must not be given a nil context
+		ctx = context.Background()
+	}
+	ctx, s.span = s.startSpan(ctx, tracing.SessionOperation, s.defaultMode, 0)
+	if s.driverConfig.Tracer != nil {
+		s.spanCtx = ctx
+	}
+}
+
+// startSpan starts tracing the given operation of the session, as a child of the session span.
+// The returned context is marked as the parent of the spans nested in the operation.
+// attempt is only reported when positive.
+func (s *sessionWithContext) startSpan(ctx context.Context, operation tracing.Operation, mode idb.AccessMode, attempt int) (context.Context, tracing.Span) {
+	if s.driverConfig.Tracer == nil {
+		return itracing.Start(ctx, nil, operation, nil)
+	}
+	ctx = itracing.WithParent(ctx, s.spanCtx)
+	attributes := map[string]any{tracing.AccessModeAttribute: "write"}
+	if mode == idb.ReadMode {
+		attributes[tracing.AccessModeAttribute] = "read"
+	}
+	if s.config.DatabaseName != idb.DefaultDatabase {
+		attributes[tracing.DatabaseAttribute] = s.config.DatabaseName
+	}
+	if s.config.ImpersonatedUser != "" {
+		attributes[tracing.ImpersonatedUserAttribute] = s.config.ImpersonatedUser
+	}
+	if attempt > 0 {
+		attributes[tracing.RetryAttemptAttribute] = attempt
+	}
+	spanCtx, span := itracing.Start(ctx, s.driverConfig.Tracer, operation, attributes)
+	return itracing.MarkParent(ctx, spanCtx), span
+}
+
 func (s *sessionWithContext) legacy() Session {
 	return &session{delegate: s}
 }
@@ -771,6 +948,18 @@ func (s *sessionWithContext) resolveHomeDatabase(ctx context.Context) error {
 		return nil
 	}
 
+	s.homeDbKey = nil
+	if key, cacheable := homeDatabaseKeyOf(ctx, s.config, s.auth); cacheable {
+		s.homeDbKey = &key
+		if database, found := s.homeDatabases.get(key); found {
+			s.log.Debugf(log.Session, s.logId, "Using cached home database '%s'", database)
+			s.config.DatabaseName = database
+			s.resolveHomeDb = false
+			s.optimisticHomeDb = true
+			return nil
+		}
+	}
+
 	bookmarks, err := s.getBookmarks(ctx)
 	if err != nil {
 		return err
@@ -787,6 +976,58 @@ func (s *sessionWithContext) resolveHomeDatabase(ctx context.Context) error {
 	s.log.Debugf(log.Session, s.logId, "Resolved home database, uses db '%s'", defaultDb)
 	s.config.DatabaseName = defaultDb
 	s.resolveHomeDb = false
+	if s.homeDbKey != nil {
+		s.homeDatabases.set(*s.homeDbKey, defaultDb)
+	}
+	return nil
+}
+
+// discardHomeDatabase invalidates the cached home database the session uses, so that it is resolved again
+func (s *sessionWithContext) discardHomeDatabase() {
+	if s.homeDbKey != nil {
+		s.homeDatabases.invalidate(*s.homeDbKey, s.config.DatabaseName)
+	}
+	s.config.DatabaseName = idb.DefaultDatabase
+	s.resolveHomeDb = true
+	s.optimisticHomeDb = false
+}
+
+// checkHomeDatabase discards the home database the session uses when the server fails a query in a way suggesting
+// the home database has moved or changed, so that it is resolved again by the next transaction
+func (s *sessionWithContext) checkHomeDatabase(err error) {
+	if !s.usesHomeDb || s.resolveHomeDb {
+		return
+	}
+	var neo4jErr *Neo4jError
+	if !errors.As(err, &neo4jErr) {
+		return
+	}
+	switch neo4jErr.Code {
+	case "Neo.ClientError.Cluster.NotALeader",
+		"Neo.ClientError.General.ForbiddenOnReadOnlyDatabase",
+		"Neo.ClientError.Database.DatabaseNotFound":
+		s.log.Infof(log.Session, s.logId, "Discarding home database '%s' after error: %s", s.config.DatabaseName, neo4jErr.Code)
+		s.discardHomeDatabase()
+	}
+}
+
+// confirmHomeDatabase checks the cached home database the session uses against the database the server reports in
+// the result summary, and replaces it when the home database has changed.
+func (s *sessionWithContext) confirmHomeDatabase(summary *resultSummary) error {
+	database := summary.sum.Database
+	if !s.optimisticHomeDb || database == "" {
+		return nil
+	}
+	s.optimisticHomeDb = false
+	if database == s.config.DatabaseName {
+		return nil
+	}
+	s.log.Infof(log.Session, s.logId, "Home database changed from '%s' to '%s'", s.config.DatabaseName, database)
+	if s.homeDbKey != nil {
+		s.homeDatabases.invalidate(*s.homeDbKey, s.config.DatabaseName)
+		s.homeDatabases.set(*s.homeDbKey, database)
+	}
+	s.config.DatabaseName = database
 	return nil
 }
 
@@ -29,9 +29,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
 	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/metrics"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/notifications"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
 )
 
 type transactionFunc func(context.Context, ManagedTransactionWork, ...func(*TransactionConfig)) (any, error)
@@ -446,6 +450,273 @@ func TestSession(outer *testing.T) {
 
 			assertTokenExpiredError(t, err)
 		})
+
+		inner.Run("Notifies driver notification handler", func(t *testing.T) {
+			var queries []string
+			conf := Config{NotificationHandler: func(query string, _ db.Notification, _ db.GqlStatusObject) {
+				queries = append(queries, query)
+			}}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			pool.BorrowConn = &ConnFake{Alive: true, ConsumeSum: &db.Summary{
+				Notifications: []db.Notification{{Code: "Neo.ClientNotification.Statement.CartesianProduct"}},
+			}}
+
+			result, err := sess.Run(context.Background(), "MATCH (n), (m) RETURN n, m", nil)
+			AssertNoError(t, err)
+			_, err = result.Consume(context.Background())
+			AssertNoError(t, err)
+			_, err = result.Consume(context.Background())
+			AssertNoError(t, err)
+
+			AssertDeepEquals(t, queries, []string{"MATCH (n), (m) RETURN n, m"})
+		})
+
+		inner.Run("Notifies session notification handler instead of driver's", func(t *testing.T) {
+			driverCalls, sessionCalls := 0, 0
+			conf := Config{NotificationHandler: func(string, db.Notification, db.GqlStatusObject) {
+				driverCalls++
+			}}
+			sessConfig := SessionConfig{NotificationHandler: func(string, db.Notification, db.GqlStatusObject) {
+				sessionCalls++
+			}}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, sessConfig, &RouterFake{}, &pool, logger, nil)
+			pool.BorrowConn = &ConnFake{Alive: true, Nexts: []Next{{Summary: &db.Summary{
+				Notifications: []db.Notification{{Code: "Neo.ClientNotification.Statement.CartesianProduct"}},
+			}}}}
+
+			_, err := sess.ExecuteRead(context.Background(), func(tx ManagedTransaction) (any, error) {
+				result, err := tx.Run(context.Background(), "MATCH (n), (m) RETURN n, m", nil)
+				if err != nil {
+					return nil, err
+				}
+				return result.Collect(context.Background())
+			})
+			AssertNoError(t, err)
+
+			AssertIntEqual(t, driverCalls, 0)
+			AssertIntEqual(t, sessionCalls, 1)
+		})
+
+		inner.Run("Fails consumption on notification matching strict policy", func(t *testing.T) {
+			conf := Config{NotificationsStrictPolicy: notifications.StrictPolicy{
+				Categories: []notifications.NotificationCategory{notifications.Performance},
+			}}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			conn := &ConnFake{Alive: true, ConsumeSum: &db.Summary{
+				Notifications: []db.Notification{{Code: "Neo.ClientNotification.Statement.CartesianProduct", Category: "PERFORMANCE"}},
+			}}
+			conn.ConsumeHook = func() {
+				conn.Bookm = "bookmark"
+			}
+			pool.BorrowConn = conn
+
+			result, err := sess.Run(context.Background(), "MATCH (n), (m) RETURN n, m", nil)
+			AssertNoError(t, err)
+			_, err = result.Consume(context.Background())
+
+			AssertTrue(t, IsNotificationError(err))
+			// the auto-commit transaction went through nonetheless
+			AssertDeepEquals(t, BookmarksToRawValues(sess.LastBookmarks()), []string{"bookmark"})
+		})
+
+		inner.Run("Fails transaction function on notification matching strict policy", func(t *testing.T) {
+			conf := Config{
+				MaxTransactionRetryTime:   3 * time.Millisecond,
+				NotificationsStrictPolicy: notifications.StrictPolicy{GqlStatuses: []string{"01N50"}},
+			}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			sess.throttleTime = time.Millisecond * 1
+			commits := 0
+			pool.BorrowConn = &ConnFake{
+				Alive: true,
+				Nexts: []Next{{Summary: &db.Summary{GqlStatusObjects: []db.GqlStatusObject{
+					{GqlStatus: "01N50", Classification: "UNRECOGNIZED", IsNotification: true},
+				}}}},
+				TxCommitHook: func() {
+					commits++
+				},
+			}
+			attempts := 0
+
+			_, err := sess.ExecuteWrite(context.Background(), func(tx ManagedTransaction) (any, error) {
+				attempts++
+				result, err := tx.Run(context.Background(), "MATCH (n:Persn) RETURN n", nil)
+				if err != nil {
+					return nil, err
+				}
+				return result.Collect(context.Background())
+			})
+
+			AssertTrue(t, IsNotificationError(err))
+			AssertIntEqual(t, attempts, 1)
+			AssertIntEqual(t, commits, 0)
+		})
+
+		inner.Run("Completes results never consumed before commit", func(t *testing.T) {
+			tracer := &TracerFake{}
+			notified := 0
+			conf := Config{Tracer: tracer, NotificationHandler: func(string, db.Notification, db.GqlStatusObject) {
+				notified++
+			}}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			record := &db.Record{Keys: []string{"n"}, Values: []any{1}}
+			pool.BorrowConn = &ConnFake{Alive: true, Nexts: []Next{{Record: record}}, ConsumeSum: &db.Summary{
+				Notifications: []db.Notification{{Code: "Neo.ClientNotification.Statement.CartesianProduct"}},
+			}}
+			tx, err := sess.BeginTransaction(context.Background())
+			AssertNoError(t, err)
+			result, err := tx.Run(context.Background(), "MATCH (n), (m) RETURN n", nil)
+			AssertNoError(t, err)
+
+			AssertNoError(t, tx.Commit(context.Background()))
+
+			AssertIntEqual(t, notified, 1)
+			query := tracer.Spans[2]
+			AssertDeepEquals(t, query.Operation, tracing.QueryOperation)
+			AssertTrue(t, query.Ended)
+			// the records have been discarded
+			records, err := result.Collect(context.Background())
+			AssertNoError(t, err)
+			AssertIntEqual(t, len(records), 0)
+			AssertIntEqual(t, notified, 1)
+		})
+
+		inner.Run("Leaves results never consumed to the commit without summary handlers nor tracer", func(t *testing.T) {
+			pool := PoolFake{}
+			sess := newSessionWithContext(&Config{}, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			consumed := 0
+			pool.BorrowConn = &ConnFake{Alive: true, ConsumeHook: func() {
+				consumed++
+			}}
+			tx, err := sess.BeginTransaction(context.Background())
+			AssertNoError(t, err)
+			_, err = tx.Run(context.Background(), "MATCH (n) RETURN n", nil)
+			AssertNoError(t, err)
+
+			AssertNoError(t, tx.Commit(context.Background()))
+
+			AssertIntEqual(t, consumed, 0)
+		})
+
+		inner.Run("Completes auto-commit result never consumed before next run", func(t *testing.T) {
+			tracer := &TracerFake{}
+			conf := Config{Tracer: tracer}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			pool.BorrowConn = &ConnFake{Alive: true, Nexts: []Next{{Summary: &db.Summary{}}}}
+			_, err := sess.Run(context.Background(), "RETURN 1", nil)
+			AssertNoError(t, err)
+
+			_, err = sess.Run(context.Background(), "RETURN 2", nil)
+			AssertNoError(t, err)
+
+			query := tracer.Spans[1]
+			AssertDeepEquals(t, query.Operation, tracing.QueryOperation)
+			AssertTrue(t, query.Ended)
+		})
+
+		inner.Run("Fails commit on never consumed result matching strict policy", func(t *testing.T) {
+			conf := Config{NotificationsStrictPolicy: notifications.StrictPolicy{GqlStatuses: []string{"01N50"}}}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			commits := 0
+			pool.BorrowConn = &ConnFake{
+				Alive: true,
+				ConsumeSum: &db.Summary{GqlStatusObjects: []db.GqlStatusObject{
+					{GqlStatus: "01N50", Classification: "UNRECOGNIZED", IsNotification: true},
+				}},
+				TxCommitHook: func() {
+					commits++
+				},
+			}
+			tx, err := sess.BeginTransaction(context.Background())
+			AssertNoError(t, err)
+			_, err = tx.Run(context.Background(), "MATCH (n:Persn) RETURN n", nil)
+			AssertNoError(t, err)
+
+			err = tx.Commit(context.Background())
+
+			AssertTrue(t, IsNotificationError(err))
+			AssertIntEqual(t, commits, 0)
+		})
+	})
+
+	outer.Run("Results of returned connections", func(inner *testing.T) {
+		// resetInBackground mimics the pool resetting returned connections in the background, racing with any result
+		// still reading from the connection
+		resetInBackground := func(pool *PoolFake, conn *ConnFake) *sync.WaitGroup {
+			resets := &sync.WaitGroup{}
+			pool.ReturnHook = func() {
+				resets.Add(1)
+				go func() {
+					defer resets.Done()
+					conn.Nexts = nil
+				}()
+			}
+			return resets
+		}
+		record := &db.Record{Keys: []string{"n"}, Values: []any{1}}
+
+		inner.Run("Fail after commit", func(t *testing.T) {
+			pool := PoolFake{}
+			sess := newSessionWithContext(&Config{}, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			conn := &ConnFake{Alive: true, Nexts: []Next{{Record: record}, {Summary: &db.Summary{}}}}
+			pool.BorrowConn = conn
+			resets := resetInBackground(&pool, conn)
+			tx, err := sess.BeginTransaction(context.Background())
+			AssertNoError(t, err)
+			result, err := tx.Run(context.Background(), "RETURN 1 AS n", nil)
+			AssertNoError(t, err)
+			AssertNoError(t, tx.Commit(context.Background()))
+
+			_, err = result.Collect(context.Background())
+
+			AssertTrue(t, IsUsageError(err))
+			resets.Wait()
+		})
+
+		inner.Run("Fail after rollback", func(t *testing.T) {
+			pool := PoolFake{}
+			sess := newSessionWithContext(&Config{}, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			conn := &ConnFake{Alive: true, Nexts: []Next{{Record: record}, {Summary: &db.Summary{}}}}
+			pool.BorrowConn = conn
+			resets := resetInBackground(&pool, conn)
+			tx, err := sess.BeginTransaction(context.Background())
+			AssertNoError(t, err)
+			result, err := tx.Run(context.Background(), "RETURN 1 AS n", nil)
+			AssertNoError(t, err)
+			AssertNoError(t, tx.Rollback(context.Background()))
+
+			_, err = result.Collect(context.Background())
+
+			AssertTrue(t, IsUsageError(err))
+			resets.Wait()
+		})
+
+		inner.Run("Fail after failed transaction function", func(t *testing.T) {
+			pool := PoolFake{}
+			sess := newSessionWithContext(&Config{}, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			conn := &ConnFake{Alive: true, Nexts: []Next{{Record: record}, {Summary: &db.Summary{}}}}
+			pool.BorrowConn = conn
+			resets := resetInBackground(&pool, conn)
+			var result ResultWithContext
+			clientErr := errors.New("client error")
+
+			_, err := sess.ExecuteWrite(context.Background(), func(tx ManagedTransaction) (any, error) {
+				result, _ = tx.Run(context.Background(), "RETURN 1 AS n", nil)
+				return nil, clientErr
+			})
+
+			AssertDeepEquals(t, err, clientErr)
+			_, err = result.Single(context.Background())
+			AssertTrue(t, IsUsageError(err))
+			resets.Wait()
+		})
 	})
 
 	outer.Run("Explicit transaction", func(inner *testing.T) {
@@ -566,6 +837,311 @@ func TestSession(outer *testing.T) {
 		})
 	})
 
+	outer.Run("Tracing", func(inner *testing.T) {
+		inner.Run("Traces auto-commit query", func(t *testing.T) {
+			tracer := &TracerFake{}
+			conf := Config{Tracer: tracer}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{DatabaseName: "movies"}, &RouterFake{}, &pool, logger, nil)
+			pool.BorrowConn = &ConnFake{Name: "server1:7687", Alive: true, ConsumeSum: &db.Summary{
+				Counters: map[string]int{"nodes-created": 1},
+			}}
+
+			result, err := sess.Run(context.Background(), "CREATE ()", nil)
+			AssertNoError(t, err)
+			_, err = result.Consume(context.Background())
+			AssertNoError(t, err)
+			AssertNoError(t, sess.Close(context.Background()))
+
+			AssertDeepEquals(t, tracer.Operations(), []tracing.Operation{
+				tracing.ConnectionAcquisitionOperation,
+				tracing.QueryOperation,
+			})
+			acquisition, query := tracer.Spans[0], tracer.Spans[1]
+			AssertTrue(t, acquisition.Ended)
+			AssertDeepEquals(t, acquisition.StartAttributes, map[string]any{
+				tracing.AccessModeAttribute: "write",
+				tracing.DatabaseAttribute:   "movies",
+			})
+			AssertDeepEquals(t, acquisition.EndAttributes, map[string]any{
+				tracing.ServerAddressAttribute: "server1:7687",
+				tracing.DatabaseAttribute:      "movies",
+			})
+			AssertTrue(t, query.Ended)
+			AssertNoError(t, query.Err)
+			AssertDeepEquals(t, query.StartAttributes, map[string]any{
+				tracing.QueryTextAttribute:     "CREATE ()",
+				tracing.ServerAddressAttribute: "server1:7687",
+				tracing.DatabaseAttribute:      "movies",
+			})
+			AssertDeepEquals(t, query.EndAttributes, map[string]any{
+				tracing.QueryCountersAttribute: map[string]int{"nodes-created": 1},
+			})
+		})
+
+		inner.Run("Traces failed query", func(t *testing.T) {
+			tracer := &TracerFake{}
+			conf := Config{Tracer: tracer}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			queryErr := &db.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"}
+			pool.BorrowConn = &ConnFake{Alive: true, Nexts: []Next{{Err: queryErr}}}
+
+			result, err := sess.Run(context.Background(), "RETURN", nil)
+			AssertNoError(t, err)
+			AssertFalse(t, result.Next(context.Background()))
+
+			query := tracer.Spans[1]
+			AssertTrue(t, query.Ended)
+			assertErrorEq(t, query.Err, queryErr)
+		})
+
+		inner.Run("Traces transaction function attempts and retry waits", func(t *testing.T) {
+			tracer := &TracerFake{}
+			conf := Config{MaxTransactionRetryTime: 1 * time.Minute, MaxConnectionPoolSize: 100, Tracer: tracer}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{ImpersonatedUser: "jane"}, &RouterFake{}, &pool, logger, nil)
+			sess.throttleTime = time.Millisecond * 1
+			pool.BorrowConn = &ConnFake{Alive: true}
+			transientErr := &db.Neo4jError{Code: "Neo.TransientError.General.MemoryPoolOutOfMemoryError"}
+			attempts := 0
+
+			_, err := sess.ExecuteRead(context.Background(), func(ManagedTransaction) (any, error) {
+				attempts++
+				if attempts == 1 {
+					return nil, transientErr
+				}
+				return nil, nil
+			})
+			AssertNoError(t, err)
+
+			AssertDeepEquals(t, tracer.Operations(), []tracing.Operation{
+				tracing.TransactionOperation,
+				tracing.ConnectionAcquisitionOperation,
+				tracing.RetryWaitOperation,
+				tracing.TransactionOperation,
+				tracing.ConnectionAcquisitionOperation,
+			})
+			firstAttempt, wait, secondAttempt := tracer.Spans[0], tracer.Spans[2], tracer.Spans[3]
+			AssertDeepEquals(t, firstAttempt.StartAttributes, map[string]any{
+				tracing.AccessModeAttribute:       "read",
+				tracing.ImpersonatedUserAttribute: "jane",
+				tracing.RetryAttemptAttribute:     1,
+			})
+			assertErrorEq(t, firstAttempt.Err, transientErr)
+			AssertDeepEquals(t, wait.StartAttributes, map[string]any{tracing.RetryAttemptAttribute: 2})
+			AssertTrue(t, wait.Ended)
+			AssertIntEqual(t, secondAttempt.StartAttributes[tracing.RetryAttemptAttribute].(int), 2)
+			AssertTrue(t, secondAttempt.Ended)
+			AssertNoError(t, secondAttempt.Err)
+		})
+
+		inner.Run("Traces explicit transaction until closure", func(t *testing.T) {
+			tracer := &TracerFake{}
+			conf := Config{Tracer: tracer}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			commitErr := errors.New("commit failed")
+			pool.BorrowConn = &ConnFake{Alive: true, TxCommitErr: commitErr}
+
+			tx, err := sess.BeginTransaction(context.Background())
+			AssertNoError(t, err)
+			transaction := tracer.Spans[0]
+			AssertFalse(t, transaction.Ended)
+
+			assertErrorEq(t, tx.Commit(context.Background()), commitErr)
+			AssertTrue(t, transaction.Ended)
+			assertErrorEq(t, transaction.Err, commitErr)
+		})
+
+		inner.Run("Traces operations as children of the session and transaction spans", func(t *testing.T) {
+			tracer := &TracerFake{}
+			conf := Config{Tracer: tracer}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{}, &RouterFake{}, &pool, logger, nil)
+			pool.BorrowConn = &ConnFake{Alive: true, ConsumeSum: &db.Summary{}}
+			sess.startSessionSpan(context.Background())
+
+			result, err := sess.Run(context.Background(), "RETURN 1", nil)
+			AssertNoError(t, err)
+			_, err = result.Consume(context.Background())
+			AssertNoError(t, err)
+			tx, err := sess.BeginTransaction(context.Background())
+			AssertNoError(t, err)
+			_, err = tx.Run(context.Background(), "RETURN 2", nil)
+			AssertNoError(t, err)
+			AssertNoError(t, tx.Commit(context.Background()))
+			AssertNoError(t, sess.Close(context.Background()))
+
+			AssertDeepEquals(t, tracer.Operations(), []tracing.Operation{
+				tracing.SessionOperation,
+				tracing.ConnectionAcquisitionOperation,
+				tracing.QueryOperation,
+				tracing.TransactionOperation,
+				tracing.ConnectionAcquisitionOperation,
+				tracing.QueryOperation,
+			})
+			session, transaction := tracer.Spans[0], tracer.Spans[3]
+			AssertNil(t, session.Parent)
+			AssertTrue(t, session.Ended)
+			AssertTrue(t, tracer.Spans[1].Parent == session)
+			AssertTrue(t, tracer.Spans[2].Parent == session)
+			AssertTrue(t, transaction.Parent == session)
+			AssertTrue(t, tracer.Spans[4].Parent == transaction)
+			AssertTrue(t, tracer.Spans[5].Parent == transaction)
+		})
+
+		inner.Run("Traces sessions created without context", func(t *testing.T) {
+			tracer := &TracerFake{}
+			conf := Config{Tracer: tracer}
+			sess := newSessionWithContext(&conf, SessionConfig{}, &RouterFake{}, &PoolFake{}, logger, nil)
+			var noCtx context.Context
+
+			sess.startSessionSpan(noCtx)
+
+			AssertDeepEquals(t, tracer.Operations(), []tracing.Operation{tracing.SessionOperation})
+		})
+
+		inner.Run("Traces nothing without tracer", func(t *testing.T) {
+			_, pool, sess := createSession()
+			pool.BorrowConn = &ConnFake{Alive: true, ConsumeSum: &db.Summary{}}
+
+			result, err := sess.Run(context.Background(), "RETURN 1", nil)
+			AssertNoError(t, err)
+			_, err = result.Consume(context.Background())
+			AssertNoError(t, err)
+			AssertNoError(t, sess.Close(context.Background()))
+		})
+	})
+
+	outer.Run("Metrics", func(inner *testing.T) {
+		inner.Run("Measures queries by API and database", func(t *testing.T) {
+			_, pool, sess := createSession()
+			sess.stats = newDriverStats()
+			conn := &ConnFake{Alive: true, ConsumeSum: &db.Summary{}}
+			conn.SelectDatabase("movies")
+			pool.BorrowConn = conn
+
+			result, err := sess.Run(context.Background(), "RETURN 1", nil)
+			AssertNoError(t, err)
+			_, err = result.Consume(context.Background())
+			AssertNoError(t, err)
+			conn.RunErr = errors.New("oopsie")
+			_, err = sess.Run(context.Background(), "RETURN 1", nil)
+			AssertError(t, err)
+
+			driverMetrics := metrics.DriverMetrics{}
+			sess.stats.snapshot(&driverMetrics)
+			AssertLen(t, driverMetrics.Queries, 1)
+			queryMetrics := driverMetrics.Queries[metrics.QueryKey{API: metrics.AutoCommitTransactionAPI, Database: "movies"}]
+			AssertDeepEquals(t, queryMetrics.Succeeded, int64(1))
+			AssertDeepEquals(t, queryMetrics.Failed, int64(1))
+			AssertDeepEquals(t, queryMetrics.Latency.Count, uint64(1))
+		})
+
+		inner.Run("Counts transaction function retries", func(t *testing.T) {
+			_, pool, sess := createSession()
+			sess.stats = newDriverStats()
+			sess.driverConfig.MaxTransactionRetryTime = 1 * time.Minute
+			pool.BorrowConn = &ConnFake{Alive: true}
+			transientErr := &db.Neo4jError{Code: "Neo.TransientError.General.MemoryPoolOutOfMemoryError"}
+			attempts := 0
+
+			_, err := sess.ExecuteRead(context.Background(), func(ManagedTransaction) (any, error) {
+				attempts++
+				if attempts < 3 {
+					return nil, transientErr
+				}
+				return nil, nil
+			})
+			AssertNoError(t, err)
+			_, err = sess.ExecuteRead(context.Background(), func(ManagedTransaction) (any, error) {
+				return nil, errors.New("not retried")
+			})
+			AssertError(t, err)
+
+			driverMetrics := metrics.DriverMetrics{}
+			sess.stats.snapshot(&driverMetrics)
+			AssertDeepEquals(t, driverMetrics.Transactions, map[string]metrics.TransactionMetrics{
+				"": {Executions: 2, Retries: 2, Failures: 1},
+			})
+		})
+	})
+
+	outer.Run("Slow queries", func(inner *testing.T) {
+		inner.Run("Reports queries exceeding the threshold", func(t *testing.T) {
+			var slowQueries []config.SlowQuery
+			conf := Config{SlowQueryThreshold: time.Nanosecond, SlowQueryHandler: func(query config.SlowQuery) {
+				slowQueries = append(slowQueries, query)
+			}}
+			pool := PoolFake{}
+			sess := newSessionWithContext(&conf, SessionConfig{DatabaseName: "movies"}, &RouterFake{}, &pool, logger, nil)
+			pool.BorrowConn = &ConnFake{Name: "server1:7687", Alive: true, ConsumeSum: &db.Summary{}}
+
+			result, err := sess.Run(context.Background(), "CREATE (:Movie {title: $title})",
+				map[string]any{"title": "The Matrix", "year": nil},
+				WithTxMetadata(map[string]any{"app": "movies"}))
+			AssertNoError(t, err)
+			_, err = result.Consume(context.Background())
+			AssertNoError(t, err)
+
+			AssertLen(t, slowQueries, 1)
+			slowQuery := slowQueries[0]
+			AssertStringEqual(t, slowQuery.Query, "CREATE (:Movie {title: $title})")
+			AssertDeepEquals(t, slowQuery.Parameters, map[string]string{"title": "string", "year": "nil"})
+			AssertDeepEquals(t, slowQuery.TransactionMetadata, map[string]any{"app": "movies"})
+			AssertStringEqual(t, slowQuery.Database, "movies")
+			AssertStringEqual(t, slowQuery.Server, "server1:7687")
+			AssertDeepEquals(t, slowQuery.Total,
+				slowQuery.ConnectionAcquisition+slowQuery.ResultAvailableAfter+slowQuery.ResultConsumption)
+			AssertNoError(t, slowQuery.Err)
+		})
+
+		inner.Run("Accounts connection acquisition to the first query of a transaction only", func(t *testing.T) {
+			var slowQueries []config.SlowQuery
+			_, pool, sess := createSession()
+			sess.driverConfig.SlowQueryThreshold = time.Nanosecond
+			sess.driverConfig.SlowQueryHandler = func(query config.SlowQuery) {
+				slowQueries = append(slowQueries, query)
+			}
+			pool.BorrowConn = &ConnFake{Alive: true, ConsumeSum: &db.Summary{}}
+
+			_, err := sess.ExecuteWrite(context.Background(), func(tx ManagedTransaction) (any, error) {
+				for i := 0; i < 2; i++ {
+					result, err := tx.Run(context.Background(), "RETURN 1", nil)
+					if err != nil {
+						return nil, err
+					}
+					if _, err = result.Consume(context.Background()); err != nil {
+						return nil, err
+					}
+				}
+				return nil, nil
+			})
+			AssertNoError(t, err)
+
+			AssertLen(t, slowQueries, 2)
+			AssertDeepEquals(t, slowQueries[1].ConnectionAcquisition, time.Duration(0))
+		})
+
+		inner.Run("Ignores queries below the threshold", func(t *testing.T) {
+			reported := false
+			_, pool, sess := createSession()
+			sess.driverConfig.SlowQueryThreshold = time.Hour
+			sess.driverConfig.SlowQueryHandler = func(config.SlowQuery) {
+				reported = true
+			}
+			pool.BorrowConn = &ConnFake{Alive: true, ConsumeSum: &db.Summary{}}
+
+			result, err := sess.Run(context.Background(), "RETURN 1", nil)
+			AssertNoError(t, err)
+			_, err = result.Consume(context.Background())
+			AssertNoError(t, err)
+
+			AssertFalse(t, reported)
+		})
+	})
+
 	outer.Run("GetServerInfo", func(inner *testing.T) {
 
 		inner.Run("Retrieves info from first borrowed connection", func(t *testing.T) {
@@ -641,6 +1217,208 @@ func TestSession(outer *testing.T) {
 		})
 	})
 
+	outer.Run("Home database cache", func(inner *testing.T) {
+		const homeDb = "mydb"
+		newSession := func(sessConfig SessionConfig, homeDatabases *homeDatabaseCache) (*RouterFake, *PoolFake, *sessionWithContext) {
+			router, pool, sess := createSessionFromConfig(sessConfig)
+			sess.homeDatabases = homeDatabases
+			return router, pool, sess
+		}
+
+		inner.Run("Resolves home database once per user", func(t *testing.T) {
+			homeDatabases := newHomeDatabaseCache()
+			lookups := 0
+			for _, user := range []string{"jane", "jane", "john"} {
+				router, pool, sess := newSession(SessionConfig{ImpersonatedUser: user}, homeDatabases)
+				router.GetNameOfDefaultDbHook = func(string) (string, error) {
+					lookups++
+					return homeDb, nil
+				}
+				pool.BorrowConn = &ConnFake{}
+
+				_, err := sess.Run(context.Background(), "cypher", nil)
+				AssertNoError(t, err)
+			}
+			AssertIntEqual(t, lookups, 2)
+		})
+
+		inner.Run("Routes optimistically with cached home database until confirmed", func(t *testing.T) {
+			homeDatabases := newHomeDatabaseCache()
+			homeDatabases.set(homeDatabaseKey{}, homeDb)
+			router, pool, sess := newSession(SessionConfig{AccessMode: AccessModeRead}, homeDatabases)
+			router.GetNameOfDefaultDbHook = func(string) (string, error) {
+				t.Error("home database should not be resolved")
+				return "", nil
+			}
+			router.GetOrUpdateReadersHook = func(_ func(context.Context) ([]string, error), database string) ([]string, error) {
+				AssertStringEqual(t, database, homeDb)
+				return []string{"aserver"}, nil
+			}
+			conn := &ConnFake{ConsumeSum: &db.Summary{Database: homeDb}}
+			pool.BorrowConn = conn
+
+			result, err := sess.Run(context.Background(), "cypher", nil)
+			AssertNoError(t, err)
+			AssertStringEqual(t, conn.DatabaseName, "")
+			_, err = result.Consume(context.Background())
+			AssertNoError(t, err)
+
+			conn = &ConnFake{}
+			pool.BorrowConn = conn
+			_, err = sess.Run(context.Background(), "cypher", nil)
+			AssertNoError(t, err)
+			AssertStringEqual(t, conn.DatabaseName, homeDb)
+		})
+
+		inner.Run("Replaces cached home database reported different by the server", func(t *testing.T) {
+			homeDatabases := newHomeDatabaseCache()
+			homeDatabases.set(homeDatabaseKey{impersonatedUser: "jane"}, "olddb")
+			_, pool, sess := newSession(SessionConfig{ImpersonatedUser: "jane"}, homeDatabases)
+			pool.BorrowConn = &ConnFake{ConsumeSum: &db.Summary{Database: homeDb}}
+
+			result, err := sess.Run(context.Background(), "cypher", nil)
+			AssertNoError(t, err)
+			_, err = result.Consume(context.Background())
+			AssertNoError(t, err)
+
+			AssertStringEqual(t, sess.config.DatabaseName, homeDb)
+			database, _ := homeDatabases.get(homeDatabaseKey{impersonatedUser: "jane"})
+			AssertStringEqual(t, database, homeDb)
+		})
+
+		inner.Run("Resolves home database again on routing error", func(t *testing.T) {
+			homeDatabases := newHomeDatabaseCache()
+			homeDatabases.set(homeDatabaseKey{}, "olddb")
+			router, pool, sess := newSession(SessionConfig{AccessMode: AccessModeRead}, homeDatabases)
+			router.GetNameOfDefaultDbHook = func(string) (string, error) {
+				return homeDb, nil
+			}
+			router.GetOrUpdateReadersHook = func(_ func(context.Context) ([]string, error), database string) ([]string, error) {
+				if database == "olddb" {
+					return nil, &db.Neo4jError{Code: "Neo.ClientError.Database.DatabaseNotFound"}
+				}
+				return []string{"aserver"}, nil
+			}
+			conn := &ConnFake{}
+			pool.BorrowConn = conn
+
+			_, err := sess.Run(context.Background(), "cypher", nil)
+			AssertNoError(t, err)
+			AssertStringEqual(t, conn.DatabaseName, homeDb)
+			database, _ := homeDatabases.get(homeDatabaseKey{})
+			AssertStringEqual(t, database, homeDb)
+		})
+
+		inner.Run("Resolves home database again after auto-commit run errors", func(t *testing.T) {
+			homeDatabases := newHomeDatabaseCache()
+			homeDatabases.set(homeDatabaseKey{}, "olddb")
+			router, pool, sess := newSession(SessionConfig{}, homeDatabases)
+			lookups := 0
+			router.GetNameOfDefaultDbHook = func(string) (string, error) {
+				lookups++
+				return homeDb, nil
+			}
+			pool.BorrowConn = &ConnFake{RunErr: &db.Neo4jError{Code: "Neo.ClientError.Database.DatabaseNotFound"}}
+
+			_, err := sess.Run(context.Background(), "cypher", nil)
+			AssertError(t, err)
+			_, found := homeDatabases.get(homeDatabaseKey{})
+			AssertFalse(t, found)
+
+			conn := &ConnFake{}
+			pool.BorrowConn = conn
+			_, err = sess.Run(context.Background(), "cypher", nil)
+			AssertNoError(t, err)
+			AssertIntEqual(t, lookups, 1)
+			AssertStringEqual(t, conn.DatabaseName, homeDb)
+		})
+
+		inner.Run("Discards cached home database after explicit transaction commit errors", func(t *testing.T) {
+			homeDatabases := newHomeDatabaseCache()
+			homeDatabases.set(homeDatabaseKey{}, "olddb")
+			_, pool, sess := newSession(SessionConfig{}, homeDatabases)
+			pool.BorrowConn = &ConnFake{TxCommitErr: &db.Neo4jError{Code: "Neo.ClientError.General.ForbiddenOnReadOnlyDatabase"}}
+			tx, err := sess.BeginTransaction(context.Background())
+			AssertNoError(t, err)
+
+			err = tx.Commit(context.Background())
+
+			AssertError(t, err)
+			_, found := homeDatabases.get(homeDatabaseKey{})
+			AssertFalse(t, found)
+			AssertTrue(t, sess.resolveHomeDb)
+		})
+
+		inner.Run("Discards cached home database after transaction function commit errors", func(t *testing.T) {
+			homeDatabases := newHomeDatabaseCache()
+			homeDatabases.set(homeDatabaseKey{}, "olddb")
+			router, pool, sess := newSession(SessionConfig{}, homeDatabases)
+			router.GetNameOfDefaultDbHook = func(string) (string, error) {
+				return homeDb, nil
+			}
+			pool.BorrowConn = &ConnFake{TxCommitErr: &db.Neo4jError{Code: "Neo.ClientError.Cluster.NotALeader"}}
+
+			_, err := sess.ExecuteWrite(context.Background(), func(ManagedTransaction) (any, error) {
+				return nil, nil
+			})
+
+			AssertError(t, err)
+			if database, _ := homeDatabases.get(homeDatabaseKey{}); database == "olddb" {
+				t.Errorf("expected home database %s to be discarded", database)
+			}
+		})
+
+		inner.Run("Keeps cached home database after other errors", func(t *testing.T) {
+			homeDatabases := newHomeDatabaseCache()
+			homeDatabases.set(homeDatabaseKey{}, homeDb)
+			_, pool, sess := newSession(SessionConfig{}, homeDatabases)
+			pool.BorrowConn = &ConnFake{RunErr: &db.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"}}
+
+			_, err := sess.Run(context.Background(), "cypher", nil)
+
+			AssertError(t, err)
+			database, _ := homeDatabases.get(homeDatabaseKey{})
+			AssertStringEqual(t, database, homeDb)
+			AssertFalse(t, sess.resolveHomeDb)
+		})
+
+		inner.Run("Resolves home database once per principal of the driver credentials", func(t *testing.T) {
+			homeDatabases := newHomeDatabaseCache()
+			lookups := 0
+			// A token manager rotating the credentials to another user
+			for _, user := range []string{"jane", "jane", "john"} {
+				router, pool, sess := newSession(SessionConfig{}, homeDatabases)
+				sess.auth = &idb.ReAuthToken{Manager: BasicAuth(user, "pass", "")}
+				router.GetNameOfDefaultDbHook = func(string) (string, error) {
+					lookups++
+					return homeDb, nil
+				}
+				pool.BorrowConn = &ConnFake{}
+
+				_, err := sess.Run(context.Background(), "cypher", nil)
+				AssertNoError(t, err)
+			}
+			AssertIntEqual(t, lookups, 2)
+		})
+
+		inner.Run("Does not cache home database of session credentials without principal", func(t *testing.T) {
+			homeDatabases := newHomeDatabaseCache()
+			lookups := 0
+			for i := 0; i < 2; i++ {
+				router, pool, sess := newSession(SessionConfig{Auth: &AuthToken{Tokens: map[string]any{"scheme": "bearer", "credentials": "token"}}}, homeDatabases)
+				router.GetNameOfDefaultDbHook = func(string) (string, error) {
+					lookups++
+					return homeDb, nil
+				}
+				pool.BorrowConn = &ConnFake{}
+
+				_, err := sess.Run(context.Background(), "cypher", nil)
+				AssertNoError(t, err)
+			}
+			AssertIntEqual(t, lookups, 2)
+		})
+	})
+
 	outer.Run("Close", func(ct *testing.T) {
 		ct.Run("Cleans up connection pool async", func(t *testing.T) {
 			_, pool, sess := createSession()
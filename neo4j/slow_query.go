@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
+	itime "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/time"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/log"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
+)
+
+// slowQueryLog reports the queries whose duration exceeds the configured threshold.
+// A nil *slowQueryLog reports nothing.
+type slowQueryLog struct {
+	threshold time.Duration
+	handler   config.SlowQueryHandler
+	log       log.Logger
+	logId     string
+}
+
+func newSlowQueryLog(driverConfig *Config, logger log.Logger, logId string) *slowQueryLog {
+	if driverConfig.SlowQueryThreshold <= 0 {
+		return nil
+	}
+	return &slowQueryLog{
+		threshold: driverConfig.SlowQueryThreshold,
+		handler:   driverConfig.SlowQueryHandler,
+		log:       logger,
+		logId:     logId,
+	}
+}
+
+// watchQuery wraps the span of a query so that the query is reported when it ends, if it turns out to be slow.
+// See resultWithContext.trackQuery for how the time until the result is available is recorded.
+func (l *slowQueryLog) watchQuery(span tracing.Span, query config.SlowQuery) tracing.Span {
+	if l == nil {
+		return span
+	}
+	return &slowQuerySpan{delegate: span, log: l, query: query, start: itime.Now()}
+}
+
+func (l *slowQueryLog) report(query config.SlowQuery) {
+	if query.Total <= l.threshold {
+		return
+	}
+	if l.handler != nil {
+		l.handler(query)
+		return
+	}
+	database := query.Database
+	if database == "" {
+		database = "<default>"
+	}
+	l.log.Warnf(log.Session, l.logId,
+		"slow query took %s (connection acquisition: %s, result available after: %s, result consumption: %s) "+
+			"on server %s and database %s: %q, parameters: %v, transaction metadata: %v, error: %v",
+		query.Total, query.ConnectionAcquisition, query.ResultAvailableAfter, query.ResultConsumption,
+		query.Server, database, query.Query, query.Parameters, query.TransactionMetadata, query.Err)
+}
+
+type slowQuerySpan struct {
+	delegate        tracing.Span
+	log             *slowQueryLog
+	query           config.SlowQuery
+	start           time.Time
+	resultAvailable time.Time
+}
+
+func (s *slowQuerySpan) onResultAvailable() {
+	s.resultAvailable = itime.Now()
+}
+
+func (s *slowQuerySpan) End(attributes map[string]any, err error) {
+	end := itime.Now()
+	if s.resultAvailable.IsZero() {
+		// the query failed before its result was available
+		s.resultAvailable = end
+	}
+	s.query.ResultAvailableAfter = s.resultAvailable.Sub(s.start)
+	s.query.ResultConsumption = end.Sub(s.resultAvailable)
+	s.query.Total = s.query.ConnectionAcquisition + s.query.ResultAvailableAfter + s.query.ResultConsumption
+	s.query.Err = err
+	s.log.report(s.query)
+	s.delegate.End(attributes, err)
+}
+
+// sanitizeParameters associates each parameter name with the type of its value, leaving out the value itself
+func sanitizeParameters(params map[string]any) map[string]string {
+	if params == nil {
+		return nil
+	}
+	result := make(map[string]string, len(params))
+	for name, value := range params {
+		if value == nil {
+			result[name] = "nil"
+			continue
+		}
+		result[name] = fmt.Sprintf("%T", value)
+	}
+	return result
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package otel reports the operations traced by the driver as OpenTelemetry spans.
+//
+// Spans follow the OpenTelemetry semantic conventions for database client calls: db.system.name is always "neo4j",
+// the target database is reported as db.namespace, query texts as db.query.text and servers as server.address and
+// server.port.
+// Attributes without a standard equivalent, such as the retry attempt or the query counters, keep the names defined
+// by the tracing package.
+//
+// The package is a separate module, so that applications not using OpenTelemetry do not depend on it. It is released
+// together with the driver and builds against the driver sources of the same revision.
+//
+// Example:
+//
+//	driver, err := neo4j.NewDriverWithContext(uri, auth, func(config *config.Config) {
+//		config.Tracer = otel.NewTracer(otel.WithTracerProvider(provider))
+//	})
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
+	otelapi "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the spans.
+const ScopeName = "github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing/otel"
+
+const (
+	dbSystemNameKey  = attribute.Key("db.system.name")
+	dbNamespaceKey   = attribute.Key("db.namespace")
+	dbQueryTextKey   = attribute.Key("db.query.text")
+	serverAddressKey = attribute.Key("server.address")
+	serverPortKey    = attribute.Key("server.port")
+	errorTypeKey     = attribute.Key("error.type")
+	dbStatusCodeKey  = attribute.Key("db.response.status_code")
+)
+
+// Option configures the Tracer returned by NewTracer.
+type Option func(*options)
+
+type options struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the provider of the OpenTelemetry tracer.
+// The global provider is used by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(o *options) {
+		o.provider = provider
+	}
+}
+
+// NewTracer returns a tracing.Tracer creating OpenTelemetry spans.
+func NewTracer(opts ...Option) tracing.Tracer {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.provider == nil {
+		o.provider = otelapi.GetTracerProvider()
+	}
+	return &tracer{delegate: o.provider.Tracer(ScopeName)}
+}
+
+type tracer struct {
+	delegate trace.Tracer
+}
+
+func (t *tracer) Start(ctx context.Context, operation tracing.Operation, attributes map[string]any) (context.Context, tracing.Span) {
+	ctx, otelSpan := t.delegate.Start(ctx, spanName(operation, attributes),
+		trace.WithSpanKind(spanKind(operation)),
+		trace.WithAttributes(dbSystemNameKey.String("neo4j")),
+		trace.WithAttributes(convert(attributes)...))
+	return ctx, &span{delegate: otelSpan}
+}
+
+type span struct {
+	delegate trace.Span
+}
+
+func (s *span) End(attributes map[string]any, err error) {
+	s.delegate.SetAttributes(convert(attributes)...)
+	if err != nil {
+		s.delegate.RecordError(err)
+		s.delegate.SetStatus(codes.Error, err.Error())
+		var neo4jErr *db.Neo4jError
+		if errors.As(err, &neo4jErr) {
+			s.delegate.SetAttributes(errorTypeKey.String(neo4jErr.Code), dbStatusCodeKey.String(neo4jErr.Code))
+		} else {
+			s.delegate.SetAttributes(errorTypeKey.String(fmt.Sprintf("%T", err)))
+		}
+	}
+	s.delegate.End()
+}
+
+// spanName follows the conventions for database spans when a query runs, i.e. the span is named after the target
+// database, if known, or the database system otherwise.
+func spanName(operation tracing.Operation, attributes map[string]any) string {
+	if operation != tracing.QueryOperation {
+		return string(operation)
+	}
+	if database, ok := attributes[tracing.DatabaseAttribute].(string); ok && database != "" {
+		return database
+	}
+	return "neo4j"
+}
+
+func spanKind(operation tracing.Operation) trace.SpanKind {
+	switch operation {
+	case tracing.QueryOperation, tracing.ConnectionAcquisitionOperation, tracing.RoutingTableRefreshOperation:
+		return trace.SpanKindClient
+	default:
+		return trace.SpanKindInternal
+	}
+}
+
+func convert(attributes map[string]any) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, 0, len(attributes))
+	for key, value := range attributes {
+		switch key {
+		case tracing.DatabaseAttribute:
+			result = append(result, dbNamespaceKey.String(fmt.Sprint(value)))
+		case tracing.QueryTextAttribute:
+			result = append(result, dbQueryTextKey.String(fmt.Sprint(value)))
+		case tracing.ServerAddressAttribute:
+			result = append(result, serverAttributes(fmt.Sprint(value))...)
+		case tracing.QueryCountersAttribute:
+			counters, _ := value.(map[string]int)
+			for counter, count := range counters {
+				result = append(result, attribute.Int(key+"."+counter, count))
+			}
+		default:
+			result = append(result, toKeyValue(key, value))
+		}
+	}
+	return result
+}
+
+func serverAttributes(address string) []attribute.KeyValue {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return []attribute.KeyValue{serverAddressKey.String(address)}
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		return []attribute.KeyValue{serverAddressKey.String(host)}
+	}
+	return []attribute.KeyValue{serverAddressKey.String(host), serverPortKey.Int(portNumber)}
+}
+
+func toKeyValue(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/db"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(outer *testing.T) {
+	outer.Parallel()
+
+	newTracer := func() (tracing.Tracer, *tracetest.SpanRecorder) {
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		return NewTracer(WithTracerProvider(provider)), recorder
+	}
+
+	outer.Run("maps queries onto database client spans", func(t *testing.T) {
+		tracer, recorder := newTracer()
+
+		_, span := tracer.Start(context.Background(), tracing.QueryOperation, map[string]any{
+			tracing.DatabaseAttribute:      "movies",
+			tracing.QueryTextAttribute:     "CREATE ()",
+			tracing.ServerAddressAttribute: "server1:7687",
+		})
+		span.End(map[string]any{tracing.QueryCountersAttribute: map[string]int{"nodes-created": 1}}, nil)
+
+		spans := recorder.Ended()
+		if len(spans) != 1 {
+			t.Fatalf("expected 1 span, got %d", len(spans))
+		}
+		if spans[0].Name() != "movies" {
+			t.Errorf("unexpected span name %q", spans[0].Name())
+		}
+		if spans[0].SpanKind() != trace.SpanKindClient {
+			t.Errorf("unexpected span kind %v", spans[0].SpanKind())
+		}
+		assertAttributes(t, spans[0].Attributes(), map[attribute.Key]attribute.Value{
+			"db.system.name":                     attribute.StringValue("neo4j"),
+			"db.namespace":                       attribute.StringValue("movies"),
+			"db.query.text":                      attribute.StringValue("CREATE ()"),
+			"server.address":                     attribute.StringValue("server1"),
+			"server.port":                        attribute.IntValue(7687),
+			"neo4j.query.counters.nodes-created": attribute.IntValue(1),
+		})
+	})
+
+	outer.Run("records errors", func(t *testing.T) {
+		tracer, recorder := newTracer()
+
+		_, span := tracer.Start(context.Background(), tracing.TransactionOperation, map[string]any{
+			tracing.RetryAttemptAttribute: 2,
+		})
+		span.End(nil, &db.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected", Msg: "deadlock"})
+
+		spans := recorder.Ended()
+		if spans[0].Name() != string(tracing.TransactionOperation) {
+			t.Errorf("unexpected span name %q", spans[0].Name())
+		}
+		if spans[0].SpanKind() != trace.SpanKindInternal {
+			t.Errorf("unexpected span kind %v", spans[0].SpanKind())
+		}
+		if spans[0].Status().Code != codes.Error {
+			t.Errorf("expected error status, got %v", spans[0].Status())
+		}
+		assertAttributes(t, spans[0].Attributes(), map[attribute.Key]attribute.Value{
+			"db.system.name":          attribute.StringValue("neo4j"),
+			"neo4j.retry.attempt":     attribute.IntValue(2),
+			"error.type":              attribute.StringValue("Neo.TransientError.Transaction.DeadlockDetected"),
+			"db.response.status_code": attribute.StringValue("Neo.TransientError.Transaction.DeadlockDetected"),
+		})
+	})
+}
+
+func assertAttributes(t *testing.T, actual []attribute.KeyValue, expected map[attribute.Key]attribute.Value) {
+	t.Helper()
+	if len(actual) != len(expected) {
+		t.Errorf("expected %d attributes, got %v", len(expected), actual)
+	}
+	for _, kv := range actual {
+		if value, ok := expected[kv.Key]; !ok || value != kv.Value {
+			t.Errorf("unexpected attribute %s=%v", kv.Key, kv.Value.Emit())
+		}
+	}
+}
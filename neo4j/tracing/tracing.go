@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+// Package tracing defines the hooks the driver calls to trace its operations, such as sessions, transactions,
+// queries and connection acquisitions.
+//
+// Nothing is traced unless a Tracer is set in config.Config.
+package tracing
+
+import "context"
+
+// Tracer is called by the driver every time it starts one of the traced operations.
+type Tracer interface {
+	// Start is called when the driver starts the given operation.
+	// ctx is the context the operation runs with. The returned context is passed to the operations nested in this
+	// one, when possible, so that implementations can propagate the current span.
+	// attributes describes the operation, see the Attribute constants for possible keys.
+	// The returned Span must not be nil.
+	Start(ctx context.Context, operation Operation, attributes map[string]any) (context.Context, Span)
+}
+
+// Span represents an operation started by Tracer.
+type Span interface {
+	// End is called once when the operation completes, with err set if the operation failed.
+	// attributes holds values that are only known upon completion, it may be nil.
+	End(attributes map[string]any, err error)
+}
+
+// Operation identifies the kind of operation being traced.
+type Operation string
+
+const (
+	// SessionOperation spans from the creation of a session to its closure.
+	SessionOperation Operation = "neo4j.session"
+	// TransactionOperation spans an explicit transaction, or a single attempt of a transaction function (see
+	// RetryAttemptAttribute).
+	TransactionOperation Operation = "neo4j.transaction"
+	// QueryOperation spans from the execution of a query until its result summary is received.
+	QueryOperation Operation = "neo4j.query"
+	// ConnectionAcquisitionOperation spans the acquisition of a connection from the pool, including the resolution
+	// of the home database and the retrieval of the routing table.
+	ConnectionAcquisitionOperation Operation = "neo4j.connection.acquisition"
+	// RoutingTableRefreshOperation spans the retrieval of a new routing table.
+	RoutingTableRefreshOperation Operation = "neo4j.routing_table.refresh"
+	// RetryWaitOperation spans the wait between two attempts of a transaction function.
+	RetryWaitOperation Operation = "neo4j.retry.wait"
+)
+
+// Attribute keys
+const (
+	// DatabaseAttribute is the name of the target database, if known.
+	DatabaseAttribute = "neo4j.database"
+	// ImpersonatedUserAttribute is the impersonated user, if any.
+	ImpersonatedUserAttribute = "neo4j.impersonated_user"
+	// AccessModeAttribute is either "read" or "write".
+	AccessModeAttribute = "neo4j.access_mode"
+	// ServerAddressAttribute is the address of the server the operation ran against.
+	ServerAddressAttribute = "neo4j.server.address"
+	// QueryTextAttribute is the text of the query.
+	// See RedactQueries for removing sensitive information from it.
+	QueryTextAttribute = "neo4j.query.text"
+	// QueryCountersAttribute holds the update statistics of a query, as a map[string]int (see db.Summary Counters).
+	QueryCountersAttribute = "neo4j.query.counters"
+	// RetryAttemptAttribute is the attempt number of a transaction function, starting at 1.
+	RetryAttemptAttribute = "neo4j.retry.attempt"
+)
+
+// RedactQueries returns a Tracer that replaces the text of queries with the result of redact, before delegating
+// to tracer.
+func RedactQueries(tracer Tracer, redact func(query string) string) Tracer {
+	return &redactingTracer{delegate: tracer, redact: redact}
+}
+
+type redactingTracer struct {
+	delegate Tracer
+	redact   func(string) string
+}
+
+func (r *redactingTracer) Start(ctx context.Context, operation Operation, attributes map[string]any) (context.Context, Span) {
+	if query, ok := attributes[QueryTextAttribute].(string); ok {
+		attributes[QueryTextAttribute] = r.redact(query)
+	}
+	return r.delegate.Start(ctx, operation, attributes)
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [https://neo4j.com]
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/testutil"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
+)
+
+func TestRedactQueries(outer *testing.T) {
+	outer.Parallel()
+
+	redact := func(string) string {
+		return "<redacted>"
+	}
+
+	outer.Run("redacts query text", func(t *testing.T) {
+		delegate := &TracerFake{}
+		tracer := tracing.RedactQueries(delegate, redact)
+
+		_, span := tracer.Start(context.Background(), tracing.QueryOperation, map[string]any{
+			tracing.QueryTextAttribute:     "MATCH (u:User {password: 'secret'}) RETURN u",
+			tracing.ServerAddressAttribute: "localhost:7687",
+		})
+		span.End(nil, nil)
+
+		AssertLen(t, delegate.Spans, 1)
+		AssertDeepEquals(t, delegate.Spans[0].StartAttributes, map[string]any{
+			tracing.QueryTextAttribute:     "<redacted>",
+			tracing.ServerAddressAttribute: "localhost:7687",
+		})
+		AssertTrue(t, delegate.Spans[0].Ended)
+	})
+
+	outer.Run("leaves other operations untouched", func(t *testing.T) {
+		delegate := &TracerFake{}
+		tracer := tracing.RedactQueries(delegate, redact)
+
+		tracer.Start(context.Background(), tracing.SessionOperation, nil)
+
+		AssertLen(t, delegate.Spans, 1)
+		AssertDeepEquals(t, delegate.Spans[0].Operation, tracing.SessionOperation)
+		AssertNil(t, delegate.Spans[0].StartAttributes)
+	})
+}
@@ -19,8 +19,13 @@ package neo4j
 
 import (
 	"context"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/config"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/db"
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/errorutil"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/telemetry"
+	itracing "github.com/neo4j/neo4j-go-driver/v5/neo4j/internal/tracing"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/tracing"
+	"time"
 )
 
 // ManagedTransaction represents a transaction managed by the driver and operated on by the user, via transaction functions
@@ -55,6 +60,17 @@ type ExplicitTransaction interface {
 type transactionState struct {
 	err                 error
 	resultErrorHandlers []func(error)
+	summaryHandlers     []func(*resultSummary) error
+	tracer              tracing.Tracer
+	// spanCtx is the parent of the spans of the queries, nil when not traced
+	spanCtx     context.Context
+	stats       *driverStats
+	api         telemetry.API
+	slowQueries *slowQueryLog
+	metadata    map[string]any
+	// acquisition is the time spent acquiring the connection, accounted to the first query only
+	acquisition time.Duration
+	results     []*resultWithContext
 }
 
 func (t *transactionState) onError(err error) {
@@ -64,6 +80,83 @@ func (t *transactionState) onError(err error) {
 	}
 }
 
+// consumeResults discards the remaining records of the results of the transaction before the transaction commits,
+// so that their summaries are handled even if they are not consumed. Without summary handlers nor tracer, the results
+// are left for the connection to discard upon commit.
+// Returns the first error of the results, e.g. a summary rejected by the strict notification policy.
+func (t *transactionState) consumeResults(ctx context.Context) error {
+	if len(t.summaryHandlers) == 0 && t.tracer == nil {
+		for _, result := range t.results {
+			// the query completes along with the transaction
+			result.endSpan(nil, nil)
+		}
+		return nil
+	}
+	var err error
+	for _, result := range t.results {
+		if result.err != nil {
+			// already reported through the result
+			continue
+		}
+		result.discard(ctx)
+		if err == nil {
+			err = result.err
+		}
+	}
+	return err
+}
+
+// detachResults detaches the results of the transaction from its connection, which is about to be returned to the
+// pool. Results not buffered yet fail, see resultWithContext.detach.
+func (t *transactionState) detachResults() {
+	for _, result := range t.results {
+		result.detach(&UsageError{Message: resultFailedError})
+	}
+}
+
+func (t *transactionState) onSummary(summary *resultSummary) error {
+	for _, summaryHandler := range t.summaryHandlers {
+		if err := summaryHandler(summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startQuery starts tracing and measuring the given query, about to run on the given connection
+func (t *transactionState) startQuery(ctx context.Context, conn db.Connection, cypher string, params map[string]any) (context.Context, tracing.Span) {
+	database := db.DefaultDatabase
+	if dbSelector, ok := conn.(db.DatabaseSelector); ok {
+		database = dbSelector.Database()
+	}
+	var span tracing.Span
+	if t.tracer == nil {
+		ctx, span = itracing.Start(ctx, nil, tracing.QueryOperation, nil)
+	} else {
+		attributes := map[string]any{
+			tracing.QueryTextAttribute:     cypher,
+			tracing.ServerAddressAttribute: conn.ServerName(),
+		}
+		if database != db.DefaultDatabase {
+			attributes[tracing.DatabaseAttribute] = database
+		}
+		ctx, span = itracing.Start(itracing.WithParent(ctx, t.spanCtx), t.tracer, tracing.QueryOperation, attributes)
+	}
+	span = t.stats.measureQuery(span, t.api, database)
+	if t.slowQueries != nil {
+		span = t.slowQueries.watchQuery(span, config.SlowQuery{
+			Query:                 cypher,
+			Parameters:            sanitizeParameters(params),
+			TransactionMetadata:   t.metadata,
+			Database:              database,
+			Server:                conn.ServerName(),
+			ConnectionAcquisition: t.acquisition,
+		})
+		t.acquisition = 0
+	}
+	return ctx, span
+}
+
 // Transaction implementation when explicit transaction started
 type explicitTransaction struct {
 	conn      db.Connection
@@ -77,14 +170,18 @@ func (tx *explicitTransaction) Run(ctx context.Context, cypher string, params ma
 	if tx.conn == nil {
 		return nil, transactionAlreadyCompletedError()
 	}
+	ctx, span := tx.txState.startQuery(ctx, tx.conn, cypher, params)
 	stream, err := tx.conn.RunTx(ctx, tx.txHandle, db.Command{Cypher: cypher, Params: params, FetchSize: tx.fetchSize})
 	if err != nil {
+		span.End(nil, err)
 		tx.txState.onError(err)
 		return nil, errorutil.WrapError(tx.txState.err)
 	}
 	// no result consumption hook here since bookmarks are sent after commit, not after pulling results
 	result := newResultWithContext(tx.conn, stream, cypher, params, tx.txState, nil)
+	result.trackQuery(span)
 	tx.txState.resultErrorHandlers = append(tx.txState.resultErrorHandlers, result.errorHandler)
+	tx.txState.results = append(tx.txState.results, result)
 	return result, nil
 }
 
@@ -95,6 +192,12 @@ func (tx *explicitTransaction) Commit(ctx context.Context) error {
 	if tx.conn == nil {
 		return transactionAlreadyCompletedError()
 	}
+	if err := tx.txState.consumeResults(ctx); err != nil {
+		// no commit, the pool resets the connection which rolls back the transaction
+		tx.txState.err = err
+		tx.onClosed()
+		return errorutil.WrapError(err)
+	}
 	tx.txState.err = tx.conn.TxCommit(ctx, tx.txHandle)
 	tx.onClosed()
 	return errorutil.WrapError(tx.txState.err)
@@ -140,12 +243,17 @@ type managedTransaction struct {
 }
 
 func (tx *managedTransaction) Run(ctx context.Context, cypher string, params map[string]any) (ResultWithContext, error) {
+	ctx, span := tx.txState.startQuery(ctx, tx.conn, cypher, params)
 	stream, err := tx.conn.RunTx(ctx, tx.txHandle, db.Command{Cypher: cypher, Params: params, FetchSize: tx.fetchSize})
 	if err != nil {
+		span.End(nil, err)
 		return nil, errorutil.WrapError(err)
 	}
 	// no result consumption hook here since bookmarks are sent after commit, not after pulling results
-	return newResultWithContext(tx.conn, stream, cypher, params, tx.txState, nil), nil
+	result := newResultWithContext(tx.conn, stream, cypher, params, tx.txState, nil)
+	result.trackQuery(span)
+	tx.txState.results = append(tx.txState.results, result)
+	return result, nil
 }
 
 // legacy interop only - remove in 6.0
@@ -190,6 +298,8 @@ func (tx *autocommitTransaction) done(ctx context.Context) {
 func (tx *autocommitTransaction) discard(ctx context.Context) {
 	if !tx.closed {
 		tx.res.Consume(ctx)
+		// the result is consumed, buffering only detaches it from the connection
+		tx.res.buffer(ctx)
 		tx.closed = true
 		tx.onClosed()
 	}